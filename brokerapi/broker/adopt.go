@@ -0,0 +1,44 @@
+package broker
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
+)
+
+// Adopt creates a new instance of a service from existing resources rather than by creating them.
+// The request is validated in the same way as a provision, and the resources, given as resource addresses
+// in the provision template mapped to IaaS resource IDs, are imported. Adopt waits for the import, and the
+// instance is only registered once the resources have been verified and applied. Otherwise the workspace
+// is removed, so that the broker keeps no state of resources that it does not own.
+// It is not part of the OSB API, and is used by the "adopt" command.
+func (broker *ServiceBroker) Adopt(ctx context.Context, instanceID string, details domain.ProvisionDetails, resources map[string]string) error {
+	broker.Logger.Info("Adopting", correlation.ID(ctx), lager.Data{
+		"instanceId": instanceID,
+		"details":    details,
+		"resources":  resources,
+	})
+
+	req, err := broker.prepareProvision(ctx, instanceID, details)
+	if err != nil {
+		return err
+	}
+	defer req.release()
+
+	if err := req.provider.Adopt(ctx, req.vars, resources); err != nil {
+		if deleteErr := req.provider.DeleteInstanceData(ctx, instanceID); deleteErr != nil {
+			broker.Logger.Error("adopt-cleanup", deleteErr)
+		}
+		return err
+	}
+
+	if err := broker.storeProvisionedInstance(instanceID, req); err != nil {
+		return err
+	}
+
+	return broker.updateStateOnOperationCompletion(ctx, req.provider, models.ProvisionOperationType, instanceID)
+}
@@ -0,0 +1,146 @@
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"code.cloudfoundry.org/lager/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+var _ = Describe("Adopt", func() {
+	const (
+		spaceID    = "test-space-id"
+		orgID      = "test-org-id"
+		planID     = "test-plan-id"
+		offeringID = "test-service-id"
+		instanceID = "test-instance-id"
+	)
+
+	var (
+		serviceBroker       *broker.ServiceBroker
+		adoptDetails        domain.ProvisionDetails
+		resources           map[string]string
+		fakeStorage         *brokerfakes.FakeStorage
+		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+	)
+
+	BeforeEach(func() {
+		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+
+		brokerConfig := &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:      offeringID,
+					Name:    "test-service",
+					Version: "1.2.3",
+					Plans: []pkgBroker.ServicePlan{
+						{
+							ServicePlan:       domain.ServicePlan{ID: planID, Name: "test-plan"},
+							ServiceProperties: map[string]any{"plan-defined-key": "plan-defined-value"},
+						},
+					},
+					ProvisionInputVariables: []pkgBroker.BrokerVariable{
+						{FieldName: "foo", Type: "string", Details: "fake field name"},
+					},
+					ProvisionComputedVariables: []varcontext.DefaultVariable{
+						{Name: "tf_id", Default: "tf:${request.instance_id}:", Overwrite: true},
+					},
+					ProviderBuilder: func(lager.Logger, pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+						return fakeServiceProvider
+					},
+				},
+			},
+		}
+
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceInstanceDetailsReturns(false, nil)
+
+		serviceBroker = must(broker.New(brokerConfig, fakeStorage, utils.NewLogger("brokers-test")))
+
+		adoptDetails = domain.ProvisionDetails{
+			ServiceID:        offeringID,
+			PlanID:           planID,
+			SpaceGUID:        spaceID,
+			OrganizationGUID: orgID,
+			RawParameters:    json.RawMessage(`{"foo":"bar"}`),
+		}
+		resources = map[string]string{"random_string.name": "existing-name"}
+	})
+
+	It("adopts the resources and registers the instance", func() {
+		fakeServiceProvider.GetTerraformOutputsReturns(storage.JSONObject{"name": "existing-name"}, nil)
+		fakeStorage.GetServiceInstanceDetailsCalls(func(string) (storage.ServiceInstanceDetails, error) {
+			return fakeStorage.StoreServiceInstanceDetailsArgsForCall(fakeStorage.StoreServiceInstanceDetailsCallCount() - 1), nil
+		})
+
+		Expect(serviceBroker.Adopt(context.TODO(), instanceID, adoptDetails, resources)).To(Succeed())
+
+		By("checking the provider adopted the resources with the provision variables")
+		Expect(fakeServiceProvider.ProvisionCallCount()).To(BeZero())
+		Expect(fakeServiceProvider.AdoptCallCount()).To(Equal(1))
+		_, vars, actualResources := fakeServiceProvider.AdoptArgsForCall(0)
+		Expect(vars.GetString("tf_id")).To(Equal("tf:test-instance-id:"))
+		Expect(vars.GetString("foo")).To(Equal("bar"))
+		Expect(vars.GetString("plan-defined-key")).To(Equal("plan-defined-value"))
+		Expect(actualResources).To(Equal(resources))
+
+		By("checking the instance was stored with the outputs")
+		Expect(fakeStorage.StoreServiceInstanceDetailsCallCount()).To(Equal(2))
+		Expect(fakeStorage.StoreServiceInstanceDetailsArgsForCall(1)).To(Equal(storage.ServiceInstanceDetails{
+			GUID:              instanceID,
+			ServiceGUID:       offeringID,
+			PlanGUID:          planID,
+			SpaceGUID:         spaceID,
+			OrganizationGUID:  orgID,
+			DefinitionVersion: "1.2.3",
+			Outputs:           storage.JSONObject{"name": "existing-name"},
+		}))
+		Expect(fakeStorage.StoreProvisionRequestDetailsCallCount()).To(Equal(1))
+		actualInstanceID, actualParams := fakeStorage.StoreProvisionRequestDetailsArgsForCall(0)
+		Expect(actualInstanceID).To(Equal(instanceID))
+		Expect(actualParams).To(Equal(storage.JSONObject{"foo": "bar"}))
+		Expect(fakeServiceProvider.ClearOperationTypeCallCount()).To(Equal(1))
+		Expect(fakeServiceProvider.DeleteInstanceDataCallCount()).To(BeZero())
+	})
+
+	It("fails when the instance already exists", func() {
+		fakeStorage.ExistsServiceInstanceDetailsReturns(true, nil)
+
+		err := serviceBroker.Adopt(context.TODO(), instanceID, adoptDetails, resources)
+		Expect(err).To(MatchError(apiresponses.ErrInstanceAlreadyExists))
+		Expect(fakeServiceProvider.AdoptCallCount()).To(BeZero())
+	})
+
+	It("fails when the parameters are not valid", func() {
+		adoptDetails.RawParameters = json.RawMessage(`{"foo":1}`)
+
+		err := serviceBroker.Adopt(context.TODO(), instanceID, adoptDetails, resources)
+		Expect(err).To(HaveOccurred())
+		Expect(fakeServiceProvider.AdoptCallCount()).To(BeZero())
+	})
+
+	It("does not register the instance and removes the workspace when the adopt fails", func() {
+		fakeServiceProvider.AdoptReturns(errors.New("the imported resources do not match the provision template"))
+
+		err := serviceBroker.Adopt(context.TODO(), instanceID, adoptDetails, resources)
+		Expect(err).To(MatchError("the imported resources do not match the provision template"))
+		Expect(fakeStorage.StoreServiceInstanceDetailsCallCount()).To(BeZero())
+		Expect(fakeStorage.StoreProvisionRequestDetailsCallCount()).To(BeZero())
+		Expect(fakeServiceProvider.DeleteInstanceDataCallCount()).To(Equal(1))
+		_, actualInstanceID := fakeServiceProvider.DeleteInstanceDataArgsForCall(0)
+		Expect(actualInstanceID).To(Equal(instanceID))
+	})
+})
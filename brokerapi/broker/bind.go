@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
 	"code.cloudfoundry.org/lager/v3"
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
@@ -18,11 +19,14 @@ import (
 
 // Bind creates an account with credentials to access an instance of a service.
 // It is bound to the `PUT /v2/service_instances/:instance_id/service_bindings/:binding_id` endpoint and can be called using the `cf bind-service` command.
-func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, _ bool) (domain.Binding, error) {
+// When asynchronous bindings are enabled and the platform allows it, the bind runs in the background and
+// the credentials are stored once LastBindingOperation observes that it has completed.
+func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID string, details domain.BindDetails, asyncAllowed bool) (domain.Binding, error) {
 	broker.Logger.Info("Binding", correlation.ID(ctx), lager.Data{
-		"instance_id": instanceID,
-		"binding_id":  bindingID,
-		"details":     details,
+		"instance_id":        instanceID,
+		"binding_id":         bindingID,
+		"accepts_incomplete": asyncAllowed,
+		"details":            details,
 	})
 
 	// check for existing binding
@@ -34,16 +38,41 @@ func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID str
 		return domain.Binding{}, apiresponses.ErrBindingAlreadyExists
 	}
 
+	// check for an asynchronous bind that has not completed yet
+	pending, err := broker.bindingPending(bindingID, instanceID)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error checking for pending binding: %w", err)
+	}
+
 	// get existing service instance details
 	instanceRecord, err := broker.store.GetServiceInstanceDetails(instanceID)
 	if err != nil {
 		return domain.Binding{}, fmt.Errorf("error retrieving service instance details: %w", err)
 	}
 
-	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(instanceRecord.ServiceGUID)
+	serviceDefinition, serviceProvider, release, err := broker.getInstanceDefinitionAndProvider(instanceRecord)
 	if err != nil {
 		return domain.Binding{}, fmt.Errorf("error retrieving service definition: %w", err)
 	}
+	defer release()
+
+	if pending {
+		// A failed bind may have created some resources, which are only destroyed by an unbind.
+		// Binding again would replace the workspace that tracks them.
+		_, _, lastOperationType, err := serviceProvider.PollBinding(ctx, instanceID, bindingID)
+		switch {
+		case err != nil && lastOperationType == models.BindOperationType:
+			return domain.Binding{}, ErrBindingFailed
+		case asyncAllowed:
+			return domain.Binding{IsAsync: true, OperationData: generateTFBindingID(instanceID, bindingID)}, nil
+		default:
+			return domain.Binding{}, ErrConcurrencyError
+		}
+	}
+
+	if instanceRecord.Released {
+		return domain.Binding{}, ErrInstanceReleased
+	}
 
 	err = serviceProvider.CheckUpgradeAvailable(generateTFInstanceID(instanceID))
 	if err != nil {
@@ -73,6 +102,10 @@ func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID str
 		return domain.Binding{}, fmt.Errorf("error generating bind variables: %w", err)
 	}
 
+	if asyncAllowed && featureflags.Enabled(featureflags.AsyncBindingsEnabled) {
+		return broker.bindAsync(ctx, instanceID, bindingID, parsedDetails, vars, serviceProvider)
+	}
+
 	// create binding
 	credsDetails, err := serviceProvider.Bind(ctx, vars)
 	if err != nil {
@@ -111,6 +144,35 @@ func (broker *ServiceBroker) Bind(ctx context.Context, instanceID, bindingID str
 	return binding, nil
 }
 
+// bindAsync stores the request details up front so that the binding can be tracked, polled and unbound
+// while the provider creates it in the background.
+func (broker *ServiceBroker) bindAsync(ctx context.Context, instanceID, bindingID string, parsedDetails paramparser.BindDetails, vars *varcontext.VarContext, serviceProvider broker.ServiceProvider) (domain.Binding, error) {
+	if err := broker.store.StoreBindRequestDetails(bindingID, instanceID, parsedDetails.RequestBindResource, parsedDetails.RequestParams); err != nil {
+		return domain.Binding{}, fmt.Errorf("error saving bind request details to database: %w", err)
+	}
+
+	if err := serviceProvider.BindAsync(ctx, vars); err != nil {
+		if deleteErr := broker.store.DeleteBindRequestDetails(bindingID, instanceID); deleteErr != nil {
+			broker.Logger.Error("bind-async-cleanup", deleteErr)
+		}
+		return domain.Binding{}, fmt.Errorf("error performing bind: %w", err)
+	}
+
+	return domain.Binding{
+		IsAsync:       true,
+		OperationData: generateTFBindingID(instanceID, bindingID),
+	}, nil
+}
+
+// bindingPending determines whether an asynchronous bind has been accepted for which no credentials have been stored yet
+func (broker *ServiceBroker) bindingPending(bindingID, instanceID string) (bool, error) {
+	storedBindRequestDetails, err := broker.store.GetBindRequestDetails(bindingID, instanceID)
+	if err != nil {
+		return false, err
+	}
+	return storedBindRequestDetails.ServiceBindingGUID != "", nil
+}
+
 func validateBindParameters(params map[string]any, validUserInputFields []broker.BrokerVariable) error {
 	if len(params) == 0 {
 		return nil
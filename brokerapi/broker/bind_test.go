@@ -14,13 +14,16 @@ import (
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
 	pkgBroker "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/featureflags"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 )
 
 var _ = Describe("Bind", func() {
@@ -202,6 +205,147 @@ var _ = Describe("Bind", func() {
 
 	})
 
+	Describe("asynchronous bind", func() {
+		BeforeEach(func() {
+			viper.Set(string(featureflags.AsyncBindingsEnabled), true)
+		})
+
+		AfterEach(func() {
+			viper.Reset()
+		})
+
+		It("starts the bind and stores the request details", func() {
+			response, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("validating response")
+			Expect(response).To(Equal(domain.Binding{
+				IsAsync:       true,
+				OperationData: "tf:test-instance-id:test-binding-id",
+			}))
+
+			By("validating provider async bind has been called")
+			Expect(fakeServiceProvider.BindAsyncCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.BindCallCount()).To(BeZero())
+
+			By("validating storage is asked to store bind request details")
+			Expect(fakeStorage.StoreBindRequestDetailsCallCount()).To(Equal(1))
+			actualBindingID, actualInstanceID, _, actualParams := fakeStorage.StoreBindRequestDetailsArgsForCall(0)
+			Expect(actualBindingID).To(Equal(bindingID))
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualParams).To(Equal(storage.JSONObject{"bind_field_1": "bind_value_1"}))
+
+			By("validating credentials are not stored yet")
+			Expect(fakeCredStore.SaveCallCount()).To(BeZero())
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+		})
+
+		When("the platform does not accept incomplete operations", func() {
+			It("binds synchronously", func() {
+				response, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.IsAsync).To(BeFalse())
+				Expect(fakeServiceProvider.BindCallCount()).To(Equal(1))
+				Expect(fakeServiceProvider.BindAsyncCallCount()).To(BeZero())
+			})
+		})
+
+		When("the bind is already in progress", func() {
+			BeforeEach(func() {
+				fakeStorage.GetBindRequestDetailsReturns(storage.BindRequestDetails{ServiceInstanceGUID: instanceID, ServiceBindingGUID: bindingID}, nil)
+			})
+
+			It("returns the pending operation", func() {
+				response, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response).To(Equal(domain.Binding{
+					IsAsync:       true,
+					OperationData: "tf:test-instance-id:test-binding-id",
+				}))
+				Expect(fakeServiceProvider.BindAsyncCallCount()).To(BeZero())
+			})
+
+			It("returns a concurrency error if incomplete operations are not accepted", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, false)
+				Expect(err).To(MatchError(broker.ErrConcurrencyError))
+			})
+		})
+
+		When("a bind is retried after it failed", func() {
+			BeforeEach(func() {
+				stored := map[string]storage.BindRequestDetails{}
+				fakeStorage.StoreBindRequestDetailsCalls(func(bindingID, instanceID string, bindResource, _ storage.JSONObject) error {
+					stored[bindingID] = storage.BindRequestDetails{ServiceInstanceGUID: instanceID, ServiceBindingGUID: bindingID, BindResource: bindResource}
+					return nil
+				})
+				fakeStorage.GetBindRequestDetailsCalls(func(bindingID, _ string) (storage.BindRequestDetails, error) {
+					return stored[bindingID], nil
+				})
+				fakeStorage.DeleteBindRequestDetailsCalls(func(bindingID, _ string) error {
+					delete(stored, bindingID)
+					return nil
+				})
+				fakeServiceProvider.PollBindingReturns(true, "", models.BindOperationType, errors.New("apply failed"))
+			})
+
+			It("refuses to bind again until the failed bind has been unbound", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+
+				lastOperation, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(lastOperation.State).To(Equal(domain.Failed))
+
+				_, err = serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).To(MatchError(broker.ErrBindingFailed))
+				Expect(fakeServiceProvider.BindAsyncCallCount()).To(Equal(1))
+
+				By("unbinding to destroy what the failed bind created")
+				unbindDetails := domain.UnbindDetails{PlanID: planID, ServiceID: serviceID}
+				unbindResponse, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(unbindResponse.IsAsync).To(BeTrue())
+				Expect(fakeServiceProvider.UnbindAsyncCallCount()).To(Equal(1))
+				_, actualInstanceID, actualBindingID, _ := fakeServiceProvider.UnbindAsyncArgsForCall(0)
+				Expect(actualInstanceID).To(Equal(instanceID))
+				Expect(actualBindingID).To(Equal(bindingID))
+
+				fakeServiceProvider.PollBindingReturns(true, "", models.UnbindOperationType, nil)
+				lastOperation, err = serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, domain.PollDetails{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(lastOperation.State).To(Equal(domain.Succeeded))
+				Expect(fakeServiceProvider.DeleteBindingDataCallCount()).To(Equal(1))
+
+				By("binding again")
+				response, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.IsAsync).To(BeTrue())
+				Expect(fakeServiceProvider.BindAsyncCallCount()).To(Equal(2))
+			})
+
+			It("refuses a synchronous bind too", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, false)
+				Expect(err).To(MatchError(broker.ErrBindingFailed))
+				Expect(fakeServiceProvider.BindCallCount()).To(BeZero())
+			})
+		})
+
+		When("provider async bind fails", func() {
+			BeforeEach(func() {
+				fakeServiceProvider.BindAsyncReturns(errors.New("bind error"))
+			})
+
+			It("should error and remove the bind request details", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, true)
+				Expect(err).To(MatchError("error performing bind: bind error"))
+				Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(Equal(1))
+			})
+		})
+	})
+
 	Describe("unsuccessful bind", func() {
 		When("error reading binding credentials", func() {
 			BeforeEach(func() {
@@ -227,6 +371,24 @@ var _ = Describe("Bind", func() {
 			})
 		})
 
+		When("the instance has been released", func() {
+			BeforeEach(func() {
+				fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+					GUID:        instanceID,
+					ServiceGUID: offeringID,
+					PlanGUID:    planID,
+					Released:    true,
+				}, nil)
+			})
+
+			It("should error", func() {
+				_, err := serviceBroker.Bind(context.TODO(), instanceID, bindingID, bindDetails, false)
+
+				Expect(err).To(MatchError(broker.ErrInstanceReleased))
+				Expect(fakeServiceProvider.BindCallCount()).To(BeZero())
+			})
+		})
+
 		When("error retrieving service instance details", func() {
 			BeforeEach(func() {
 				fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{}, fmt.Errorf("error"))
@@ -16,9 +16,12 @@
 package broker
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
@@ -27,7 +30,7 @@ import (
 
 // ServiceBroker is a brokerapi.ServiceBroker that can be used to generate an OSB compatible service broker.
 type ServiceBroker struct {
-	registry  broker.BrokerRegistry
+	registry  atomic.Pointer[registrySnapshot]
 	credStore CredStore
 
 	store  Storage
@@ -39,12 +42,102 @@ type TFDeploymentGUID string
 // New creates a ServiceBroker.
 // Exactly one of ServiceBroker or error will be nil when returned.
 func New(cfg *BrokerConfig, store Storage, logger lager.Logger) (*ServiceBroker, error) {
-	return &ServiceBroker{
-		registry:  cfg.Registry,
+	serviceBroker := &ServiceBroker{
 		credStore: cfg.CredStore,
 		Logger:    logger,
 		store:     store,
-	}, nil
+	}
+	serviceBroker.registry.Store(newRegistrySnapshot(cfg.Registry))
+
+	return serviceBroker, nil
+}
+
+// Registry returns the service definitions currently served by the broker.
+func (broker *ServiceBroker) Registry() broker.BrokerRegistry {
+	return broker.registry.Load().registry
+}
+
+// SwapRegistry atomically replaces the service definitions served by the broker.
+// Operations that are already in flight keep the service definitions that they started with.
+// The registry is validated first, and the current registry is kept if it is not valid.
+// The returned channel is closed once no request is using the previous registry any more.
+func (broker *ServiceBroker) SwapRegistry(registry broker.BrokerRegistry) (<-chan struct{}, error) {
+	if err := registry.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid registry: %w", err)
+	}
+
+	enabledServices, err := registry.GetEnabledServices()
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("invalid registry: %w", err)
+	case len(enabledServices) == 0:
+		return nil, errors.New("invalid registry: no services are defined")
+	}
+
+	previous := broker.registry.Swap(newRegistrySnapshot(registry))
+	return previous.replace(), nil
+}
+
+// acquireRegistry returns the current registry, which is not reported as released by SwapRegistry
+// until the returned function has been called
+func (broker *ServiceBroker) acquireRegistry() (broker.BrokerRegistry, func()) {
+	for {
+		snapshot := broker.registry.Load()
+		if snapshot.acquire() {
+			return snapshot.registry, sync.OnceFunc(snapshot.release)
+		}
+	}
+}
+
+// registrySnapshot counts the requests that are using a registry, so that the binaries
+// extracted for it are only removed once it has been replaced and the requests have finished
+type registrySnapshot struct {
+	registry broker.BrokerRegistry
+
+	mu       sync.Mutex
+	users    int
+	replaced bool
+	released chan struct{}
+}
+
+func newRegistrySnapshot(registry broker.BrokerRegistry) *registrySnapshot {
+	return &registrySnapshot{
+		registry: registry,
+		released: make(chan struct{}),
+	}
+}
+
+// acquire fails if the snapshot has been replaced, in which case the current snapshot should be used
+func (s *registrySnapshot) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.replaced {
+		return false
+	}
+	s.users++
+	return true
+}
+
+func (s *registrySnapshot) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users--
+	if s.replaced && s.users == 0 {
+		close(s.released)
+	}
+}
+
+func (s *registrySnapshot) replace() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replaced = true
+	if s.users == 0 {
+		close(s.released)
+	}
+	return s.released
 }
 
 func validateProvisionParameters(params map[string]any, validUserInputFields []broker.BrokerVariable, validImportFields []broker.ImportVariable, plan *broker.ServicePlan) error {
@@ -21,6 +21,18 @@ type FakeCredStore struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ReferenceStub        func(string, any) any
+	referenceMutex       sync.RWMutex
+	referenceArgsForCall []struct {
+		arg1 string
+		arg2 any
+	}
+	referenceReturns struct {
+		result1 any
+	}
+	referenceReturnsOnCall map[int]struct {
+		result1 any
+	}
 	SaveStub        func(context.Context, string, any, string) (any, error)
 	saveMutex       sync.RWMutex
 	saveArgsForCall []struct {
@@ -103,6 +115,68 @@ func (fake *FakeCredStore) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeCredStore) Reference(arg1 string, arg2 any) any {
+	fake.referenceMutex.Lock()
+	ret, specificReturn := fake.referenceReturnsOnCall[len(fake.referenceArgsForCall)]
+	fake.referenceArgsForCall = append(fake.referenceArgsForCall, struct {
+		arg1 string
+		arg2 any
+	}{arg1, arg2})
+	stub := fake.ReferenceStub
+	fakeReturns := fake.referenceReturns
+	fake.recordInvocation("Reference", []interface{}{arg1, arg2})
+	fake.referenceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCredStore) ReferenceCallCount() int {
+	fake.referenceMutex.RLock()
+	defer fake.referenceMutex.RUnlock()
+	return len(fake.referenceArgsForCall)
+}
+
+func (fake *FakeCredStore) ReferenceCalls(stub func(string, any) any) {
+	fake.referenceMutex.Lock()
+	defer fake.referenceMutex.Unlock()
+	fake.ReferenceStub = stub
+}
+
+func (fake *FakeCredStore) ReferenceArgsForCall(i int) (string, any) {
+	fake.referenceMutex.RLock()
+	defer fake.referenceMutex.RUnlock()
+	argsForCall := fake.referenceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCredStore) ReferenceReturns(result1 any) {
+	fake.referenceMutex.Lock()
+	defer fake.referenceMutex.Unlock()
+	fake.ReferenceStub = nil
+	fake.referenceReturns = struct {
+		result1 any
+	}{result1}
+}
+
+func (fake *FakeCredStore) ReferenceReturnsOnCall(i int, result1 any) {
+	fake.referenceMutex.Lock()
+	defer fake.referenceMutex.Unlock()
+	fake.ReferenceStub = nil
+	if fake.referenceReturnsOnCall == nil {
+		fake.referenceReturnsOnCall = make(map[int]struct {
+			result1 any
+		})
+	}
+	fake.referenceReturnsOnCall[i] = struct {
+		result1 any
+	}{result1}
+}
+
 func (fake *FakeCredStore) Save(arg1 context.Context, arg2 string, arg3 any, arg4 string) (any, error) {
 	fake.saveMutex.Lock()
 	ret, specificReturn := fake.saveReturnsOnCall[len(fake.saveArgsForCall)]
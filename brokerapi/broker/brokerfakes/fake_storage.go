@@ -9,6 +9,19 @@ import (
 )
 
 type FakeStorage struct {
+	CountServiceInstanceDetailsStub        func(storage.ServiceInstanceDetails) (int64, error)
+	countServiceInstanceDetailsMutex       sync.RWMutex
+	countServiceInstanceDetailsArgsForCall []struct {
+		arg1 storage.ServiceInstanceDetails
+	}
+	countServiceInstanceDetailsReturns struct {
+		result1 int64
+		result2 error
+	}
+	countServiceInstanceDetailsReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
 	CreateServiceBindingCredentialsStub        func(storage.ServiceBindingCredentials) error
 	createServiceBindingCredentialsMutex       sync.RWMutex
 	createServiceBindingCredentialsArgsForCall []struct {
@@ -208,6 +221,18 @@ type FakeStorage struct {
 	removeLockFileReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RetainTerraformDeploymentStub        func(string, string) error
+	retainTerraformDeploymentMutex       sync.RWMutex
+	retainTerraformDeploymentArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	retainTerraformDeploymentReturns struct {
+		result1 error
+	}
+	retainTerraformDeploymentReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StoreBindRequestDetailsStub        func(string, string, storage.JSONObject, storage.JSONObject) error
 	storeBindRequestDetailsMutex       sync.RWMutex
 	storeBindRequestDetailsArgsForCall []struct {
@@ -256,6 +281,30 @@ type FakeStorage struct {
 	storeTerraformDeploymentReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateBindRequestDetailsStub        func(string, string, storage.JSONObject) error
+	updateBindRequestDetailsMutex       sync.RWMutex
+	updateBindRequestDetailsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 storage.JSONObject
+	}
+	updateBindRequestDetailsReturns struct {
+		result1 error
+	}
+	updateBindRequestDetailsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateServiceBindingCredentialsStub        func(storage.ServiceBindingCredentials) error
+	updateServiceBindingCredentialsMutex       sync.RWMutex
+	updateServiceBindingCredentialsArgsForCall []struct {
+		arg1 storage.ServiceBindingCredentials
+	}
+	updateServiceBindingCredentialsReturns struct {
+		result1 error
+	}
+	updateServiceBindingCredentialsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	WriteLockFileStub        func(string) error
 	writeLockFileMutex       sync.RWMutex
 	writeLockFileArgsForCall []struct {
@@ -271,6 +320,70 @@ type FakeStorage struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeStorage) CountServiceInstanceDetails(arg1 storage.ServiceInstanceDetails) (int64, error) {
+	fake.countServiceInstanceDetailsMutex.Lock()
+	ret, specificReturn := fake.countServiceInstanceDetailsReturnsOnCall[len(fake.countServiceInstanceDetailsArgsForCall)]
+	fake.countServiceInstanceDetailsArgsForCall = append(fake.countServiceInstanceDetailsArgsForCall, struct {
+		arg1 storage.ServiceInstanceDetails
+	}{arg1})
+	stub := fake.CountServiceInstanceDetailsStub
+	fakeReturns := fake.countServiceInstanceDetailsReturns
+	fake.recordInvocation("CountServiceInstanceDetails", []interface{}{arg1})
+	fake.countServiceInstanceDetailsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorage) CountServiceInstanceDetailsCallCount() int {
+	fake.countServiceInstanceDetailsMutex.RLock()
+	defer fake.countServiceInstanceDetailsMutex.RUnlock()
+	return len(fake.countServiceInstanceDetailsArgsForCall)
+}
+
+func (fake *FakeStorage) CountServiceInstanceDetailsCalls(stub func(storage.ServiceInstanceDetails) (int64, error)) {
+	fake.countServiceInstanceDetailsMutex.Lock()
+	defer fake.countServiceInstanceDetailsMutex.Unlock()
+	fake.CountServiceInstanceDetailsStub = stub
+}
+
+func (fake *FakeStorage) CountServiceInstanceDetailsArgsForCall(i int) storage.ServiceInstanceDetails {
+	fake.countServiceInstanceDetailsMutex.RLock()
+	defer fake.countServiceInstanceDetailsMutex.RUnlock()
+	argsForCall := fake.countServiceInstanceDetailsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorage) CountServiceInstanceDetailsReturns(result1 int64, result2 error) {
+	fake.countServiceInstanceDetailsMutex.Lock()
+	defer fake.countServiceInstanceDetailsMutex.Unlock()
+	fake.CountServiceInstanceDetailsStub = nil
+	fake.countServiceInstanceDetailsReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorage) CountServiceInstanceDetailsReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.countServiceInstanceDetailsMutex.Lock()
+	defer fake.countServiceInstanceDetailsMutex.Unlock()
+	fake.CountServiceInstanceDetailsStub = nil
+	if fake.countServiceInstanceDetailsReturnsOnCall == nil {
+		fake.countServiceInstanceDetailsReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.countServiceInstanceDetailsReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStorage) CreateServiceBindingCredentials(arg1 storage.ServiceBindingCredentials) error {
 	fake.createServiceBindingCredentialsMutex.Lock()
 	ret, specificReturn := fake.createServiceBindingCredentialsReturnsOnCall[len(fake.createServiceBindingCredentialsArgsForCall)]
@@ -1279,6 +1392,68 @@ func (fake *FakeStorage) RemoveLockFileReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeStorage) RetainTerraformDeployment(arg1 string, arg2 string) error {
+	fake.retainTerraformDeploymentMutex.Lock()
+	ret, specificReturn := fake.retainTerraformDeploymentReturnsOnCall[len(fake.retainTerraformDeploymentArgsForCall)]
+	fake.retainTerraformDeploymentArgsForCall = append(fake.retainTerraformDeploymentArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RetainTerraformDeploymentStub
+	fakeReturns := fake.retainTerraformDeploymentReturns
+	fake.recordInvocation("RetainTerraformDeployment", []interface{}{arg1, arg2})
+	fake.retainTerraformDeploymentMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) RetainTerraformDeploymentCallCount() int {
+	fake.retainTerraformDeploymentMutex.RLock()
+	defer fake.retainTerraformDeploymentMutex.RUnlock()
+	return len(fake.retainTerraformDeploymentArgsForCall)
+}
+
+func (fake *FakeStorage) RetainTerraformDeploymentCalls(stub func(string, string) error) {
+	fake.retainTerraformDeploymentMutex.Lock()
+	defer fake.retainTerraformDeploymentMutex.Unlock()
+	fake.RetainTerraformDeploymentStub = stub
+}
+
+func (fake *FakeStorage) RetainTerraformDeploymentArgsForCall(i int) (string, string) {
+	fake.retainTerraformDeploymentMutex.RLock()
+	defer fake.retainTerraformDeploymentMutex.RUnlock()
+	argsForCall := fake.retainTerraformDeploymentArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorage) RetainTerraformDeploymentReturns(result1 error) {
+	fake.retainTerraformDeploymentMutex.Lock()
+	defer fake.retainTerraformDeploymentMutex.Unlock()
+	fake.RetainTerraformDeploymentStub = nil
+	fake.retainTerraformDeploymentReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) RetainTerraformDeploymentReturnsOnCall(i int, result1 error) {
+	fake.retainTerraformDeploymentMutex.Lock()
+	defer fake.retainTerraformDeploymentMutex.Unlock()
+	fake.RetainTerraformDeploymentStub = nil
+	if fake.retainTerraformDeploymentReturnsOnCall == nil {
+		fake.retainTerraformDeploymentReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.retainTerraformDeploymentReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorage) StoreBindRequestDetails(arg1 string, arg2 string, arg3 storage.JSONObject, arg4 storage.JSONObject) error {
 	fake.storeBindRequestDetailsMutex.Lock()
 	ret, specificReturn := fake.storeBindRequestDetailsReturnsOnCall[len(fake.storeBindRequestDetailsArgsForCall)]
@@ -1527,6 +1702,130 @@ func (fake *FakeStorage) StoreTerraformDeploymentReturnsOnCall(i int, result1 er
 	}{result1}
 }
 
+func (fake *FakeStorage) UpdateBindRequestDetails(arg1 string, arg2 string, arg3 storage.JSONObject) error {
+	fake.updateBindRequestDetailsMutex.Lock()
+	ret, specificReturn := fake.updateBindRequestDetailsReturnsOnCall[len(fake.updateBindRequestDetailsArgsForCall)]
+	fake.updateBindRequestDetailsArgsForCall = append(fake.updateBindRequestDetailsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 storage.JSONObject
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateBindRequestDetailsStub
+	fakeReturns := fake.updateBindRequestDetailsReturns
+	fake.recordInvocation("UpdateBindRequestDetails", []interface{}{arg1, arg2, arg3})
+	fake.updateBindRequestDetailsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) UpdateBindRequestDetailsCallCount() int {
+	fake.updateBindRequestDetailsMutex.RLock()
+	defer fake.updateBindRequestDetailsMutex.RUnlock()
+	return len(fake.updateBindRequestDetailsArgsForCall)
+}
+
+func (fake *FakeStorage) UpdateBindRequestDetailsCalls(stub func(string, string, storage.JSONObject) error) {
+	fake.updateBindRequestDetailsMutex.Lock()
+	defer fake.updateBindRequestDetailsMutex.Unlock()
+	fake.UpdateBindRequestDetailsStub = stub
+}
+
+func (fake *FakeStorage) UpdateBindRequestDetailsArgsForCall(i int) (string, string, storage.JSONObject) {
+	fake.updateBindRequestDetailsMutex.RLock()
+	defer fake.updateBindRequestDetailsMutex.RUnlock()
+	argsForCall := fake.updateBindRequestDetailsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorage) UpdateBindRequestDetailsReturns(result1 error) {
+	fake.updateBindRequestDetailsMutex.Lock()
+	defer fake.updateBindRequestDetailsMutex.Unlock()
+	fake.UpdateBindRequestDetailsStub = nil
+	fake.updateBindRequestDetailsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) UpdateBindRequestDetailsReturnsOnCall(i int, result1 error) {
+	fake.updateBindRequestDetailsMutex.Lock()
+	defer fake.updateBindRequestDetailsMutex.Unlock()
+	fake.UpdateBindRequestDetailsStub = nil
+	if fake.updateBindRequestDetailsReturnsOnCall == nil {
+		fake.updateBindRequestDetailsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateBindRequestDetailsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) UpdateServiceBindingCredentials(arg1 storage.ServiceBindingCredentials) error {
+	fake.updateServiceBindingCredentialsMutex.Lock()
+	ret, specificReturn := fake.updateServiceBindingCredentialsReturnsOnCall[len(fake.updateServiceBindingCredentialsArgsForCall)]
+	fake.updateServiceBindingCredentialsArgsForCall = append(fake.updateServiceBindingCredentialsArgsForCall, struct {
+		arg1 storage.ServiceBindingCredentials
+	}{arg1})
+	stub := fake.UpdateServiceBindingCredentialsStub
+	fakeReturns := fake.updateServiceBindingCredentialsReturns
+	fake.recordInvocation("UpdateServiceBindingCredentials", []interface{}{arg1})
+	fake.updateServiceBindingCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorage) UpdateServiceBindingCredentialsCallCount() int {
+	fake.updateServiceBindingCredentialsMutex.RLock()
+	defer fake.updateServiceBindingCredentialsMutex.RUnlock()
+	return len(fake.updateServiceBindingCredentialsArgsForCall)
+}
+
+func (fake *FakeStorage) UpdateServiceBindingCredentialsCalls(stub func(storage.ServiceBindingCredentials) error) {
+	fake.updateServiceBindingCredentialsMutex.Lock()
+	defer fake.updateServiceBindingCredentialsMutex.Unlock()
+	fake.UpdateServiceBindingCredentialsStub = stub
+}
+
+func (fake *FakeStorage) UpdateServiceBindingCredentialsArgsForCall(i int) storage.ServiceBindingCredentials {
+	fake.updateServiceBindingCredentialsMutex.RLock()
+	defer fake.updateServiceBindingCredentialsMutex.RUnlock()
+	argsForCall := fake.updateServiceBindingCredentialsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorage) UpdateServiceBindingCredentialsReturns(result1 error) {
+	fake.updateServiceBindingCredentialsMutex.Lock()
+	defer fake.updateServiceBindingCredentialsMutex.Unlock()
+	fake.UpdateServiceBindingCredentialsStub = nil
+	fake.updateServiceBindingCredentialsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorage) UpdateServiceBindingCredentialsReturnsOnCall(i int, result1 error) {
+	fake.updateServiceBindingCredentialsMutex.Lock()
+	defer fake.updateServiceBindingCredentialsMutex.Unlock()
+	fake.UpdateServiceBindingCredentialsStub = nil
+	if fake.updateServiceBindingCredentialsReturnsOnCall == nil {
+		fake.updateServiceBindingCredentialsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateServiceBindingCredentialsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorage) WriteLockFile(arg1 string) error {
 	fake.writeLockFileMutex.Lock()
 	ret, specificReturn := fake.writeLockFileReturnsOnCall[len(fake.writeLockFileArgsForCall)]
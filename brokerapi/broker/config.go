@@ -23,11 +23,13 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/brokerpak"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/config"
+	"github.com/spf13/viper"
 )
 
 //counterfeiter:generate . CredStore
 type CredStore interface {
 	Save(ctx context.Context, path string, cred any, actor string) (any, error)
+	Reference(path string, cred any) any
 	Delete(ctx context.Context, path string) error
 }
 type BrokerConfig struct {
@@ -36,9 +38,9 @@ type BrokerConfig struct {
 }
 
 func NewBrokerConfigFromEnv(logger lager.Logger) (*BrokerConfig, error) {
-	registry := broker.BrokerRegistry{}
-	if err := brokerpak.RegisterAll(registry); err != nil {
-		return nil, fmt.Errorf("error loading brokerpaks: %v", err)
+	registry, err := NewRegistryFromEnv()
+	if err != nil {
+		return nil, err
 	}
 
 	envConfig, err := config.Parse()
@@ -62,12 +64,32 @@ func NewBrokerConfigFromEnv(logger lager.Logger) (*BrokerConfig, error) {
 	}, nil
 }
 
+// NewRegistryFromEnv fetches, validates and registers the brokerpaks in the brokerpak sources configuration
+func NewRegistryFromEnv() (broker.BrokerRegistry, error) {
+	return NewRegistryFromConfig(viper.GetViper())
+}
+
+// NewRegistryFromConfig fetches, validates and registers the brokerpaks in the brokerpak sources configuration
+// of a Viper instance. The services read their plans, defaults and quotas from the same instance.
+func NewRegistryFromConfig(v *viper.Viper) (broker.BrokerRegistry, error) {
+	registry := broker.BrokerRegistry{}
+	if err := brokerpak.RegisterAllFromConfig(v, registry); err != nil {
+		return nil, fmt.Errorf("error loading brokerpaks: %v", err)
+	}
+
+	return registry, nil
+}
+
 type NoopCredStore struct{}
 
 func (NoopCredStore) Save(ctx context.Context, path string, cred any, actor string) (any, error) {
 	return cred, nil
 }
 
+func (NoopCredStore) Reference(path string, cred any) any {
+	return cred
+}
+
 func (NoopCredStore) Delete(ctx context.Context, path string) error {
 	return nil
 }
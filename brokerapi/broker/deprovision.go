@@ -10,6 +10,7 @@ import (
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
@@ -44,10 +45,11 @@ func (broker *ServiceBroker) Deprovision(ctx context.Context, instanceID string,
 		return domain.DeprovisionServiceSpec{}, fmt.Errorf("database error getting existing instance: %s", err)
 	}
 
-	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(instance.ServiceGUID)
+	serviceDefinition, serviceProvider, release, err := broker.getInstanceDefinitionAndProvider(instance)
 	if err != nil {
 		return domain.DeprovisionServiceSpec{}, err
 	}
+	defer release()
 
 	deploymentID := generateTFInstanceID(instanceID)
 
@@ -55,6 +57,18 @@ func (broker *ServiceBroker) Deprovision(ctx context.Context, instanceID string,
 		return domain.DeprovisionServiceSpec{}, err
 	}
 
+	if retainsResources(instance, serviceDefinition) {
+		// The resources are not destroyed. The workspace is retained so that they can be imported again later.
+		broker.Logger.Info("deprovision-retain-resources", lager.Data{"released": instance.Released})
+		if err := broker.store.RetainTerraformDeployment(instanceID, deploymentID); err != nil {
+			return domain.DeprovisionServiceSpec{}, fmt.Errorf("error retaining workspace: %w", err)
+		}
+		if err := broker.removeServiceInstanceData(ctx, instanceID, serviceProvider); err != nil {
+			return domain.DeprovisionServiceSpec{}, err
+		}
+		return domain.DeprovisionServiceSpec{}, nil
+	}
+
 	err = serviceProvider.CheckUpgradeAvailable(deploymentID)
 	switch {
 	case errors.As(err, &workspace.CannotReadVersionError{}):
@@ -110,6 +124,16 @@ func (broker *ServiceBroker) Deprovision(ctx context.Context, instanceID string,
 	return response, nil
 }
 
+// retainsResources returns whether the resources of the instance and its bindings must be kept when they
+// are deleted, either because the instance was released or because its plan retains resources on delete
+func retainsResources(instance storage.ServiceInstanceDetails, serviceDefinition *broker.ServiceDefinition) bool {
+	if instance.Released {
+		return true
+	}
+	plan, err := serviceDefinition.GetPlanByID(instance.PlanGUID)
+	return err == nil && plan.RetainOnDelete
+}
+
 func (broker *ServiceBroker) removeServiceInstanceData(ctx context.Context, instanceID string, serviceProvider broker.ServiceProvider) error {
 	if err := broker.store.DeleteServiceInstanceDetails(instanceID); err != nil {
 		return fmt.Errorf("error deleting instance details from database: %s. WARNING: this instance will remain visible in cf. Contact your operator for cleanup", err)
@@ -113,6 +113,64 @@ var _ = Describe("Deprovision", func() {
 		Expect(actualSIDetails.GUID).To(Equal(instanceToDeleteID))
 	})
 
+	When("the instance has been released", func() {
+		BeforeEach(func() {
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+				ServiceGUID: offeringID,
+				PlanGUID:    planID,
+				GUID:        instanceToDeleteID,
+				Released:    true,
+			}, nil)
+		})
+
+		It("retains the workspace and removes the instance without destroying the resources", func() {
+			response, err := serviceBroker.Deprovision(context.TODO(), instanceToDeleteID, deprovisionDetails, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.IsAsync).To(BeFalse())
+
+			Expect(fakeServiceProvider.DeprovisionCallCount()).To(BeZero())
+			Expect(fakeStorage.RetainTerraformDeploymentCallCount()).To(Equal(1))
+			actualInstanceID, actualDeploymentID := fakeStorage.RetainTerraformDeploymentArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceToDeleteID))
+			Expect(actualDeploymentID).To(Equal("tf:test-instance-id:"))
+			Expect(fakeStorage.DeleteServiceInstanceDetailsCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteServiceInstanceDetailsArgsForCall(0)).To(Equal(instanceToDeleteID))
+			Expect(fakeStorage.DeleteProvisionRequestDetailsCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.DeleteInstanceDataCallCount()).To(Equal(1))
+		})
+
+		When("the workspace cannot be retained", func() {
+			BeforeEach(func() {
+				fakeStorage.RetainTerraformDeploymentReturns(errors.New("boom"))
+			})
+
+			It("should error and keep the instance", func() {
+				_, err := serviceBroker.Deprovision(context.TODO(), instanceToDeleteID, deprovisionDetails, true)
+				Expect(err).To(MatchError("error retaining workspace: boom"))
+
+				Expect(fakeStorage.DeleteServiceInstanceDetailsCallCount()).To(BeZero())
+				Expect(fakeServiceProvider.DeleteInstanceDataCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	When("the plan retains resources on delete", func() {
+		BeforeEach(func() {
+			serviceBroker.Registry()["test-service"].Plans[0].RetainOnDelete = true
+		})
+
+		It("retains the workspace and removes the instance without destroying the resources", func() {
+			response, err := serviceBroker.Deprovision(context.TODO(), instanceToDeleteID, deprovisionDetails, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.IsAsync).To(BeFalse())
+
+			Expect(fakeServiceProvider.DeprovisionCallCount()).To(BeZero())
+			Expect(fakeStorage.RetainTerraformDeploymentCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteServiceInstanceDetailsCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.DeleteInstanceDataCallCount()).To(Equal(1))
+		})
+	})
+
 	When("provider deprovision errors", func() {
 		BeforeEach(func() {
 			fakeServiceProvider.DeprovisionReturns(nil, errors.New("cannot deprovision right now"))
@@ -15,16 +15,23 @@ var (
 	nonUpdateableParameterMsg = "attempt to update parameter that may result in service instance re-creation and data loss"
 	notFoundMsg               = "not found"
 	concurrencyErrorMsg       = "ConcurrencyError"
+	instanceReleasedMsg       = "the service instance has been released to be managed outside the broker, it can only be deleted"
+	bindingFailedMsg          = "a previous bind with this binding ID failed, unbind it to clean up before binding again"
 
 	badRequestKey            = "bad-request"
 	invalidUserInputKey      = "parsing-user-request"
 	nonUpdatableParameterKey = "prohibited"
 	notFoundKey              = "not-found"
 	concurrencyErrorKey      = "concurrency-error"
+	quotaExceededKey         = "quota-exceeded"
+	instanceReleasedKey      = "instance-released"
+	bindingFailedKey         = "binding-failed"
 
 	ErrBadRequest            = apiresponses.NewFailureResponse(errors.New(badRequestMsg), http.StatusBadRequest, badRequestKey)
 	ErrInvalidUserInput      = apiresponses.NewFailureResponse(errors.New(invalidUserInputMsg), http.StatusBadRequest, invalidUserInputKey)
 	ErrNonUpdatableParameter = apiresponses.NewFailureResponse(errors.New(nonUpdateableParameterMsg), http.StatusBadRequest, nonUpdatableParameterKey)
 	ErrNotFound              = apiresponses.NewFailureResponse(errors.New(notFoundMsg), http.StatusNotFound, notFoundKey)
 	ErrConcurrencyError      = apiresponses.NewFailureResponse(errors.New(concurrencyErrorMsg), http.StatusUnprocessableEntity, concurrencyErrorKey)
+	ErrInstanceReleased      = apiresponses.NewFailureResponse(errors.New(instanceReleasedMsg), http.StatusUnprocessableEntity, instanceReleasedKey)
+	ErrBindingFailed         = apiresponses.NewFailureResponse(errors.New(bindingFailedMsg), http.StatusConflict, bindingFailedKey)
 )
@@ -44,17 +44,18 @@ func (broker *ServiceBroker) GetBinding(ctx context.Context, instanceID, binding
 	}
 
 	// check whether service plan is bindable
-	serviceDefinition, _, err := broker.getDefinitionAndProvider(instanceRecord.ServiceGUID)
+	serviceDefinition, _, release, err := broker.getInstanceDefinitionAndProvider(instanceRecord)
 	if err != nil {
 		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving service definition: %w", err)
 	}
+	defer release()
 	if !serviceDefinition.Bindable {
 		return domain.GetBindingSpec{}, ErrBadRequest
 	}
 
 	// check whether binding exists
-	//	 with the current implementation, bind is a synchroneous operation which waits for all resources to be created before binding credentials are stored
-	//   therefore, we can assume the binding operation is completed if it exists at the store
+	//   credentials are only stored once the bind operation has completed, so a binding
+	//   that is still being created asynchronously is reported as not found
 	bindingExists, err := broker.store.ExistsServiceBindingCredentials(bindingID, instanceID)
 	if err != nil {
 		return domain.GetBindingSpec{}, fmt.Errorf("error checking for existing binding: %w", err)
@@ -69,11 +70,20 @@ func (broker *ServiceBroker) GetBinding(ctx context.Context, instanceID, binding
 		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving bind request details: %w", err)
 	}
 
+	storedCreds, err := broker.store.GetServiceBindingCredentials(bindingID, instanceID)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error retrieving binding credentials: %w", err)
+	}
+
+	binding, err := buildInstanceCredentials(storedCreds.Credentials, instanceRecord.Outputs)
+	if err != nil {
+		return domain.GetBindingSpec{}, fmt.Errorf("error building credentials: %w", err)
+	}
+
 	// broker does not support Log Drain, Route Services, or Volume Mounts
 	// broker does not support binding metadata
-	// credentials are returned with synchronous bind request
 	return domain.GetBindingSpec{
-		Credentials:     nil,
+		Credentials:     broker.credStore.Reference(computeCredHubPath(broker.getServiceName(serviceDefinition), bindingID), binding.Credentials),
 		SyslogDrainURL:  "",
 		RouteServiceURL: "",
 		VolumeMounts:    nil,
@@ -34,6 +34,7 @@ var _ = Describe("GetBinding", func() {
 
 		fakeStorage         *brokerfakes.FakeStorage
 		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+		fakeCredStore       *brokerfakes.FakeCredStore
 
 		brokerConfig *broker.BrokerConfig
 
@@ -43,6 +44,8 @@ var _ = Describe("GetBinding", func() {
 	BeforeEach(func() {
 		fakeStorage = &brokerfakes.FakeStorage{}
 		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+		fakeCredStore = &brokerfakes.FakeCredStore{}
+		fakeCredStore.ReferenceReturns(map[string]any{"fake-ref": "fake-value"})
 
 		providerBuilder := func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
 			return fakeServiceProvider
@@ -67,6 +70,7 @@ var _ = Describe("GetBinding", func() {
 					ProviderBuilder: providerBuilder,
 				},
 			},
+			CredStore: fakeCredStore,
 		}
 
 		serviceBroker = must(broker.New(brokerConfig, fakeStorage, utils.NewLogger("get-binding-test")))
@@ -88,13 +92,19 @@ var _ = Describe("GetBinding", func() {
 			storage.ServiceInstanceDetails{
 				GUID:             instanceID,
 				Name:             "test-instance",
-				Outputs:          storage.JSONObject{},
+				Outputs:          storage.JSONObject{"fakeInstanceOutput": "fakeInstanceValue"},
 				ServiceGUID:      offeringID,
 				PlanGUID:         planID,
 				SpaceGUID:        spaceID,
 				OrganizationGUID: orgID,
 			}, nil)
 		fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+		fakeStorage.GetServiceBindingCredentialsReturns(storage.ServiceBindingCredentials{
+			ServiceGUID:         offeringID,
+			ServiceInstanceGUID: instanceID,
+			BindingGUID:         bindingID,
+			Credentials:         storage.JSONObject{"fakeOutput": "fakeValue"},
+		}, nil)
 		fakeStorage.GetBindRequestDetailsReturns(storage.BindRequestDetails{
 			ServiceInstanceGUID: instanceID,
 			ServiceBindingGUID:  bindingID,
@@ -124,15 +134,24 @@ var _ = Describe("GetBinding", func() {
 			By("validating storage is asked for bind request details")
 			Expect(fakeStorage.GetBindRequestDetailsCallCount()).To(Equal(1))
 		})
-		It("does not return binding credentials", func() {
+		It("returns binding credentials", func() {
 			response, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{ServiceID: offeringID, PlanID: planID})
 			Expect(err).ToNot(HaveOccurred())
 
 			By("validating response")
-			Expect(response.Credentials).To(BeZero())
-
-			By("validating storage is not asked for binding credentials")
-			Expect(fakeStorage.GetServiceBindingCredentialsCallCount()).To(Equal(0))
+			Expect(response.Credentials).To(Equal(map[string]any{"fake-ref": "fake-value"}))
+
+			By("validating storage is asked for binding credentials")
+			Expect(fakeStorage.GetServiceBindingCredentialsCallCount()).To(Equal(1))
+
+			By("validating the credstore reference is built from the combined credentials")
+			Expect(fakeCredStore.ReferenceCallCount()).To(Equal(1))
+			actualPath, actualCred := fakeCredStore.ReferenceArgsForCall(0)
+			Expect(actualPath).To(Equal("/c/csb/test-service/test-binding-id/secrets-and-services"))
+			Expect(actualCred).To(Equal(map[string]any{
+				"fakeInstanceOutput": "fakeInstanceValue",
+				"fakeOutput":         "fakeValue",
+			}))
 		})
 		It("does not return binding metadata", func() {
 			response, err := serviceBroker.GetBinding(context.TODO(), instanceID, bindingID, domain.FetchBindingDetails{ServiceID: offeringID, PlanID: planID})
@@ -233,14 +252,8 @@ var _ = Describe("GetBinding", func() {
 		})
 	})
 
-	// with the current implementation, bind is a synchroneous operation which waits for all resources to be created
-	//   bindings are stored only in case resource creation is successfull
-	//   -> it is not possible to retrieve a binding that is in progress from the store
-	// When("binding operation is in progress", func() {
-	// 	It("returns status code 404 (not found)", func() {
-	// 		Fail("not implemented")
-	// 	})
-	// })
+	// credentials are only stored once a bind operation has completed,
+	//   so a binding that is in progress is reported as not found by the "binding does not exist" case above
 
 	When("service_id is not set", func() {
 		It("ignores service_id and returns binding details", func() {
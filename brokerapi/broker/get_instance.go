@@ -44,10 +44,11 @@ func (broker *ServiceBroker) GetInstance(ctx context.Context, instanceID string,
 	}
 
 	// get instance status
-	_, serviceProvider, err := broker.getDefinitionAndProvider(instanceRecord.ServiceGUID)
+	_, serviceProvider, release, err := broker.getInstanceDefinitionAndProvider(instanceRecord)
 	if err != nil {
 		return domain.GetInstanceDetailsSpec{}, fmt.Errorf("error retrieving service definition: %w", err)
 	}
+	defer release()
 
 	done, _, lastOperationType, err := serviceProvider.PollInstance(ctx, instanceRecord.GUID)
 	if err != nil {
@@ -2,18 +2,23 @@ package broker
 
 import (
 	"context"
+	"fmt"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
 	"code.cloudfoundry.org/lager/v3"
 
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
 )
 
 // LastBindingOperation fetches last operation state for a service binding.
 // GET /v2/service_instances/{instance_id}/service_bindings/{binding_id}/last_operation
 //
-// NOTE: This functionality is not implemented.
+// It is called by the platform if a bind or unbind operation was asynchronous.
 func (broker *ServiceBroker) LastBindingOperation(ctx context.Context, instanceID, bindingID string, details domain.PollDetails) (domain.LastOperation, error) {
 	broker.Logger.Info("LastBindingOperation", correlation.ID(ctx), lager.Data{
 		"instance_id":    instanceID,
@@ -23,5 +28,89 @@ func (broker *ServiceBroker) LastBindingOperation(ctx context.Context, instanceI
 		"operation_data": details.OperationData,
 	})
 
-	return domain.LastOperation{}, apiresponses.ErrAsyncRequired
+	credsExist, err := broker.store.ExistsServiceBindingCredentials(bindingID, instanceID)
+	if err != nil {
+		return domain.LastOperation{}, fmt.Errorf("error locating service binding: %w", err)
+	}
+	if !credsExist {
+		pending, err := broker.bindingPending(bindingID, instanceID)
+		switch {
+		case err != nil:
+			return domain.LastOperation{}, fmt.Errorf("error locating service binding: %w", err)
+		case !pending:
+			return domain.LastOperation{}, apiresponses.ErrBindingDoesNotExist
+		}
+	}
+
+	instance, err := broker.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return domain.LastOperation{}, fmt.Errorf("error getting service instance details: %w", err)
+	}
+
+	serviceDefinition, serviceProvider, release, err := broker.getInstanceDefinitionAndProvider(instance)
+	if err != nil {
+		return domain.LastOperation{}, err
+	}
+	defer release()
+
+	done, message, lastOperationType, err := serviceProvider.PollBinding(ctx, instanceID, bindingID)
+	if err != nil {
+		// The bind request details of a failed bind are kept, so that an unbind can destroy
+		// anything that the bind created
+		return domain.LastOperation{State: domain.Failed, Description: err.Error()}, nil
+	}
+
+	if !done {
+		return domain.LastOperation{State: domain.InProgress, Description: message}, nil
+	}
+
+	switch {
+	case lastOperationType == models.UnbindOperationType:
+		if err := broker.removeBindingData(ctx, instanceID, bindingID, serviceDefinition, serviceProvider); err != nil {
+			return domain.LastOperation{}, err
+		}
+	case !credsExist:
+		if err := broker.storeBindingCredentials(ctx, instance, bindingID, serviceDefinition, serviceProvider); err != nil {
+			return domain.LastOperation{}, err
+		}
+	}
+
+	return domain.LastOperation{State: domain.Succeeded, Description: message}, nil
+}
+
+// storeBindingCredentials saves the outputs of a completed asynchronous bind, which makes them
+// available through GetBinding.
+func (broker *ServiceBroker) storeBindingCredentials(ctx context.Context, instance storage.ServiceInstanceDetails, bindingID string, serviceDefinition *broker.ServiceDefinition, serviceProvider broker.ServiceProvider) error {
+	credsDetails, err := serviceProvider.GetBindingOutputs(ctx, instance.GUID, bindingID)
+	if err != nil {
+		return fmt.Errorf("error getting binding outputs: %w", err)
+	}
+
+	storedBindRequestDetails, err := broker.store.GetBindRequestDetails(bindingID, instance.GUID)
+	if err != nil {
+		return fmt.Errorf("error retrieving bind request details: %w", err)
+	}
+
+	binding, err := buildInstanceCredentials(credsDetails, instance.Outputs)
+	if err != nil {
+		return fmt.Errorf("error building credentials: %w", err)
+	}
+
+	// Credentials are only stored in the database once saved in the CredStore, so that
+	// a failure here will be retried when the platform next polls
+	if _, err := broker.credStore.Save(ctx, computeCredHubPath(broker.getServiceName(serviceDefinition), bindingID), binding.Credentials, paramparser.CredHubActor(storedBindRequestDetails.BindResource)); err != nil {
+		return fmt.Errorf("bind failure: %w", err)
+	}
+
+	newCreds := storage.ServiceBindingCredentials{
+		ServiceInstanceGUID: instance.GUID,
+		BindingGUID:         bindingID,
+		ServiceGUID:         instance.ServiceGUID,
+		Credentials:         credsDetails,
+	}
+	if err := broker.store.CreateServiceBindingCredentials(newCreds); err != nil {
+		return fmt.Errorf("error saving credentials to database: %w", err)
+	}
+
+	return nil
 }
@@ -2,24 +2,208 @@ package broker_test
 
 import (
 	"context"
+	"errors"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"code.cloudfoundry.org/lager/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker/brokerfakes"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
-
-	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
 )
 
 var _ = Describe("LastBindingOperation", func() {
-	It("is not implemented for async bindings", func() {
-		serviceBroker, err := broker.New(&broker.BrokerConfig{}, &brokerfakes.FakeStorage{}, utils.NewLogger("brokers-test"))
-		Expect(err).ToNot(HaveOccurred())
+	const (
+		appGUID    = "test-app-guid"
+		planID     = "test-plan-id"
+		offeringID = "test-service-id"
+		instanceID = "test-instance-id"
+		bindingID  = "test-binding-id"
+	)
+
+	var (
+		serviceBroker *broker.ServiceBroker
+		pollDetails   domain.PollDetails
+
+		fakeStorage         *brokerfakes.FakeStorage
+		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+		fakeCredStore       *brokerfakes.FakeCredStore
+	)
+
+	BeforeEach(func() {
+		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+		fakeServiceProvider.PollBindingReturns(true, "operation complete", models.BindOperationType, nil)
+		fakeServiceProvider.GetBindingOutputsReturns(storage.JSONObject{"fakeOutput": "fakeValue"}, nil)
+
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceBindingCredentialsReturns(false, nil)
+		fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+			GUID:        instanceID,
+			ServiceGUID: offeringID,
+			PlanGUID:    planID,
+			Outputs:     storage.JSONObject{"fakeInstanceOutput": "fakeInstanceValue"},
+		}, nil)
+		fakeStorage.GetBindRequestDetailsReturns(storage.BindRequestDetails{
+			ServiceInstanceGUID: instanceID,
+			ServiceBindingGUID:  bindingID,
+			BindResource:        storage.JSONObject{"app_guid": appGUID},
+		}, nil)
+
+		fakeCredStore = &brokerfakes.FakeCredStore{}
+
+		providerBuilder := func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+			return fakeServiceProvider
+		}
+
+		brokerConfig := &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:   offeringID,
+					Name: "test-service",
+					Plans: []pkgBroker.ServicePlan{
+						{ServicePlan: domain.ServicePlan{ID: planID, Name: "test-plan"}},
+					},
+					ProviderBuilder: providerBuilder,
+				},
+			},
+			CredStore: fakeCredStore,
+		}
+
+		serviceBroker = must(broker.New(brokerConfig, fakeStorage, utils.NewLogger("last-binding-operation-test")))
+
+		pollDetails = domain.PollDetails{
+			ServiceID:     offeringID,
+			PlanID:        planID,
+			OperationData: "tf:test-instance-id:test-binding-id",
+		}
+	})
+
+	When("a bind has completed", func() {
+		It("stores the binding credentials and returns succeeded", func() {
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(domain.LastOperation{State: domain.Succeeded, Description: "operation complete"}))
+
+			By("validating the provider has been polled")
+			Expect(fakeServiceProvider.PollBindingCallCount()).To(Equal(1))
+			_, actualInstanceID, actualBindingID := fakeServiceProvider.PollBindingArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualBindingID).To(Equal(bindingID))
+
+			By("validating credstore has been called")
+			Expect(fakeCredStore.SaveCallCount()).To(Equal(1))
+			_, actualPath, actualCred, actualActor := fakeCredStore.SaveArgsForCall(0)
+			Expect(actualPath).To(Equal("/c/csb/test-service/test-binding-id/secrets-and-services"))
+			Expect(actualCred).To(Equal(map[string]any{
+				"fakeInstanceOutput": "fakeInstanceValue",
+				"fakeOutput":         "fakeValue",
+			}))
+			Expect(actualActor).To(Equal("mtls-app:test-app-guid"))
+
+			By("validating the credentials are stored")
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(Equal(1))
+			Expect(fakeStorage.CreateServiceBindingCredentialsArgsForCall(0)).To(Equal(storage.ServiceBindingCredentials{
+				ServiceGUID:         offeringID,
+				ServiceInstanceGUID: instanceID,
+				BindingGUID:         bindingID,
+				Credentials:         storage.JSONObject{"fakeOutput": "fakeValue"},
+			}))
+		})
+
+		When("saving to the credstore fails", func() {
+			It("returns an error and does not store the credentials", func() {
+				fakeCredStore.SaveReturns(nil, errors.New("credstore boom"))
+
+				_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+				Expect(err).To(MatchError("bind failure: credstore boom"))
+				Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+			})
+		})
+
+		When("the credentials were already stored", func() {
+			It("does not store them again", func() {
+				fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+
+				response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.State).To(Equal(domain.Succeeded))
+				Expect(fakeCredStore.SaveCallCount()).To(BeZero())
+				Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	When("an unbind has completed", func() {
+		It("removes the binding data and returns succeeded", func() {
+			fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+			fakeServiceProvider.PollBindingReturns(true, "", models.UnbindOperationType, nil)
+
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.State).To(Equal(domain.Succeeded))
+
+			Expect(fakeCredStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteServiceBindingCredentialsCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.DeleteBindingDataCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the operation is in progress", func() {
+		It("returns in progress", func() {
+			fakeServiceProvider.PollBindingReturns(false, "still working", models.BindOperationType, nil)
+
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(domain.LastOperation{State: domain.InProgress, Description: "still working"}))
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+		})
+	})
+
+	When("the operation has failed", func() {
+		It("returns failed", func() {
+			fakeServiceProvider.PollBindingReturns(true, "", models.BindOperationType, errors.New("apply failed"))
+
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(domain.LastOperation{State: domain.Failed, Description: "apply failed"}))
+			Expect(fakeStorage.CreateServiceBindingCredentialsCallCount()).To(BeZero())
+		})
+
+		It("keeps the bind request details so that an unbind can clean up", func() {
+			fakeServiceProvider.PollBindingReturns(true, "", models.BindOperationType, errors.New("apply failed"))
+
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(BeZero())
+			Expect(fakeServiceProvider.DeleteBindingDataCallCount()).To(BeZero())
+		})
+
+		It("keeps the bind request details when an unbind failed", func() {
+			fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+			fakeServiceProvider.PollBindingReturns(true, "", models.UnbindOperationType, errors.New("destroy failed"))
+
+			response, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.State).To(Equal(domain.Failed))
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(BeZero())
+		})
+	})
 
-		_, err = serviceBroker.LastBindingOperation(context.TODO(), "instance-id", "binding-id", domain.PollDetails{})
+	When("the binding does not exist", func() {
+		It("returns binding does not exist", func() {
+			fakeStorage.GetBindRequestDetailsReturns(storage.BindRequestDetails{}, nil)
 
-		Expect(err).To(MatchError("This service plan requires client support for asynchronous service operations."))
+			_, err := serviceBroker.LastBindingOperation(context.TODO(), instanceID, bindingID, pollDetails)
+			Expect(err).To(MatchError(apiresponses.ErrBindingDoesNotExist))
+			Expect(fakeServiceProvider.PollBindingCallCount()).To(BeZero())
+		})
 	})
 })
@@ -41,10 +41,11 @@ func (broker *ServiceBroker) LastOperation(ctx context.Context, instanceID strin
 		return domain.LastOperation{}, fmt.Errorf("error getting service instance details: %w", err)
 	}
 
-	_, serviceProvider, err := broker.getDefinitionAndProvider(instance.ServiceGUID)
+	_, serviceProvider, release, err := broker.getInstanceDefinitionAndProvider(instance)
 	if err != nil {
 		return domain.LastOperation{}, err
 	}
+	defer release()
 
 	done, message, lastOperationType, err := serviceProvider.PollInstance(ctx, instance.GUID)
 	if err != nil {
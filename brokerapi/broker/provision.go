@@ -24,6 +24,8 @@ import (
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/request"
 )
@@ -41,29 +43,68 @@ func (broker *ServiceBroker) Provision(ctx context.Context, instanceID string, d
 		return domain.ProvisionedServiceSpec{}, apiresponses.ErrAsyncRequired
 	}
 
+	req, err := broker.prepareProvision(ctx, instanceID, details)
+	if err != nil {
+		return domain.ProvisionedServiceSpec{}, err
+	}
+	defer req.release()
+
+	err = req.provider.Provision(ctx, req.vars)
+	if err != nil {
+		return domain.ProvisionedServiceSpec{}, err
+	}
+
+	if err := broker.storeProvisionedInstance(instanceID, req); err != nil {
+		return domain.ProvisionedServiceSpec{}, err
+	}
+
+	operationID := generateTFInstanceID(instanceID)
+
+	return domain.ProvisionedServiceSpec{IsAsync: true, DashboardURL: "", OperationData: operationID}, nil
+}
+
+// provisionRequest is a validated request to provision a service instance
+type provisionRequest struct {
+	details    paramparser.ProvisionDetails
+	definition *broker.ServiceDefinition
+	provider   broker.ServiceProvider
+	vars       *varcontext.VarContext
+
+	// release must be called once the request has finished with the registry
+	release func()
+}
+
+// prepareProvision checks that the instance does not exist, validates the request against the service
+// definition and quotas, and computes the variables for the provision template
+func (broker *ServiceBroker) prepareProvision(ctx context.Context, instanceID string, details domain.ProvisionDetails) (_ provisionRequest, err error) {
 	// make sure that instance hasn't already been provisioned
 	exists, err := broker.store.ExistsServiceInstanceDetails(instanceID)
 	switch {
 	case err != nil:
-		return domain.ProvisionedServiceSpec{}, fmt.Errorf("database error checking for existing instance: %s", err)
+		return provisionRequest{}, fmt.Errorf("database error checking for existing instance: %s", err)
 	case exists:
-		return domain.ProvisionedServiceSpec{}, apiresponses.ErrInstanceAlreadyExists
+		return provisionRequest{}, apiresponses.ErrInstanceAlreadyExists
 	}
 
 	parsedDetails, err := paramparser.ParseProvisionDetails(details)
 	if err != nil {
-		return domain.ProvisionedServiceSpec{}, ErrInvalidUserInput
+		return provisionRequest{}, ErrInvalidUserInput
 	}
 
-	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(parsedDetails.ServiceID)
+	serviceDefinition, serviceProvider, release, err := broker.getDefinitionAndProvider(parsedDetails.ServiceID)
 	if err != nil {
-		return domain.ProvisionedServiceSpec{}, err
+		return provisionRequest{}, err
 	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
 
 	// verify the service exists and the plan exists
 	plan, err := serviceDefinition.GetPlanByID(parsedDetails.PlanID)
 	if err != nil {
-		return domain.ProvisionedServiceSpec{}, err
+		return provisionRequest{}, err
 	}
 
 	// Give the user a better error message if they give us a bad request
@@ -72,41 +113,49 @@ func (broker *ServiceBroker) Provision(ctx context.Context, instanceID string, d
 		serviceDefinition.ProvisionInputVariables,
 		serviceDefinition.ImportInputVariables,
 		plan); err != nil {
-		return domain.ProvisionedServiceSpec{}, err
+		return provisionRequest{}, err
+	}
+
+	if err := broker.checkQuotas(serviceDefinition, plan, parsedDetails); err != nil {
+		return provisionRequest{}, err
 	}
 
 	// validate parameters meet the service's schema and merge the user vars with
 	// the plan's
 	vars, err := serviceDefinition.ProvisionVariables(instanceID, parsedDetails, *plan, request.DecodeOriginatingIdentityHeader(ctx))
 	if err != nil {
-		return domain.ProvisionedServiceSpec{}, err
+		return provisionRequest{}, err
 	}
 
-	err = serviceProvider.Provision(ctx, vars)
-	if err != nil {
-		return domain.ProvisionedServiceSpec{}, err
-	}
+	return provisionRequest{
+		details:    parsedDetails,
+		definition: serviceDefinition,
+		provider:   serviceProvider,
+		vars:       vars,
+		release:    release,
+	}, nil
+}
 
-	// save instance details
+// storeProvisionedInstance saves the details of an instance whose provision has started
+func (broker *ServiceBroker) storeProvisionedInstance(instanceID string, req provisionRequest) error {
 	instanceDetails := storage.ServiceInstanceDetails{
-		ServiceGUID:      parsedDetails.ServiceID,
-		GUID:             instanceID,
-		PlanGUID:         parsedDetails.PlanID,
-		SpaceGUID:        parsedDetails.SpaceGUID,
-		OrganizationGUID: parsedDetails.OrganizationGUID,
+		ServiceGUID:       req.details.ServiceID,
+		GUID:              instanceID,
+		PlanGUID:          req.details.PlanID,
+		SpaceGUID:         req.details.SpaceGUID,
+		OrganizationGUID:  req.details.OrganizationGUID,
+		DefinitionVersion: req.definition.Version,
 	}
 
 	if err := broker.store.StoreServiceInstanceDetails(instanceDetails); err != nil {
-		return domain.ProvisionedServiceSpec{}, fmt.Errorf("error saving instance details to database: %s. WARNING: this instance cannot be deprovisioned through cf. Contact your operator for cleanup", err)
+		return fmt.Errorf("error saving instance details to database: %s. WARNING: this instance cannot be deprovisioned through cf. Contact your operator for cleanup", err)
 	}
 
 	// save provision request details
-	delete(parsedDetails.RequestParams, "vacant")
-	if err := broker.store.StoreProvisionRequestDetails(instanceID, parsedDetails.RequestParams); err != nil {
-		return domain.ProvisionedServiceSpec{}, fmt.Errorf("error saving provision request details to database: %s. Services relying on async provisioning will not be able to complete provisioning", err)
+	delete(req.details.RequestParams, "vacant")
+	if err := broker.store.StoreProvisionRequestDetails(instanceID, req.details.RequestParams); err != nil {
+		return fmt.Errorf("error saving provision request details to database: %s. Services relying on async provisioning will not be able to complete provisioning", err)
 	}
 
-	operationID := generateTFInstanceID(instanceDetails.GUID)
-
-	return domain.ProvisionedServiceSpec{IsAsync: true, DashboardURL: "", OperationData: operationID}, nil
+	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
@@ -55,6 +57,7 @@ var _ = Describe("Provision", func() {
 					GlobalLabels: map[string]string{"key1": "value1", "key2": "value2"},
 					ID:           offeringID,
 					Name:         "test-service",
+					Version:      "1.2.3",
 					Plans: []pkgBroker.ServicePlan{
 						{
 							ServicePlan: domain.ServicePlan{
@@ -141,6 +144,7 @@ var _ = Describe("Provision", func() {
 			Expect(actualSIDetails.PlanGUID).To(Equal(planID))
 			Expect(actualSIDetails.SpaceGUID).To(Equal(spaceID))
 			Expect(actualSIDetails.OrganizationGUID).To(Equal(orgID))
+			Expect(actualSIDetails.DefinitionVersion).To(Equal("1.2.3"))
 
 			By("validating provision parameters storing call")
 			Expect(fakeStorage.StoreProvisionRequestDetailsCallCount()).To(Equal(1))
@@ -205,6 +209,35 @@ var _ = Describe("Provision", func() {
 		})
 	})
 
+	Describe("brokerpak reload", func() {
+		It("does not report the previous registry as released until the provision has finished with it", func() {
+			var released <-chan struct{}
+			fakeServiceProvider.ProvisionStub = func(context.Context, *varcontext.VarContext) error {
+				var err error
+				released, err = serviceBroker.SwapRegistry(serviceBroker.Registry())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(released).NotTo(BeClosed())
+				return nil
+			}
+
+			_, err := serviceBroker.Provision(context.TODO(), newInstanceID, provisionDetails, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(released).To(BeClosed())
+		})
+
+		It("reports the previous registry as released when the provision fails", func() {
+			var released <-chan struct{}
+			fakeServiceProvider.ProvisionStub = func(context.Context, *varcontext.VarContext) error {
+				released, _ = serviceBroker.SwapRegistry(serviceBroker.Registry())
+				return errors.New("boom")
+			}
+
+			_, err := serviceBroker.Provision(context.TODO(), newInstanceID, provisionDetails, true)
+			Expect(err).To(MatchError("boom"))
+			Expect(released).To(BeClosed())
+		})
+	})
+
 	Describe("invalid provision parameters", func() {
 		When("additional properties are passed", func() {
 			It("should error", func() {
@@ -260,6 +293,69 @@ var _ = Describe("Provision", func() {
 		})
 	})
 
+	Describe("quotas", func() {
+		AfterEach(func() {
+			viper.Reset()
+		})
+
+		When("a quota applies and has capacity", func() {
+			It("provisions the instance", func() {
+				viper.Set("service.test-service.quotas", `[{"plan_name":"test-plan","organization_guid":"test-org-id","limit":2}]`)
+				fakeStorage.CountServiceInstanceDetailsReturns(1, nil)
+
+				_, err := serviceBroker.Provision(context.TODO(), "new-instance", provisionDetails, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeServiceProvider.ProvisionCallCount()).To(Equal(1))
+
+				By("validating the instances were counted for the scope of the quota")
+				Expect(fakeStorage.CountServiceInstanceDetailsCallCount()).To(Equal(1))
+				Expect(fakeStorage.CountServiceInstanceDetailsArgsForCall(0)).To(Equal(storage.ServiceInstanceDetails{
+					ServiceGUID:      offeringID,
+					PlanGUID:         planID,
+					OrganizationGUID: orgID,
+				}))
+			})
+		})
+
+		When("a quota applies and would be exceeded", func() {
+			It("returns HTTP 422 and does not provision", func() {
+				viper.Set("service.test-service.quotas", `[{"space_guid":"test-space-id","limit":2}]`)
+				fakeStorage.CountServiceInstanceDetailsReturns(2, nil)
+
+				_, err := serviceBroker.Provision(context.TODO(), "new-instance", provisionDetails, true)
+				Expect(err).To(MatchError(`quota exceeded for service "test-service" (space "test-space-id": limit 2): 2 instance(s) already exist`))
+
+				Expect(err.(*apiresponses.FailureResponse).ValidatedStatusCode(slog.Default())).To(Equal(http.StatusUnprocessableEntity))
+
+				Expect(fakeServiceProvider.ProvisionCallCount()).To(BeZero())
+				Expect(fakeStorage.CountServiceInstanceDetailsArgsForCall(0)).To(Equal(storage.ServiceInstanceDetails{
+					ServiceGUID: offeringID,
+					SpaceGUID:   spaceID,
+				}))
+			})
+		})
+
+		When("a quota does not apply", func() {
+			It("does not count instances", func() {
+				viper.Set("service.test-service.quotas", `[{"plan_name":"other-plan","limit":0}]`)
+
+				_, err := serviceBroker.Provision(context.TODO(), "new-instance", provisionDetails, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeStorage.CountServiceInstanceDetailsCallCount()).To(BeZero())
+			})
+		})
+
+		When("counting instances fails", func() {
+			It("should error", func() {
+				viper.Set("service.test-service.quotas", `[{"limit":1}]`)
+				fakeStorage.CountServiceInstanceDetailsReturns(0, errors.New("failed to count"))
+
+				_, err := serviceBroker.Provision(context.TODO(), "new-instance", provisionDetails, true)
+				Expect(err).To(MatchError("error counting service instances for quota: failed to count"))
+			})
+		})
+	})
+
 	When("instance already exists", func() {
 		BeforeEach(func() {
 			fakeStorage.ExistsServiceInstanceDetailsReturns(true, nil)
@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+// checkQuotas rejects a provision that would take the number of instances over any operator-configured quota
+// that applies to the plan, organization and space of the request.
+func (broker *ServiceBroker) checkQuotas(serviceDefinition *broker.ServiceDefinition, plan *broker.ServicePlan, details paramparser.ProvisionDetails) error {
+	quotas, err := serviceDefinition.Quotas()
+	if err != nil {
+		return fmt.Errorf("error reading quotas: %w", err)
+	}
+
+	for _, quota := range quotas {
+		if !quota.Matches(*plan, details.OrganizationGUID, details.SpaceGUID) {
+			continue
+		}
+
+		filter := storage.ServiceInstanceDetails{
+			ServiceGUID:      serviceDefinition.ID,
+			OrganizationGUID: quota.OrganizationGUID,
+			SpaceGUID:        quota.SpaceGUID,
+		}
+		if quota.PlanID != "" || quota.PlanName != "" {
+			filter.PlanGUID = plan.ID
+		}
+
+		count, err := broker.store.CountServiceInstanceDetails(filter)
+		if err != nil {
+			return fmt.Errorf("error counting service instances for quota: %w", err)
+		}
+
+		if count >= int64(quota.Limit) {
+			return apiresponses.NewFailureResponse(
+				fmt.Errorf("quota exceeded for service %q (%s): %d instance(s) already exist", serviceDefinition.Name, quota, count),
+				http.StatusUnprocessableEntity,
+				quotaExceededKey,
+			)
+		}
+	}
+
+	return nil
+}
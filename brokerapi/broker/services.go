@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 )
 
@@ -12,7 +13,8 @@ import (
 func (broker *ServiceBroker) Services(_ context.Context) ([]domain.Service, error) {
 	var svcs []domain.Service
 
-	enabledServices, err := broker.registry.GetEnabledServices()
+	registry := broker.Registry()
+	enabledServices, err := registry.GetEnabledServices()
 	if err != nil {
 		return nil, err
 	}
@@ -24,14 +26,34 @@ func (broker *ServiceBroker) Services(_ context.Context) ([]domain.Service, erro
 	return svcs, nil
 }
 
-func (broker *ServiceBroker) getDefinitionAndProvider(serviceID string) (*broker.ServiceDefinition, broker.ServiceProvider, error) {
-	defn, err := broker.registry.GetServiceByID(serviceID)
+// getDefinitionAndProvider returns the definition of a service from the current registry.
+// The returned function must be called once the request has finished with the registry.
+func (broker *ServiceBroker) getDefinitionAndProvider(serviceID string) (*broker.ServiceDefinition, broker.ServiceProvider, func(), error) {
+	registry, release := broker.acquireRegistry()
+	defn, err := registry.GetServiceByID(serviceID)
 	if err != nil {
-		return nil, nil, err
+		release()
+		return nil, nil, nil, err
 	}
 
 	providerBuilder := defn.ProviderBuilder(broker.Logger, broker.store)
-	return defn, providerBuilder, nil
+	return defn, providerBuilder, release, nil
+}
+
+// getInstanceDefinitionAndProvider returns the definition that the service instance is pinned to,
+// which may come from an older version of the brokerpak than the definition in the catalog.
+// The returned function must be called once the request has finished with the registry.
+func (broker *ServiceBroker) getInstanceDefinitionAndProvider(instance storage.ServiceInstanceDetails) (*broker.ServiceDefinition, broker.ServiceProvider, func(), error) {
+	registry, release := broker.acquireRegistry()
+	defn, err := registry.GetServiceByID(instance.ServiceGUID)
+	if err != nil {
+		release()
+		return nil, nil, nil, err
+	}
+
+	defn = defn.ForVersion(instance.DefinitionVersion)
+	providerBuilder := defn.ProviderBuilder(broker.Logger, broker.store)
+	return defn, providerBuilder, release, nil
 }
 
 func (broker *ServiceBroker) getServiceName(def *broker.ServiceDefinition) string {
@@ -90,4 +90,58 @@ var _ = Describe("Services", func() {
 			Expect(servicesList[1].Plans[0].Name).To(Equal("test-plan-3"))
 		})
 	})
+	Describe("swapping the registry", func() {
+		newRegistry := func(services ...*pkgBroker.ServiceDefinition) pkgBroker.BrokerRegistry {
+			registry := pkgBroker.BrokerRegistry{}
+			for _, s := range services {
+				registry[s.Name] = s
+			}
+			return registry
+		}
+
+		It("serves the new service offerings", func() {
+			_, err := serviceBroker.SwapRegistry(newRegistry(&pkgBroker.ServiceDefinition{
+				ID:    "third-service-id",
+				Name:  "third-service",
+				Plans: []pkgBroker.ServicePlan{{ServicePlan: domain.ServicePlan{ID: "plan-4", Name: "test-plan-4"}}},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+
+			servicesList, err := serviceBroker.Services(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(servicesList).To(HaveLen(1))
+			Expect(servicesList[0].ID).To(Equal("third-service-id"))
+		})
+
+		It("keeps the current service offerings when the new registry is invalid", func() {
+			_, err := serviceBroker.SwapRegistry(newRegistry(
+				&pkgBroker.ServiceDefinition{ID: "duplicate-id", Name: "third-service"},
+				&pkgBroker.ServiceDefinition{ID: "duplicate-id", Name: "fourth-service"},
+			))
+			Expect(err).To(MatchError(ContainSubstring("invalid registry")))
+
+			servicesList, err := serviceBroker.Services(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(servicesList).To(HaveLen(2))
+		})
+
+		It("keeps the current service offerings when the new registry is empty", func() {
+			_, err := serviceBroker.SwapRegistry(pkgBroker.BrokerRegistry{})
+			Expect(err).To(MatchError("invalid registry: no services are defined"))
+
+			servicesList, err := serviceBroker.Services(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(servicesList).To(HaveLen(2))
+		})
+
+		It("reports the previous registry as released when no request is using it", func() {
+			released, err := serviceBroker.SwapRegistry(newRegistry(&pkgBroker.ServiceDefinition{
+				ID:    "third-service-id",
+				Name:  "third-service",
+				Plans: []pkgBroker.ServicePlan{{ServicePlan: domain.ServicePlan{ID: "plan-4", Name: "test-plan-4"}}},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(released).To(BeClosed())
+		})
+	})
 })
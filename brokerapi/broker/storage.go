@@ -19,14 +19,19 @@ type Storage interface {
 	StoreServiceInstanceDetails(d storage.ServiceInstanceDetails) error
 	GetServiceInstanceDetails(guid string) (storage.ServiceInstanceDetails, error)
 	ExistsServiceInstanceDetails(guid string) (bool, error)
+	CountServiceInstanceDetails(filter storage.ServiceInstanceDetails) (int64, error)
 	DeleteServiceInstanceDetails(guid string) error
 
 	StoreBindRequestDetails(bindingID, instanceID string, bindResource, parameters storage.JSONObject) error
 	GetBindRequestDetails(bindingID, instanceID string) (storage.BindRequestDetails, error)
+	UpdateBindRequestDetails(bindingID, instanceID string, parameters storage.JSONObject) error
 	DeleteBindRequestDetails(bindingID, instanceID string) error
 
 	CreateServiceBindingCredentials(binding storage.ServiceBindingCredentials) error
 	GetServiceBindingCredentials(bindingID, serviceInstanceID string) (storage.ServiceBindingCredentials, error)
+	UpdateServiceBindingCredentials(binding storage.ServiceBindingCredentials) error
 	ExistsServiceBindingCredentials(bindingID, serviceInstanceID string) (bool, error)
 	DeleteServiceBindingCredentials(bindingID, serviceInstanceID string) error
+
+	RetainTerraformDeployment(serviceInstanceID, deploymentID string) error
 }
@@ -8,8 +8,10 @@ import (
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
 	"code.cloudfoundry.org/lager/v3"
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/featureflags"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/request"
@@ -17,26 +19,36 @@ import (
 
 // Unbind destroys an account and credentials with access to an instance of a service.
 // It is bound to the `DELETE /v2/service_instances/:instance_id/service_bindings/:binding_id` endpoint and can be called using the `cf unbind-service` command.
-func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, _ bool) (domain.UnbindSpec, error) {
+// When asynchronous bindings are enabled and the platform allows it, the unbind runs in the background and
+// the binding data is removed once LastBindingOperation observes that it has completed.
+func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID string, details domain.UnbindDetails, asyncAllowed bool) (domain.UnbindSpec, error) {
 	broker.Logger.Info("Unbinding", correlation.ID(ctx), lager.Data{
-		"instance_id": instanceID,
-		"binding_id":  bindingID,
-		"details":     details,
+		"instance_id":        instanceID,
+		"binding_id":         bindingID,
+		"accepts_incomplete": asyncAllowed,
+		"details":            details,
 	})
 
 	// verify the service exists and the plan exists
-	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(details.ServiceID)
+	serviceDefinition, serviceProvider, release, err := broker.getDefinitionAndProvider(details.ServiceID)
 	if err != nil {
 		return domain.UnbindSpec{}, err
 	}
+	defer release()
 
-	// validate existence of binding
+	// validate existence of binding, which may still be pending if it was created asynchronously
 	exists, err := broker.store.ExistsServiceBindingCredentials(bindingID, instanceID)
-	switch {
-	case err != nil:
+	if err != nil {
 		return domain.UnbindSpec{}, fmt.Errorf("error locating service binding: %w", err)
-	case !exists:
-		return domain.UnbindSpec{}, apiresponses.ErrBindingDoesNotExist
+	}
+	if !exists {
+		pending, err := broker.bindingPending(bindingID, instanceID)
+		switch {
+		case err != nil:
+			return domain.UnbindSpec{}, fmt.Errorf("error locating service binding: %w", err)
+		case !pending:
+			return domain.UnbindSpec{}, apiresponses.ErrBindingDoesNotExist
+		}
 	}
 
 	// get existing service instance details
@@ -46,6 +58,19 @@ func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID s
 		return domain.UnbindSpec{}, apiresponses.ErrInstanceDoesNotExist
 	}
 
+	if pinned := serviceDefinition.ForVersion(instance.DefinitionVersion); pinned != serviceDefinition {
+		serviceDefinition, serviceProvider = pinned, pinned.ProviderBuilder(broker.Logger, broker.store)
+	}
+
+	if retainsResources(instance, serviceDefinition) {
+		// The resources are not destroyed. The workspace is retained so that they can be imported again later.
+		broker.Logger.Info("unbind-retain-resources", lager.Data{"released": instance.Released})
+		if err := broker.retainBinding(ctx, instanceID, bindingID, serviceDefinition, serviceProvider); err != nil {
+			return domain.UnbindSpec{}, err
+		}
+		return domain.UnbindSpec{}, nil
+	}
+
 	err = serviceProvider.CheckUpgradeAvailable(generateTFBindingID(instanceID, bindingID))
 	switch {
 	case errors.As(err, &workspace.CannotReadVersionError{}):
@@ -61,6 +86,10 @@ func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID s
 		return domain.UnbindSpec{}, fmt.Errorf("failed to unbind: %s", err.Error())
 	}
 
+	if err := serviceProvider.CheckOperationConstraints(generateTFBindingID(instanceID, bindingID), models.UnbindOperationType); err != nil {
+		return domain.UnbindSpec{}, err
+	}
+
 	plan, err := serviceDefinition.GetPlanByID(details.PlanID)
 	if err != nil {
 		return domain.UnbindSpec{}, err
@@ -81,6 +110,13 @@ func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID s
 		return domain.UnbindSpec{}, err
 	}
 
+	if asyncAllowed && featureflags.Enabled(featureflags.AsyncBindingsEnabled) {
+		if err := serviceProvider.UnbindAsync(ctx, instanceID, bindingID, vars); err != nil {
+			return domain.UnbindSpec{}, err
+		}
+		return domain.UnbindSpec{IsAsync: true, OperationData: generateTFBindingID(instanceID, bindingID)}, nil
+	}
+
 	// remove binding from service provider
 	if err := serviceProvider.Unbind(ctx, instanceID, bindingID, vars); err != nil {
 		return domain.UnbindSpec{}, err
@@ -93,6 +129,17 @@ func (broker *ServiceBroker) Unbind(ctx context.Context, instanceID, bindingID s
 	return domain.UnbindSpec{}, nil
 }
 
+func (broker *ServiceBroker) retainBinding(ctx context.Context, instanceID, bindingID string, serviceDefinition *broker.ServiceDefinition, serviceProvider broker.ServiceProvider) error {
+	deploymentID := generateTFBindingID(instanceID, bindingID)
+	if err := serviceProvider.CheckOperationConstraints(deploymentID, models.UnbindOperationType); err != nil {
+		return err
+	}
+	if err := broker.store.RetainTerraformDeployment(instanceID, deploymentID); err != nil {
+		return fmt.Errorf("error retaining workspace: %w", err)
+	}
+	return broker.removeBindingData(ctx, instanceID, bindingID, serviceDefinition, serviceProvider)
+}
+
 func (broker *ServiceBroker) removeBindingData(ctx context.Context, instanceID, bindingID string, serviceDefinition *broker.ServiceDefinition, serviceProvider broker.ServiceProvider) error {
 	// remove the credential from CredHub
 	if err := broker.credStore.Delete(ctx, computeCredHubPath(broker.getServiceName(serviceDefinition), bindingID)); err != nil {
@@ -14,11 +14,13 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
 	pkgBroker "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/featureflags"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 )
 
 var _ = Describe("Unbind", func() {
@@ -276,6 +278,98 @@ var _ = Describe("Unbind", func() {
 		})
 	})
 
+	When("the instance has been released", func() {
+		BeforeEach(func() {
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+				GUID:        instanceID,
+				ServiceGUID: offeringID,
+				PlanGUID:    planID,
+				Released:    true,
+			}, nil)
+		})
+
+		It("retains the workspace and removes the binding without destroying its resources", func() {
+			response, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.IsAsync).To(BeFalse())
+
+			Expect(fakeServiceProvider.UnbindCallCount()).To(BeZero())
+			Expect(fakeServiceProvider.UnbindAsyncCallCount()).To(BeZero())
+			Expect(fakeStorage.RetainTerraformDeploymentCallCount()).To(Equal(1))
+			actualInstanceID, actualDeploymentID := fakeStorage.RetainTerraformDeploymentArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualDeploymentID).To(Equal("tf:test-instance-id:test-binding-id"))
+			Expect(fakeStorage.DeleteServiceBindingCredentialsCallCount()).To(Equal(1))
+			Expect(fakeStorage.DeleteBindRequestDetailsCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.DeleteBindingDataCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the plan retains resources on delete", func() {
+		BeforeEach(func() {
+			serviceBroker.Registry()["test-service"].Plans[0].RetainOnDelete = true
+		})
+
+		It("retains the workspace and removes the binding without destroying its resources", func() {
+			_, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeServiceProvider.UnbindCallCount()).To(BeZero())
+			Expect(fakeStorage.RetainTerraformDeploymentCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.DeleteBindingDataCallCount()).To(Equal(1))
+		})
+
+		When("an operation is in progress", func() {
+			BeforeEach(func() {
+				fakeServiceProvider.CheckOperationConstraintsReturns(errors.New("operation in progress"))
+			})
+
+			It("should error", func() {
+				_, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, false)
+				Expect(err).To(MatchError("operation in progress"))
+				Expect(fakeStorage.RetainTerraformDeploymentCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("asynchronous unbind", func() {
+		BeforeEach(func() {
+			viper.Set(string(featureflags.AsyncBindingsEnabled), true)
+		})
+
+		AfterEach(func() {
+			viper.Reset()
+		})
+
+		It("starts the unbind and keeps the binding data", func() {
+			response, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(domain.UnbindSpec{
+				IsAsync:       true,
+				OperationData: "tf:test-instance-id:test-binding-id",
+			}))
+
+			By("validating provider async unbind has been called")
+			Expect(fakeServiceProvider.UnbindAsyncCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.UnbindCallCount()).To(BeZero())
+
+			By("validating the binding data is removed only once the operation completes")
+			Expect(fakeCredStore.DeleteCallCount()).To(BeZero())
+			Expect(fakeStorage.DeleteServiceBindingCredentialsCallCount()).To(BeZero())
+			Expect(fakeServiceProvider.DeleteBindingDataCallCount()).To(BeZero())
+		})
+
+		When("a bind is still in progress", func() {
+			It("should error", func() {
+				fakeServiceProvider.CheckOperationConstraintsReturns(apiresponses.ErrConcurrentInstanceAccess)
+
+				_, err := serviceBroker.Unbind(context.TODO(), instanceID, bindingID, unbindDetails, true)
+				Expect(err).To(MatchError(apiresponses.ErrConcurrentInstanceAccess))
+				Expect(fakeServiceProvider.UnbindAsyncCallCount()).To(BeZero())
+			})
+		})
+	})
+
 	Describe("unsuccessful unbind", func() {
 		When("service offering does not exists", func() {
 			const nonExistentService = "non-existent-service"
@@ -318,6 +412,7 @@ var _ = Describe("Unbind", func() {
 		When("the service binding credentials do not exist", func() {
 			BeforeEach(func() {
 				fakeStorage.ExistsServiceBindingCredentialsReturns(false, nil)
+				fakeStorage.GetBindRequestDetailsReturns(storage.BindRequestDetails{}, nil)
 			})
 
 			It("should return HTTP 410 as per OSBAPI spec", func() {
@@ -42,10 +42,15 @@ func (broker *ServiceBroker) Update(ctx context.Context, instanceID string, deta
 		return domain.UpdateServiceSpec{}, fmt.Errorf("database error getting existing instance: %s", err)
 	}
 
-	serviceDefinition, serviceProvider, err := broker.getDefinitionAndProvider(instance.ServiceGUID)
+	if instance.Released {
+		return domain.UpdateServiceSpec{}, ErrInstanceReleased
+	}
+
+	serviceDefinition, serviceProvider, release, err := broker.getDefinitionAndProvider(instance.ServiceGUID)
 	if err != nil {
 		return domain.UpdateServiceSpec{}, err
 	}
+	defer release()
 
 	parsedDetails, err := paramparser.ParseUpdateDetails(details)
 	if err != nil {
@@ -67,6 +72,18 @@ func (broker *ServiceBroker) Update(ctx context.Context, instanceID string, deta
 		return domain.UpdateServiceSpec{}, apiresponses.ErrAsyncRequired
 	}
 
+	// An upgrade moves the instance onto the definition in the catalog,
+	// otherwise the instance keeps using the definition it is pinned to
+	operation, decideErr := decider.DecideOperation(maintenanceInfoVersion, parsedDetails)
+	if operation != decider.Upgrade {
+		if pinned := serviceDefinition.ForVersion(instance.DefinitionVersion); pinned != serviceDefinition {
+			serviceDefinition, serviceProvider = pinned, pinned.ProviderBuilder(broker.Logger, broker.store)
+			if plan, err = serviceDefinition.GetPlanByID(parsedDetails.PlanID); err != nil {
+				return domain.UpdateServiceSpec{}, err
+			}
+		}
+	}
+
 	// Give the user a better error message if they give us a bad request
 	if err := validateProvisionParameters(parsedDetails.RequestParams, serviceDefinition.ProvisionInputVariables, nil, plan); err != nil {
 		return domain.UpdateServiceSpec{}, err
@@ -74,6 +91,9 @@ func (broker *ServiceBroker) Update(ctx context.Context, instanceID string, deta
 	if !serviceDefinition.AllowedUpdate(parsedDetails.RequestParams) {
 		return domain.UpdateServiceSpec{}, ErrNonUpdatableParameter
 	}
+	if err := serviceDefinition.ValidateUpdateParameters(parsedDetails.RequestParams, *plan); err != nil {
+		return domain.UpdateServiceSpec{}, err
+	}
 
 	provisionDetails, err := broker.store.GetProvisionRequestDetails(instanceID)
 	if err != nil {
@@ -99,10 +119,9 @@ func (broker *ServiceBroker) Update(ctx context.Context, instanceID string, deta
 		return domain.UpdateServiceSpec{}, err
 	}
 
-	operation, err := decider.DecideOperation(maintenanceInfoVersion, parsedDetails)
 	switch {
-	case err != nil:
-		return domain.UpdateServiceSpec{}, fmt.Errorf("error deciding update path: %w", err)
+	case decideErr != nil:
+		return domain.UpdateServiceSpec{}, fmt.Errorf("error deciding update path: %w", decideErr)
 	case operation == decider.Upgrade:
 		return broker.doUpgrade(ctx, serviceDefinition, serviceProvider, instance, vars, plan)
 	default:
@@ -138,6 +157,7 @@ func (broker *ServiceBroker) doUpgrade(ctx context.Context, serviceDefinition *b
 		}
 
 		instance.Outputs = outs
+		instance.DefinitionVersion = serviceDefinition.Version
 		if err := broker.store.StoreServiceInstanceDetails(instance); err != nil {
 			broker.storeUpgradeError(err, instance.GUID)
 			return
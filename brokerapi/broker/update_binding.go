@@ -0,0 +1,130 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/paramparser"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils/request"
+)
+
+// UpdateBinding changes the parameters of an existing binding by re-applying its Terraform workspace.
+// The OSB API does not define binding updates, so it is bound to the broker-specific
+// `PATCH /v2/service_instances/:instance_id/service_bindings/:binding_id` endpoint.
+func (broker *ServiceBroker) UpdateBinding(ctx context.Context, instanceID, bindingID string, details domain.BindDetails) (domain.Binding, error) {
+	broker.Logger.Info("UpdatingBinding", correlation.ID(ctx), lager.Data{
+		"instance_id": instanceID,
+		"binding_id":  bindingID,
+		"details":     details,
+	})
+
+	// validate existence of binding
+	exists, err := broker.store.ExistsServiceBindingCredentials(bindingID, instanceID)
+	switch {
+	case err != nil:
+		return domain.Binding{}, fmt.Errorf("error locating service binding: %w", err)
+	case !exists:
+		return domain.Binding{}, ErrNotFound
+	}
+
+	// get existing service instance details
+	instanceRecord, err := broker.store.GetServiceInstanceDetails(instanceID)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error retrieving service instance details: %w", err)
+	}
+
+	serviceDefinition, serviceProvider, release, err := broker.getInstanceDefinitionAndProvider(instanceRecord)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error retrieving service definition: %w", err)
+	}
+	defer release()
+
+	err = serviceProvider.CheckUpgradeAvailable(generateTFBindingID(instanceID, bindingID))
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("failed to update binding: %s", err.Error())
+	}
+
+	plan, err := serviceDefinition.GetPlanByID(instanceRecord.PlanGUID)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error getting service plan: %w", err)
+	}
+
+	var requestParams map[string]any
+	if len(details.RawParameters) > 0 {
+		if err := json.Unmarshal(details.RawParameters, &requestParams); err != nil {
+			return domain.Binding{}, ErrInvalidUserInput
+		}
+	}
+
+	// Give the user a better error message if they give us a bad request
+	if err := validateBindParameters(requestParams, serviceDefinition.BindInputVariables); err != nil {
+		return domain.Binding{}, err
+	}
+	if !serviceDefinition.AllowedBindingUpdate(requestParams) {
+		return domain.Binding{}, ErrNonUpdatableParameter
+	}
+
+	storedBindRequestDetails, err := broker.store.GetBindRequestDetails(bindingID, instanceID)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error retrieving bind request details for %q: %w", instanceID, err)
+	}
+
+	parsedDetails, err := paramparser.ParseStoredBindRequestDetails(storedBindRequestDetails, plan.ID, serviceDefinition.ID)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error parsing stored bind request details for instance %q: %w", instanceID, err)
+	}
+
+	mergedParams, err := mergeJSON(storedBindRequestDetails.Parameters, requestParams)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error merging update and bind details: %w", err)
+	}
+	parsedDetails.RequestParams = mergedParams
+
+	if len(details.RawContext) > 0 {
+		if err := json.Unmarshal(details.RawContext, &parsedDetails.RequestContext); err != nil {
+			return domain.Binding{}, ErrInvalidUserInput
+		}
+	}
+
+	vars, err := serviceDefinition.BindVariables(instanceRecord, bindingID, parsedDetails, plan, request.DecodeOriginatingIdentityHeader(ctx))
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error generating bind variables: %w", err)
+	}
+
+	credsDetails, err := serviceProvider.UpdateBinding(ctx, vars)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error performing binding update: %w", err)
+	}
+
+	updatedCreds := storage.ServiceBindingCredentials{
+		ServiceInstanceGUID: instanceID,
+		BindingGUID:         bindingID,
+		ServiceGUID:         instanceRecord.ServiceGUID,
+		Credentials:         credsDetails,
+	}
+	if err := broker.store.UpdateServiceBindingCredentials(updatedCreds); err != nil {
+		return domain.Binding{}, fmt.Errorf("error saving credentials to database: %w", err)
+	}
+
+	if err := broker.store.UpdateBindRequestDetails(bindingID, instanceID, mergedParams); err != nil {
+		return domain.Binding{}, fmt.Errorf("error saving bind request details to database: %w", err)
+	}
+
+	binding, err := buildInstanceCredentials(updatedCreds.Credentials, instanceRecord.Outputs)
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("error building credentials: %w", err)
+	}
+
+	binding.Credentials, err = broker.credStore.Save(ctx, computeCredHubPath(broker.getServiceName(serviceDefinition), bindingID), binding.Credentials, paramparser.CredHubActor(storedBindRequestDetails.BindResource))
+	if err != nil {
+		return domain.Binding{}, fmt.Errorf("binding update failure: %w", err)
+	}
+
+	return binding, nil
+}
@@ -0,0 +1,167 @@
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/lager/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	pkgBroker "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	pkgBrokerFakes "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker/brokerfakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+var _ = Describe("UpdateBinding", func() {
+	const (
+		appGUID    = "test-app-guid"
+		planID     = "test-plan-id"
+		offeringID = "test-service-id"
+		instanceID = "test-instance-id"
+		bindingID  = "test-binding-id"
+	)
+
+	var (
+		serviceBroker *broker.ServiceBroker
+		updateDetails domain.BindDetails
+
+		fakeStorage         *brokerfakes.FakeStorage
+		fakeServiceProvider *pkgBrokerFakes.FakeServiceProvider
+		fakeCredStore       *brokerfakes.FakeCredStore
+	)
+
+	BeforeEach(func() {
+		fakeServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+		fakeServiceProvider.UpdateBindingReturns(map[string]any{"fakeOutput": "updatedValue"}, nil)
+
+		fakeStorage = &brokerfakes.FakeStorage{}
+		fakeStorage.ExistsServiceBindingCredentialsReturns(true, nil)
+		fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+			GUID:        instanceID,
+			ServiceGUID: offeringID,
+			PlanGUID:    planID,
+			Outputs:     map[string]any{"fakeInstanceOutput": "fakeInstanceValue"},
+		}, nil)
+		fakeStorage.GetBindRequestDetailsReturns(storage.BindRequestDetails{
+			ServiceInstanceGUID: instanceID,
+			ServiceBindingGUID:  bindingID,
+			BindResource:        storage.JSONObject{"app_guid": appGUID},
+			Parameters:          storage.JSONObject{"role": "reader", "username": "fake-user"},
+		}, nil)
+
+		fakeCredStore = &brokerfakes.FakeCredStore{}
+		fakeCredStore.SaveReturns(map[string]any{"fake-ref": "fake-value"}, nil)
+
+		providerBuilder := func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+			return fakeServiceProvider
+		}
+
+		brokerConfig := &broker.BrokerConfig{
+			Registry: pkgBroker.BrokerRegistry{
+				"test-service": &pkgBroker.ServiceDefinition{
+					ID:   offeringID,
+					Name: "test-service",
+					Plans: []pkgBroker.ServicePlan{
+						{ServicePlan: domain.ServicePlan{ID: planID, Name: "test-plan"}},
+					},
+					BindInputVariables: []pkgBroker.BrokerVariable{
+						{FieldName: "role", Type: "string", Details: "fake role"},
+						{FieldName: "username", Type: "string", Details: "fake username", ProhibitUpdate: true},
+					},
+					ProviderBuilder: providerBuilder,
+				},
+			},
+			CredStore: fakeCredStore,
+		}
+
+		serviceBroker = must(broker.New(brokerConfig, fakeStorage, utils.NewLogger("update-binding-test")))
+
+		updateDetails = domain.BindDetails{
+			PlanID:        planID,
+			ServiceID:     offeringID,
+			RawParameters: json.RawMessage(`{"role":"writer"}`),
+		}
+	})
+
+	It("re-applies the binding with merged parameters and stores the result", func() {
+		response, err := serviceBroker.UpdateBinding(context.TODO(), instanceID, bindingID, updateDetails)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response).To(Equal(domain.Binding{Credentials: map[string]any{"fake-ref": "fake-value"}}))
+
+		By("validating the provider was called with the merged variables")
+		Expect(fakeServiceProvider.UpdateBindingCallCount()).To(Equal(1))
+		_, actualVars := fakeServiceProvider.UpdateBindingArgsForCall(0)
+		Expect(actualVars.GetString("role")).To(Equal("writer"))
+		Expect(actualVars.GetString("username")).To(Equal("fake-user"))
+
+		By("validating the credentials and request details are updated")
+		Expect(fakeStorage.UpdateServiceBindingCredentialsCallCount()).To(Equal(1))
+		Expect(fakeStorage.UpdateServiceBindingCredentialsArgsForCall(0)).To(Equal(storage.ServiceBindingCredentials{
+			ServiceGUID:         offeringID,
+			ServiceInstanceGUID: instanceID,
+			BindingGUID:         bindingID,
+			Credentials:         map[string]any{"fakeOutput": "updatedValue"},
+		}))
+		Expect(fakeStorage.UpdateBindRequestDetailsCallCount()).To(Equal(1))
+		actualBindingID, actualInstanceID, actualParams := fakeStorage.UpdateBindRequestDetailsArgsForCall(0)
+		Expect(actualBindingID).To(Equal(bindingID))
+		Expect(actualInstanceID).To(Equal(instanceID))
+		Expect(actualParams).To(Equal(storage.JSONObject{"role": "writer", "username": "fake-user"}))
+
+		By("validating credstore has been called")
+		Expect(fakeCredStore.SaveCallCount()).To(Equal(1))
+		_, actualPath, actualCred, actualActor := fakeCredStore.SaveArgsForCall(0)
+		Expect(actualPath).To(Equal("/c/csb/test-service/test-binding-id/secrets-and-services"))
+		Expect(actualCred).To(Equal(map[string]any{
+			"fakeInstanceOutput": "fakeInstanceValue",
+			"fakeOutput":         "updatedValue",
+		}))
+		Expect(actualActor).To(Equal("mtls-app:test-app-guid"))
+	})
+
+	When("the binding does not exist", func() {
+		It("returns not found", func() {
+			fakeStorage.ExistsServiceBindingCredentialsReturns(false, nil)
+
+			_, err := serviceBroker.UpdateBinding(context.TODO(), instanceID, bindingID, updateDetails)
+			Expect(err).To(MatchError(broker.ErrNotFound))
+			Expect(fakeServiceProvider.UpdateBindingCallCount()).To(BeZero())
+		})
+	})
+
+	When("a parameter that prohibits update is supplied", func() {
+		It("returns an error", func() {
+			updateDetails.RawParameters = json.RawMessage(`{"username":"other-user"}`)
+
+			_, err := serviceBroker.UpdateBinding(context.TODO(), instanceID, bindingID, updateDetails)
+			Expect(err).To(MatchError(broker.ErrNonUpdatableParameter))
+			Expect(fakeServiceProvider.UpdateBindingCallCount()).To(BeZero())
+		})
+	})
+
+	When("an undefined parameter is supplied", func() {
+		It("returns an error", func() {
+			updateDetails.RawParameters = json.RawMessage(`{"invalid":"value"}`)
+
+			_, err := serviceBroker.UpdateBinding(context.TODO(), instanceID, bindingID, updateDetails)
+			Expect(err).To(MatchError("additional properties are not allowed: invalid"))
+		})
+	})
+
+	When("the provider fails", func() {
+		It("returns an error and does not store credentials", func() {
+			fakeServiceProvider.UpdateBindingReturns(nil, errors.New("boom"))
+
+			_, err := serviceBroker.UpdateBinding(context.TODO(), instanceID, bindingID, updateDetails)
+			Expect(err).To(MatchError("error performing binding update: boom"))
+			Expect(fakeStorage.UpdateServiceBindingCredentialsCallCount()).To(BeZero())
+			Expect(fakeStorage.UpdateBindRequestDetailsCallCount()).To(BeZero())
+		})
+	})
+})
@@ -496,6 +496,63 @@ var _ = Describe("Update", func() {
 		})
 	})
 
+	Describe("pinned definition versions", func() {
+		var fakePinnedServiceProvider *pkgBrokerFakes.FakeServiceProvider
+
+		BeforeEach(func() {
+			fakePinnedServiceProvider = &pkgBrokerFakes.FakeServiceProvider{}
+
+			current := serviceBroker.Registry()["test-service"]
+			pinned := *current
+			pinned.Version = "1.0.0"
+			pinned.ProviderBuilder = func(logger lager.Logger, store pkgBroker.ServiceProviderStorage) pkgBroker.ServiceProvider {
+				return fakePinnedServiceProvider
+			}
+			current.Version = "1.1.0"
+			current.PreviousVersions = map[string]*pkgBroker.ServiceDefinition{"1.0.0": &pinned}
+
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+				GUID:              instanceID,
+				ServiceGUID:       offeringID,
+				PlanGUID:          originalPlanID,
+				SpaceGUID:         spaceID,
+				OrganizationGUID:  orgID,
+				DefinitionVersion: "1.0.0",
+			}, nil)
+		})
+
+		It("updates the instance with the definition it is pinned to", func() {
+			_, err := serviceBroker.Update(context.TODO(), instanceID, updateDetails, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePinnedServiceProvider.UpdateCallCount()).To(Equal(1))
+			Expect(fakeServiceProvider.UpdateCallCount()).To(Equal(0))
+
+			Expect(fakeStorage.StoreServiceInstanceDetailsCallCount()).To(Equal(1))
+			Expect(fakeStorage.StoreServiceInstanceDetailsArgsForCall(0).DefinitionVersion).To(Equal("1.0.0"))
+		})
+
+		It("moves the instance onto the current definition when it is upgraded", func() {
+			fakeServiceProvider.UpgradeInstanceReturns(&sync.WaitGroup{}, nil)
+			fakeStorage.GetTerraformDeploymentReturns(storage.TerraformDeployment{
+				ID:                 updateOperationID,
+				LastOperationType:  models.UpgradeOperationType,
+				LastOperationState: tf.InProgress,
+			}, nil)
+
+			updateDetails.PreviousValues.MaintenanceInfo = &domain.MaintenanceInfo{Version: "1.0.0"}
+
+			_, err := serviceBroker.Update(context.TODO(), instanceID, updateDetails, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeServiceProvider.UpgradeInstanceCallCount()).To(Equal(1))
+			Expect(fakePinnedServiceProvider.UpgradeInstanceCallCount()).To(Equal(0))
+
+			Eventually(fakeStorage.StoreServiceInstanceDetailsCallCount).Should(Equal(1))
+			Expect(fakeStorage.StoreServiceInstanceDetailsArgsForCall(0).DefinitionVersion).To(Equal("1.1.0"))
+		})
+	})
+
 	Describe("instance context variables", func() {
 		Describe("passing variables on provision and update", func() {
 			BeforeEach(func() {
@@ -626,6 +683,26 @@ var _ = Describe("Update", func() {
 			})
 		})
 
+		Describe("updating parameter with a value that does not match the update schema", func() {
+			It("should error", func() {
+				updateDetails = domain.UpdateDetails{
+					ServiceID: offeringID,
+					PlanID:    originalPlanID,
+					PreviousValues: domain.PreviousValues{
+						PlanID:    originalPlanID,
+						ServiceID: offeringID,
+						OrgID:     orgID,
+						SpaceID:   spaceID,
+					},
+					RawParameters: json.RawMessage(`{"foo":42}`),
+				}
+
+				_, err := serviceBroker.Update(context.TODO(), instanceID, updateDetails, true)
+				Expect(err).To(MatchError("1 error(s) occurred: foo: Invalid type. Expected: string, given: integer"))
+				Expect(fakeServiceProvider.UpdateCallCount()).To(BeZero())
+			})
+		})
+
 		Describe("updating parameter that is not defined in the service definition", func() {
 			It("should error", func() {
 				u := domain.UpdateDetails{
@@ -740,6 +817,23 @@ var _ = Describe("Update", func() {
 		})
 	})
 
+	When("the instance has been released", func() {
+		BeforeEach(func() {
+			fakeStorage.GetServiceInstanceDetailsReturns(storage.ServiceInstanceDetails{
+				GUID:        instanceID,
+				ServiceGUID: offeringID,
+				PlanGUID:    originalPlanID,
+				Released:    true,
+			}, nil)
+		})
+
+		It("should error", func() {
+			_, err := serviceBroker.Update(context.TODO(), instanceID, updateDetails, true)
+			Expect(err).To(MatchError(broker.ErrInstanceReleased))
+			Expect(fakeServiceProvider.UpdateCallCount()).To(BeZero())
+		})
+	})
+
 	When("request json is invalid", func() {
 		It("should error", func() {
 			updateDetails := domain.UpdateDetails{
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"github.com/spf13/cobra"
+
+	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+func init() {
+	var (
+		service          string
+		plan             string
+		spaceGUID        string
+		organizationGUID string
+		params           string
+		resources        []string
+	)
+
+	adoptCmd := &cobra.Command{
+		Use:     "adopt <instance-guid>",
+		GroupID: "broker",
+		Short:   "create a service instance from existing resources",
+		Long: `Lets you bring existing IaaS resources under the management of the Cloud Service Broker as a new service instance.
+
+The resources are imported into the normal provision template of the service, using the parameters given, in the
+same way as "tofu import". Each resource is given as an address in the provision template and the ID of the resource
+in the IaaS, for example:
+
+  cloud-service-broker adopt <instance-guid> --service csb-aws-s3-bucket --plan default \
+    --params '{"bucket_name":"my-bucket"}' --resource aws_s3_bucket.bucket=my-bucket
+
+Once imported, a plan must show that the resources already match the provision template with the given parameters,
+otherwise the adopt fails and nothing is changed in the IaaS. The instance is then registered in the database as if it
+had been provisioned, with the outputs of the provision template. The broker configuration is used to load the
+brokerpaks, and the parameters are validated and subject to quotas as for a provision.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			details := domain.ProvisionDetails{
+				SpaceGUID:        spaceGUID,
+				OrganizationGUID: organizationGUID,
+				RawParameters:    json.RawMessage(params),
+			}
+			adoptServiceInstance(args[0], service, plan, details, resources)
+		},
+	}
+
+	adoptCmd.Flags().StringVar(&service, "service", "", "name or ID of the service")
+	adoptCmd.Flags().StringVar(&plan, "plan", "", "name or ID of the plan")
+	adoptCmd.Flags().StringVar(&spaceGUID, "space-guid", "", "GUID of the space of the service instance")
+	adoptCmd.Flags().StringVar(&organizationGUID, "organization-guid", "", "GUID of the organization of the service instance")
+	adoptCmd.Flags().StringVarP(&params, "params", "c", "{}", "provision parameters as a JSON object")
+	adoptCmd.Flags().StringArrayVar(&resources, "resource", nil, "resource to import, as <address>=<id> (may be repeated)")
+	_ = adoptCmd.MarkFlagRequired("service")
+	_ = adoptCmd.MarkFlagRequired("plan")
+	_ = adoptCmd.MarkFlagRequired("resource")
+
+	rootCmd.AddCommand(adoptCmd)
+}
+
+func adoptServiceInstance(guid, service, plan string, details domain.ProvisionDetails, resourceFlags []string) {
+	resources, err := parseAdoptResources(resourceFlags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := utils.NewLogger("adopt")
+	db := dbservice.New(logger)
+	encryptor := setupDBEncryption(db, logger)
+	store := storage.New(db, encryptor)
+
+	cfg, err := osbapiBroker.NewBrokerConfigFromEnv(logger)
+	if err != nil {
+		log.Fatalf("error initializing service broker config: %s", err)
+	}
+	serviceBroker, err := osbapiBroker.New(cfg, store, logger)
+	if err != nil {
+		log.Fatalf("error initializing service broker: %s", err)
+	}
+
+	details.ServiceID, details.PlanID, err = findServiceAndPlan(cfg.Registry, service, plan)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("importing %d resource(s) into instance %s", len(resources), guid)
+	if err := serviceBroker.Adopt(context.Background(), guid, details, resources); err != nil {
+		log.Fatalf("error adopting resources: %s. The instance was not registered", err)
+	}
+	log.Printf("adopted instance %s", guid)
+}
+
+// parseAdoptResources parses resources in the form <address>=<id>
+func parseAdoptResources(flags []string) (map[string]string, error) {
+	resources := make(map[string]string)
+	for _, flag := range flags {
+		address, id, ok := strings.Cut(flag, "=")
+		if !ok || address == "" || id == "" {
+			return nil, fmt.Errorf("invalid resource %q, expected <address>=<id>", flag)
+		}
+		if _, ok := resources[address]; ok {
+			return nil, fmt.Errorf("resource %q is specified more than once", address)
+		}
+		resources[address] = id
+	}
+	return resources, nil
+}
+
+// findServiceAndPlan finds the IDs of a service and plan that are given either by name or by ID
+func findServiceAndPlan(registry broker.BrokerRegistry, service, plan string) (string, string, error) {
+	for _, defn := range registry.GetAllServices() {
+		if defn.ID != service && defn.Name != service {
+			continue
+		}
+
+		for _, p := range defn.Plans {
+			if p.ID == plan || p.Name == plan {
+				return defn.ID, p.ID, nil
+			}
+		}
+		return "", "", fmt.Errorf("plan %q not found for service %q", plan, defn.Name)
+	}
+
+	return "", "", fmt.Errorf("service %q not found", service)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
+	pakBroker "github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/server"
+)
+
+const reloadCleanupInterval = 30 * time.Second
+
+// brokerpakReloader replaces the service definitions of a running broker with those of the
+// brokerpaks currently configured, along with the docs, examples and SBOMs generated from them.
+type brokerpakReloader struct {
+	mu       sync.Mutex
+	broker   *osbapiBroker.ServiceBroker
+	docs     atomic.Pointer[http.HandlerFunc]
+	examples atomic.Pointer[http.HandlerFunc]
+	sbom     atomic.Pointer[http.HandlerFunc]
+	logger   lager.Logger
+
+	// operationsInFlight reports whether any OpenTofu operations are running
+	operationsInFlight func() bool
+}
+
+var _ server.BrokerpakReloader = (*brokerpakReloader)(nil)
+
+func newBrokerpakReloader(serviceBroker *osbapiBroker.ServiceBroker, operationsInFlight func() bool, logger lager.Logger) *brokerpakReloader {
+	r := &brokerpakReloader{
+		broker:             serviceBroker,
+		logger:             logger.Session("reload-brokerpaks"),
+		operationsInFlight: operationsInFlight,
+	}
+	r.storeHandlers(serviceBroker.Registry())
+	return r
+}
+
+// ReloadBrokerpaks reads the configuration into a new Viper instance, then fetches and validates the brokerpaks.
+// The global configuration is never modified, as requests read it concurrently. Instead, the services are bound
+// to the new configuration, and the registry is only swapped once it has been fully loaded, so a failure leaves
+// the current catalog and configuration in place. Only the brokerpak and service configuration is reloaded.
+func (r *brokerpakReloader) ReloadBrokerpaks() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, err := newConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := osbapiBroker.NewRegistryFromConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := r.broker.Registry()
+	released, err := r.broker.SwapRegistry(registry)
+	if err != nil {
+		r.removePluginDirs(registry)
+		return nil, err
+	}
+	r.storeHandlers(registry)
+	go r.removePluginDirsWhenIdle(previous, released)
+
+	var names []string
+	for _, svc := range registry.GetAllServices() {
+		names = append(names, svc.Name)
+	}
+	return names, nil
+}
+
+// removePluginDirsWhenIdle removes the directories that the binaries of the previous registry were in, once
+// they are no longer used. Requests that started with the previous registry keep using it, so it waits until
+// they have finished, by which time any operations that they started hold a lock file, and then waits until
+// no operations hold a lock file.
+func (r *brokerpakReloader) removePluginDirsWhenIdle(registry pakBroker.BrokerRegistry, released <-chan struct{}) {
+	<-released
+	for r.operationsInFlight() {
+		time.Sleep(reloadCleanupInterval)
+	}
+	r.removePluginDirs(registry)
+}
+
+// removePluginDirs removes the directories that the binaries of a registry's brokerpaks were extracted to
+func (r *brokerpakReloader) removePluginDirs(registry pakBroker.BrokerRegistry) {
+	for _, dir := range pluginDirs(registry) {
+		if err := os.RemoveAll(dir); err != nil {
+			r.logger.Error("remove-plugin-dir", err, lager.Data{"dir": dir})
+		}
+	}
+}
+
+func pluginDirs(registry pakBroker.BrokerRegistry) []string {
+	var dirs []string
+	add := func(svc *pakBroker.ServiceDefinition) {
+		if svc.Brokerpak != nil && svc.Brokerpak.PluginDir != "" && !slices.Contains(dirs, svc.Brokerpak.PluginDir) {
+			dirs = append(dirs, svc.Brokerpak.PluginDir)
+		}
+	}
+
+	for _, svc := range registry {
+		add(svc)
+		for _, previous := range svc.PreviousVersions {
+			add(previous)
+		}
+	}
+	return dirs
+}
+
+func (r *brokerpakReloader) storeHandlers(registry pakBroker.BrokerRegistry) {
+	docs := server.DocsHandler(registry)
+	examples := server.NewExampleHandler(registry)
+	sbom := server.NewSBOMHandler(registry)
+	r.docs.Store(&docs)
+	r.examples.Store(&examples)
+	r.sbom.Store(&sbom)
+}
+
+func (r *brokerpakReloader) serveDocs(w http.ResponseWriter, req *http.Request) {
+	(*r.docs.Load())(w, req)
+}
+
+func (r *brokerpakReloader) serveExamples(w http.ResponseWriter, req *http.Request) {
+	(*r.examples.Load())(w, req)
+}
+
+func (r *brokerpakReloader) serveSBOM(w http.ResponseWriter, req *http.Request) {
+	(*r.sbom.Load())(w, req)
+}
+
+// listenForReloadSignal reloads the brokerpaks each time the process receives SIGHUP
+func (r *brokerpakReloader) listenForReloadSignal() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	for range sigChan {
+		services, err := r.ReloadBrokerpaks()
+		if err != nil {
+			r.logger.Error("failed", err)
+			continue
+		}
+		r.logger.Info("reloaded", lager.Data{"services": services})
+	}
+}
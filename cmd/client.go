@@ -40,6 +40,8 @@ var (
 	exampleName     string
 	fileName        string
 	exampleJobCount int
+	exampleReports  client.ReportPaths
+	previousPort    int
 )
 
 func init() {
@@ -140,6 +142,9 @@ user-defined plans.
 		Long: `Run all examples generated by the use command through a
 	provision/bind/unbind/deprovision cycle.
 
+	Examples that upgrade the brokerpak run against the broker on --previous-port
+	until they upgrade, and are skipped when it is not given.
+
 	Exits with a 0 if all examples were successful, 1 otherwise.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			apiClient, err := client.NewClientFromEnv()
@@ -147,13 +152,21 @@ user-defined plans.
 				log.Fatalf("Error creating client: %v", err)
 			}
 
+			var previousClient *client.Client
+			if previousPort != 0 {
+				previousClient, err = client.NewClientFromEnvWithPort(previousPort)
+				if err != nil {
+					log.Fatalf("Error creating client for the previous broker: %v", err)
+				}
+			}
+
 			switch {
 			case exampleName != "" && serviceName == "":
 				log.Fatalf("If an example name is specified, you must provide an accompanying service name.")
 			case fileName != "":
-				client.RunExamplesFromFile(apiClient, fileName, serviceName, exampleName)
+				client.RunExamplesFromFile(apiClient, previousClient, fileName, serviceName, exampleName, exampleReports)
 			default:
-				client.RunExamplesForService(server.GetExamplesFromServer(), apiClient, serviceName, exampleName, exampleJobCount)
+				client.RunExamplesForService(server.GetExamplesFromServer(), apiClient, previousClient, serviceName, exampleName, exampleJobCount, exampleReports)
 			}
 		},
 	}
@@ -185,6 +198,9 @@ user-defined plans.
 	runExamplesCmd.Flags().StringVarP(&exampleName, "example-name", "", "", "only run examples matching this name")
 	runExamplesCmd.Flags().StringVarP(&fileName, "filename", "", "", "json file that contains list of CompleteServiceExamples")
 	runExamplesCmd.Flags().IntVarP(&exampleJobCount, "jobs", "j", 1, "number of parallel client examples to run concurrently")
+	runExamplesCmd.Flags().StringVarP(&exampleReports.JUnit, "junit-report", "", "", "file to write a JUnit XML report of the examples to")
+	runExamplesCmd.Flags().StringVarP(&exampleReports.JSON, "json-report", "", "", "file to write a JSON report of the examples to")
+	runExamplesCmd.Flags().IntVarP(&previousPort, "previous-port", "", 0, "port of a broker serving the previous version of the brokerpak, used by examples that upgrade")
 }
 
 func newClientCommand(use, short string, run func(*client.Client) *client.BrokerResponse) *cobra.Command {
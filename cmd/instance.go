@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/instancebundle"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+// bundlePassphraseEnvVar is the environment variable that the passphrase of an instance bundle is read from,
+// so that it does not show up in the process list or shell history
+const bundlePassphraseEnvVar = "CSB_BUNDLE_PASSPHRASE"
+
+func init() {
+	var store *storage.Storage
+
+	instanceCmd := &cobra.Command{
+		Use:     "instance",
+		GroupID: "broker",
+		Short:   "Move service instances between brokers",
+		Long: `Move service instances and their bindings between brokers that use different databases,
+without recreating their resources. The bundle is encrypted with a passphrase that is read from the
+` + bundlePassphraseEnvVar + ` environment variable, and the same passphrase must be used to import it.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			logger := utils.NewLogger("instance")
+			db := dbservice.New(logger)
+			encryptor := setupDBEncryption(db, logger)
+			store = storage.New(db, encryptor)
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	rootCmd.AddCommand(instanceCmd)
+
+	var release bool
+	exportCmd := &cobra.Command{
+		Use:   "export <instance-guid> <bundle-file>",
+		Short: "export a service instance and its bindings to an encrypted bundle",
+		Long: `Writes the service instance details, provision request details, binding credentials and bind request
+details, and the Terraform workspaces of the instance and its bindings, to an encrypted bundle that can be
+imported by another broker. No operation may be in progress for the instance or its bindings.
+
+With --release, the instance is also marked as released, so that deleting it from this broker does not destroy
+the resources that are now managed by the other broker.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			exportInstanceBundle(store, args[0], args[1], release)
+		},
+	}
+	exportCmd.Flags().BoolVar(&release, "release", false, "mark the instance as released so that deleting it does not destroy the resources")
+	instanceCmd.AddCommand(exportCmd)
+
+	var (
+		instanceGUID     string
+		organizationGUID string
+		spaceGUID        string
+		bindingGUIDs     map[string]string
+	)
+	importCmd := &cobra.Command{
+		Use:   "import <bundle-file>",
+		Short: "import a service instance and its bindings from an encrypted bundle",
+		Long: `Writes the records in a bundle that was exported by another broker to the database of this broker,
+encrypted with the database encryption key of this broker. The instance and bindings keep their GUIDs unless
+they are remapped, for example when the platform has created the instance with a new GUID. When the target
+platform has different organizations and spaces, set the organization and space of the instance. The import fails
+without writing anything if the instance or any of its Terraform workspaces already exist.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			importInstanceBundle(store, args[0], instancebundle.GUIDs{
+				Instance:     instanceGUID,
+				Organization: organizationGUID,
+				Space:        spaceGUID,
+				Bindings:     bindingGUIDs,
+			})
+		},
+	}
+	importCmd.Flags().StringVar(&instanceGUID, "instance-guid", "", "GUID to import the service instance with")
+	importCmd.Flags().StringVar(&organizationGUID, "organization-guid", "", "GUID of the organization of the service instance on this platform")
+	importCmd.Flags().StringVar(&spaceGUID, "space-guid", "", "GUID of the space of the service instance on this platform")
+	importCmd.Flags().StringToStringVar(&bindingGUIDs, "binding-guid", nil, "GUID to import a binding with, as <exported-guid>=<new-guid> (may be repeated)")
+	instanceCmd.AddCommand(importCmd)
+}
+
+func exportInstanceBundle(store *storage.Storage, instanceGUID, dest string, release bool) {
+	passphrase := bundlePassphrase()
+
+	bundle, err := instancebundle.Export(store, instanceGUID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	contents, err := instancebundle.Encrypt(bundle, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(dest, contents, 0o600); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("exported service instance %q with %d binding(s) to %q", instanceGUID, len(bundle.Bindings), dest)
+
+	if release {
+		releaseServiceInstance(store, bundle.Instance)
+	}
+}
+
+func importInstanceBundle(store *storage.Storage, source string, guids instancebundle.GUIDs) {
+	passphrase := bundlePassphrase()
+
+	contents, err := os.ReadFile(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bundle, err := instancebundle.Decrypt(contents, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := instancebundle.Import(store, bundle, guids); err != nil {
+		log.Fatal(err)
+	}
+
+	instanceGUID := bundle.Instance.GUID
+	if guids.Instance != "" {
+		instanceGUID = guids.Instance
+	}
+	log.Printf("imported service instance %q with %d binding(s) from %q", instanceGUID, len(bundle.Bindings), source)
+}
+
+func bundlePassphrase() string {
+	passphrase := os.Getenv(bundlePassphraseEnvVar)
+	if passphrase == "" {
+		log.Fatalf("the passphrase of the bundle must be set in the %s environment variable", bundlePassphraseEnvVar)
+	}
+	return passphrase
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/local"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/client"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -13,6 +14,8 @@ const localID = "local"
 func init() {
 	var params, plan, service, example string
 	var all bool
+	var reports client.ReportPaths
+	var previousBrokerpak string
 
 	rootCmd.AddGroup(&cobra.Group{
 		ID:    localID,
@@ -171,11 +174,14 @@ func init() {
 			if !all && service == "" && example == "" {
 				log.Fatalln("specify --service-name and/or --example-name, or --all to run all the tests")
 			}
-			local.RunExamples(service, example, viper.GetString(pakCachePath))
+			local.RunExamples(service, example, viper.GetString(pakCachePath), previousBrokerpak, reports)
 		},
 	}
 	runExamplesCmd.Flags().StringVarP(&service, serviceFlag, "s", "", "service offering name")
 	runExamplesCmd.Flags().StringVarP(&example, exampleFlag, "e", "", "example test name")
 	runExamplesCmd.Flags().BoolVarP(&all, allFlag, "a", false, "run all tests")
+	runExamplesCmd.Flags().StringVar(&reports.JUnit, "junit-report", "", "file to write a JUnit XML report of the tests to")
+	runExamplesCmd.Flags().StringVar(&reports.JSON, "json-report", "", "file to write a JSON report of the tests to")
+	runExamplesCmd.Flags().StringVar(&previousBrokerpak, "previous-brokerpak", "", "previous version of the brokerpak, for tests that upgrade")
 	rootCmd.AddCommand(runExamplesCmd)
 }
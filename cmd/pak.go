@@ -80,6 +80,24 @@ dependencies, services it provides, and the contents.
 
 	cloud-service-broker pak info my-pak.brokerpak
 
+The CycloneDX software bill of materials bundled in the pack can be printed with:
+
+	cloud-service-broker pak info --sbom my-pak.brokerpak
+
+Before releasing a new version of a pack, you can review what changed since the
+previous version, including changes that break existing service instances:
+
+	cloud-service-broker pak diff my-pak-1.0.0.brokerpak my-pak-1.1.0.brokerpak
+
+To fail a release pipeline on changes that break existing service instances:
+
+	cloud-service-broker pak check-compat my-pak-1.1.0.brokerpak --against my-pak-1.0.0.brokerpak
+
+A pack can be published to an OCI registry, and then loaded by the broker from an
+oci:// source:
+
+	cloud-service-broker pak push my-pak.brokerpak oci://registry.example.com/paks/my-pak:1.0.0
+
 `,
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
@@ -107,6 +125,7 @@ dependencies, services it provides, and the contents.
 		includeSourceFlag = "include-source"
 		targetFlag        = "target"
 		compressFlag      = "compress"
+		signingKeyFlag    = "signing-key"
 	)
 	buildCmd := &cobra.Command{
 		Use:   "build [path/to/pack/directory]",
@@ -131,11 +150,22 @@ dependencies, services it provides, and the contents.
 				log.Fatalf("error while obtaining the %q flag: %s", targetFlag, err)
 			}
 
+			signingKey, err := cmd.Flags().GetString(signingKeyFlag)
+			if err != nil {
+				log.Fatalf("error while obtaining the %q flag: %s", signingKeyFlag, err)
+			}
+
 			pakPath, err := brokerpak.Pack(directory, viper.GetString(pakCachePath), includeSource, compress, platform.Parse(target))
 			if err != nil {
 				log.Fatalf("error while packing %q: %v", directory, err)
 			}
 
+			if signingKey != "" {
+				if err := brokerpak.Sign(pakPath, signingKey); err != nil {
+					log.Fatalf("error while signing %q: %v", pakPath, err)
+				}
+			}
+
 			if err := brokerpak.Validate(pakPath); err != nil {
 				log.Fatalf("created: %v, but it failed validity checking: %v\n", pakPath, err)
 			} else {
@@ -146,17 +176,99 @@ dependencies, services it provides, and the contents.
 	buildCmd.Flags().BoolP(includeSourceFlag, "s", false, "include source in the brokerpak")
 	buildCmd.Flags().Bool(compressFlag, true, "compress the brokerpak")
 	buildCmd.Flags().StringP(targetFlag, "t", "", "target specified platform; format 'darwin/amd64'; or special case 'current'")
+	buildCmd.Flags().String(signingKeyFlag, "", "sign the brokerpak with the PEM encoded ed25519 private key in this file")
 	pakCmd.AddCommand(buildCmd)
 
-	pakCmd.AddCommand(&cobra.Command{
+	infoCmd := &cobra.Command{
 		Use:   "info [pack.brokerpak]",
 		Short: "get info about a brokerpak",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := brokerpak.Info(args[0]); err != nil {
+			sbom, err := cmd.Flags().GetBool("sbom")
+			if err != nil {
+				log.Fatalf("error while obtaining the %q flag: %s", "sbom", err)
+			}
+
+			info := brokerpak.Info
+			if sbom {
+				info = brokerpak.SBOM
+			}
+
+			if err := info(args[0]); err != nil {
 				log.Fatalf("error getting info for %q: %v", args[0], err)
 			}
 		},
+	}
+	infoCmd.Flags().Bool("sbom", false, "print the CycloneDX software bill of materials of the brokerpak")
+	pakCmd.AddCommand(infoCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff [old.brokerpak] [new.brokerpak]",
+		Short: "report the differences between two brokerpaks",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			jsonOutput, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				log.Fatalf("error while obtaining the %q flag: %s", "json", err)
+			}
+
+			if err := brokerpak.Diff(args[0], args[1], jsonOutput); err != nil {
+				log.Fatalf("error comparing %q and %q: %v", args[0], args[1], err)
+			}
+		},
+	}
+	diffCmd.Flags().Bool("json", false, "output the differences as JSON")
+	pakCmd.AddCommand(diffCmd)
+
+	checkCompatCmd := &cobra.Command{
+		Use:   "check-compat [pack.brokerpak]",
+		Short: "check that a brokerpak does not break instances created with a previous brokerpak",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			against, err := cmd.Flags().GetString("against")
+			if err != nil {
+				log.Fatalf("error while obtaining the %q flag: %s", "against", err)
+			}
+
+			if err := brokerpak.CheckCompat(args[0], against); err != nil {
+				log.Fatalf("Error: %v\n", err)
+			}
+			log.Println("Compatible")
+		},
+	}
+	checkCompatCmd.Flags().String("against", "", "the previous brokerpak that existing service instances were created with")
+	_ = checkCompatCmd.MarkFlagRequired("against")
+	pakCmd.AddCommand(checkCompatCmd)
+
+	pakCmd.AddCommand(&cobra.Command{
+		Use:   "mirror [pack.brokerpak] [directory]",
+		Short: "write the providers in a brokerpak as an OpenTofu provider mirror",
+		Long: `Writes the providers bundled in a brokerpak, for all of its platforms, to the directory
+as an OpenTofu provider mirror. The directory can be used as a filesystem mirror, or
+served over HTTPS as a network mirror. A SHA256SUMS file and a .terraformrc file that
+installs the providers from the mirror are written to the root of the directory, so
+that tofu can be run by hand with exactly the providers that the broker uses.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := brokerpak.Mirror(args[0], args[1]); err != nil {
+				log.Fatalf("error while mirroring %q: %v", args[0], err)
+			}
+		},
+	})
+
+	pakCmd.AddCommand(&cobra.Command{
+		Use:   "push [pack.brokerpak] [oci://registry/repository:tag]",
+		Short: "publish a brokerpak to an OCI registry",
+		Long: `Publishes a brokerpak to an OCI registry as an artifact, and prints the reference
+with the digest of the published brokerpak. The digest can be used in brokerpak.sources
+to pin the exact brokerpak. Credentials for the registry are read from the
+brokerpak.registry_credentials configuration value.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := brokerpak.Push(args[0], args[1]); err != nil {
+				log.Fatalf("error while pushing %q: %v", args[0], err)
+			}
+		},
 	})
 
 	pakCmd.AddCommand(&cobra.Command{
@@ -172,6 +284,26 @@ dependencies, services it provides, and the contents.
 		},
 	})
 
+	verifyCmd := &cobra.Command{
+		Use:   "verify [pack.brokerpak]",
+		Short: "verify the signature of a brokerpak",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			publicKeys, err := cmd.Flags().GetString("public-keys")
+			if err != nil {
+				log.Fatalf("error while obtaining the %q flag: %s", "public-keys", err)
+			}
+
+			if err := brokerpak.Verify(args[0], publicKeys); err != nil {
+				log.Fatalf("Error: %v\n", err)
+			}
+			log.Println("Signature verified")
+		},
+	}
+	verifyCmd.Flags().String("public-keys", "", "file containing one or more PEM encoded ed25519 public keys to trust")
+	_ = verifyCmd.MarkFlagRequired("public-keys")
+	pakCmd.AddCommand(verifyCmd)
+
 	pakCmd.AddCommand(&cobra.Command{
 		Use:   "run-examples [pack.brokerpak]",
 		Short: "run the examples from a brokerpak",
@@ -49,23 +49,30 @@ func purgeServiceInstance(serviceInstanceGUID string) {
 	encryptor := setupDBEncryption(db, logger)
 	store := storage.New(db, encryptor)
 
+	if err := deleteServiceInstanceFromStore(store, serviceInstanceGUID); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("deleted instance %s from the Cloud Service Broker database", serviceInstanceGUID)
+}
+
+func deleteServiceInstanceFromStore(store *storage.Storage, serviceInstanceGUID string) error {
 	bindings, err := store.GetServiceBindingIDsForServiceInstance(serviceInstanceGUID)
 	if err != nil {
-		log.Fatalf("error listing bindings: %s", err)
+		return fmt.Errorf("error listing bindings: %w", err)
 	}
 	for _, bindingGUID := range bindings {
 		if err := deleteServiceBindingFromStore(store, serviceInstanceGUID, bindingGUID); err != nil {
-			log.Fatalf("error deleting binding %q for service instance %q: %s", bindingGUID, serviceInstanceGUID, err)
+			return fmt.Errorf("error deleting binding %q for service instance %q: %w", bindingGUID, serviceInstanceGUID, err)
 		}
 	}
 	if err := store.DeleteProvisionRequestDetails(serviceInstanceGUID); err != nil {
-		log.Fatalf("error deleting provision request details for %q: %s", serviceInstanceGUID, err)
+		return fmt.Errorf("error deleting provision request details for %q: %w", serviceInstanceGUID, err)
 	}
 	if err := store.DeleteServiceInstanceDetails(serviceInstanceGUID); err != nil {
-		log.Fatalf("error deleting service instance details for %q: %s", serviceInstanceGUID, err)
+		return fmt.Errorf("error deleting service instance details for %q: %w", serviceInstanceGUID, err)
 	}
 	if err := store.DeleteTerraformDeployment(fmt.Sprintf("tf:%s:", serviceInstanceGUID)); err != nil {
-		log.Fatalf("error deleting service terraform deployment for %q: %s", serviceInstanceGUID, err)
+		return fmt.Errorf("error deleting service terraform deployment for %q: %w", serviceInstanceGUID, err)
 	}
-	log.Printf("deleted instance %s from the Cloud Service Broker database", serviceInstanceGUID)
+	return nil
 }
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/lager/v3"
+	"github.com/spf13/cobra"
+
+	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/reconcile"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+const reconcilePollInterval = 10 * time.Second
+
+func init() {
+	var (
+		platformGUIDsFile string
+		purge             bool
+		destroy           bool
+		yes               bool
+		allowUnmatched    bool
+	)
+
+	reconcileCmd := &cobra.Command{
+		Use:     "reconcile",
+		GroupID: "broker",
+		Short:   "compare the service instances in the database with those on the platform",
+		Long: `Lets you find service instances that are known to either the Cloud Service Broker or the platform, but not both.
+
+The GUIDs of the service instances on the platform are read from a file, or from stdin, separated by whitespace.
+Lines starting with "#" are ignored. Only the service instances of this broker should be listed.
+
+If using Cloud Foundry, the GUIDs can be listed with:
+
+  cf curl "/v3/service_instances?service_plan_guids=<plan-guids>&per_page=5000" | jq -r '.resources[].guid' > platform-guids.txt
+  cloud-service-broker reconcile --platform-guids platform-guids.txt
+
+Service instances that only exist in the Cloud Service Broker database can be removed with either:
+
+  --purge    remove all references from the database, leaving any resources in the IaaS
+  --destroy  unbind and deprovision the instance, deleting the resources in the IaaS
+
+Service instances that only exist on the platform are reported, but never changed.
+
+As a safeguard against a wrong or truncated list of platform GUIDs, nothing is purged or destroyed when the list
+is empty, or when it matches none of the service instances in the database, unless --allow-unmatched is specified.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if purge && destroy {
+				log.Fatal("only one of --purge and --destroy may be specified")
+			}
+
+			platformGUIDs, err := readPlatformGUIDs(platformGUIDsFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if (purge || destroy) && !yes && usesStdin(platformGUIDsFile) {
+				log.Fatal("--yes must be specified when the platform service instance GUIDs are read from stdin")
+			}
+
+			reconcileServiceInstances(platformGUIDs, purge, destroy, yes, allowUnmatched)
+		},
+	}
+
+	reconcileCmd.Flags().StringVarP(&platformGUIDsFile, "platform-guids", "f", "-", `file listing the platform service instance GUIDs, or "-" for stdin`)
+	reconcileCmd.Flags().BoolVar(&purge, "purge", false, "remove service instances that are not known to the platform from the database")
+	reconcileCmd.Flags().BoolVar(&destroy, "destroy", false, "deprovision service instances that are not known to the platform")
+	reconcileCmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation before purging or destroying")
+	reconcileCmd.Flags().BoolVar(&allowUnmatched, "allow-unmatched", false, "purge or destroy even when the platform GUIDs are empty or match none of the broker's service instances")
+
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+func reconcileServiceInstances(platformGUIDs []string, purge, destroy, yes, allowUnmatched bool) {
+	logger := utils.NewLogger("reconcile")
+	db := dbservice.New(logger)
+	encryptor := setupDBEncryption(db, logger)
+	store := storage.New(db, encryptor)
+
+	brokerGUIDs, err := store.GetServiceInstancesIDs()
+	if err != nil {
+		log.Fatalf("error listing service instances: %s", err)
+	}
+
+	report := reconcile.Compare(brokerGUIDs, platformGUIDs)
+	printReconcileReport(os.Stdout, report)
+
+	if len(report.BrokerOrphans) == 0 || (!purge && !destroy) {
+		return
+	}
+
+	if err := reconcile.CheckOverlap(brokerGUIDs, platformGUIDs); err != nil && !allowUnmatched {
+		log.Fatalf("refusing to remove service instances: %s; specify --allow-unmatched if this is intended", err)
+	}
+
+	action := "Purge"
+	if destroy {
+		action = "Destroy"
+	}
+	if !yes && !confirm(fmt.Sprintf("%s the %d service instance(s) only known to the broker?", action, len(report.BrokerOrphans))) {
+		log.Print("no changes made")
+		return
+	}
+
+	if purge {
+		for _, guid := range report.BrokerOrphans {
+			if err := deleteServiceInstanceFromStore(store, guid); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("deleted instance %s from the Cloud Service Broker database", guid)
+		}
+		return
+	}
+
+	cfg, err := osbapiBroker.NewBrokerConfigFromEnv(logger)
+	if err != nil {
+		log.Fatalf("error initializing service broker config: %s", err)
+	}
+	serviceBroker, err := osbapiBroker.New(cfg, store, logger)
+	if err != nil {
+		log.Fatalf("error initializing service broker: %s", err)
+	}
+
+	var failed int
+	for _, guid := range report.BrokerOrphans {
+		if err := destroyServiceInstance(context.Background(), serviceBroker, store, guid, logger); err != nil {
+			log.Printf("error destroying instance %s: %s", guid, err)
+			failed++
+			continue
+		}
+		log.Printf("destroyed instance %s", guid)
+	}
+	if failed > 0 {
+		log.Fatalf("failed to destroy %d service instance(s)", failed)
+	}
+}
+
+// destroyServiceInstance unbinds and deprovisions an instance in the same way that the platform would, waiting
+// for the deprovision to complete. The broker removes the instance from the database once it has succeeded.
+func destroyServiceInstance(ctx context.Context, serviceBroker *osbapiBroker.ServiceBroker, store *storage.Storage, guid string, logger lager.Logger) error {
+	instance, err := store.GetServiceInstanceDetails(guid)
+	if err != nil {
+		return fmt.Errorf("error getting service instance details: %w", err)
+	}
+
+	bindings, err := store.GetServiceBindingIDsForServiceInstance(guid)
+	if err != nil {
+		return fmt.Errorf("error listing bindings: %w", err)
+	}
+	for _, bindingGUID := range bindings {
+		unbindDetails := domain.UnbindDetails{ServiceID: instance.ServiceGUID, PlanID: instance.PlanGUID}
+		if _, err := serviceBroker.Unbind(ctx, guid, bindingGUID, unbindDetails, false); err != nil {
+			return fmt.Errorf("error unbinding %q: %w", bindingGUID, err)
+		}
+	}
+
+	deprovisionDetails := domain.DeprovisionDetails{ServiceID: instance.ServiceGUID, PlanID: instance.PlanGUID}
+	spec, err := serviceBroker.Deprovision(ctx, guid, deprovisionDetails, true)
+	switch {
+	case err != nil:
+		return fmt.Errorf("error deprovisioning: %w", err)
+	case !spec.IsAsync:
+		return nil
+	}
+
+	pollDetails := domain.PollDetails{ServiceID: instance.ServiceGUID, PlanID: instance.PlanGUID, OperationData: spec.OperationData}
+	return waitForLastOperation(ctx, serviceBroker, guid, pollDetails, "deprovision", logger)
+}
+
+// waitForLastOperation polls the last operation of a service instance in the same way that the platform would,
+// until it has succeeded or failed
+func waitForLastOperation(ctx context.Context, serviceBroker *osbapiBroker.ServiceBroker, guid string, pollDetails domain.PollDetails, operation string, logger lager.Logger) error {
+	for {
+		time.Sleep(reconcilePollInterval)
+
+		lastOperation, err := serviceBroker.LastOperation(ctx, guid, pollDetails)
+		switch {
+		case err != nil:
+			return fmt.Errorf("error polling %s: %w", operation, err)
+		case lastOperation.State == domain.Failed:
+			return fmt.Errorf("%s failed: %s", operation, lastOperation.Description)
+		case lastOperation.State == domain.Succeeded:
+			return nil
+		default:
+			logger.Info("waiting-for-"+operation, lager.Data{"instance_id": guid, "message": lastOperation.Description})
+		}
+	}
+}
+
+func printReconcileReport(w io.Writer, report reconcile.Report) {
+	_, _ = fmt.Fprintf(w, "Service instances only known to the broker: %d\n", len(report.BrokerOrphans))
+	for _, guid := range report.BrokerOrphans {
+		_, _ = fmt.Fprintf(w, "  %s\n", guid)
+	}
+	_, _ = fmt.Fprintf(w, "Service instances only known to the platform: %d\n", len(report.PlatformOrphans))
+	for _, guid := range report.PlatformOrphans {
+		_, _ = fmt.Fprintf(w, "  %s\n", guid)
+	}
+}
+
+func readPlatformGUIDs(path string) ([]string, error) {
+	if usesStdin(path) {
+		return reconcile.ReadGUIDs(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening platform service instance GUIDs file: %w", err)
+	}
+	defer f.Close()
+
+	return reconcile.ReadGUIDs(f)
+}
+
+func usesStdin(path string) bool {
+	return path == "" || path == "-"
+}
+
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N]: ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/brokerpak"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
 )
 
@@ -61,6 +62,24 @@ func init() {
 	viper.AutomaticEnv()
 }
 
+// newConfig reads the configuration into a new Viper instance that is set up like the global one
+func newConfig() (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvPrefix(utils.EnvironmentVarPrefix)
+	v.SetEnvKeyReplacer(utils.PropertyToEnvReplacer)
+	v.AutomaticEnv()
+	brokerpak.SetConfigDefaults(v)
+
+	if cfgFile != "" {
+		v.SetConfigFile(cfgFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading config: %w", err)
+		}
+	}
+
+	return v, nil
+}
+
 func initConfig() {
 	if cfgFile == "" {
 		return
@@ -35,6 +35,7 @@ import (
 	"code.cloudfoundry.org/lager/v3"
 	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/credhubrepo"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/displaycatalog"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/encryption"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/infohandler"
@@ -62,6 +63,9 @@ const (
 	encryptionEnabled   = "db.encryption.enabled"
 
 	shutdownTimeout = time.Hour
+
+	readinessCheckTimeout        = 2 * time.Second
+	credHubReadinessCheckTimeout = 5 * time.Second
 )
 
 var cfCompatibilityToggle = toggles.Features.Toggle("enable-cf-sharing", false, `Set all services to have the Sharable flag so they can be shared
@@ -101,7 +105,11 @@ func serve() {
 	logger := utils.NewLogger("cloud-service-broker")
 	logger.Info("starting", lager.Data{"version": utils.Version})
 	db := dbservice.NewWithMigrations(logger)
-	encryptor := setupDBEncryption(db, logger)
+	encryptor, encryptionLabel := setupDBEncryptionWithLabel(db, logger)
+	migrationLevel, err := dbservice.LastMigration(db)
+	if err != nil {
+		logger.Fatal("Error reading database migration level", err)
+	}
 
 	// init broker
 	cfg, err := osbapiBroker.NewBrokerConfigFromEnv(logger)
@@ -115,10 +123,11 @@ func serve() {
 		logger.Fatal("Error recovering in-progress operations", err)
 	}
 
-	serviceBroker, err = osbapiBroker.New(cfg, csbStore, logger)
+	csbBroker, err := osbapiBroker.New(cfg, csbStore, logger)
 	if err != nil {
 		logger.Fatal("Error initializing service broker", err)
 	}
+	serviceBroker = csbBroker
 
 	credentials := brokerapi.BrokerCredentials{
 		Username: viper.GetString(apiUserProp),
@@ -146,7 +155,43 @@ func serve() {
 	if err != nil {
 		logger.Error("failed to get database connection", err)
 	}
-	httpServer := startServer(cfg.Registry, sqldb, brokerAPI, csbStore, credentials)
+	reloader := newBrokerpakReloader(csbBroker, csbStore.LockFilesExist, logger)
+	go reloader.listenForReloadSignal()
+
+	_, credHubDisabled := cfg.CredStore.(osbapiBroker.NoopCredStore)
+	brokerpaks := func() []pakBroker.Brokerpak { return csbBroker.Registry().Brokerpaks() }
+	info := infohandler.Config{
+		Brokerpaks:     brokerpaks,
+		FeatureToggles: toggles.Features,
+		Storage: &infohandler.Storage{
+			DatabaseType:    db.Dialector.Name(),
+			MigrationLevel:  migrationLevel,
+			EncryptionLabel: encryptionLabel,
+			CredHubEnabled:  !credHubDisabled,
+		},
+	}
+
+	readinessChecks := []server.ReadinessCheck{
+		server.TofuBinariesCheck(brokerpaks, readinessCheckTimeout),
+		server.PluginDirsCheck(brokerpaks, readinessCheckTimeout),
+		// Any file in the lock file directory is taken to be the lock of an operation, so it is not written to
+		server.FreeSpaceCheck("lock-file-dir", csbStore.LockFileDir(), readinessCheckTimeout),
+	}
+	if credHub, ok := cfg.CredStore.(*credhubrepo.Repo); ok {
+		readinessChecks = append(readinessChecks, server.PingCheck("credhub", credHub.Ping, credHubReadinessCheckTimeout))
+	}
+
+	httpServer := startServer(serverOptions{
+		registry:        cfg.Registry,
+		db:              sqldb,
+		brokerAPI:       brokerAPI,
+		bindingUpdater:  csbBroker,
+		reloader:        reloader,
+		store:           csbStore,
+		credentials:     credentials,
+		info:            info,
+		readinessChecks: readinessChecks,
+	})
 
 	listenForShutdownSignal(httpServer, logger, csbStore)
 }
@@ -159,10 +204,21 @@ func serveDocs() {
 		logger.Error("loading brokerpaks", err)
 	}
 
-	startServer(registry, nil, nil, nil, brokerapi.BrokerCredentials{})
+	info := infohandler.Config{
+		Brokerpaks:     registry.Brokerpaks,
+		FeatureToggles: toggles.Features,
+	}
+
+	startServer(serverOptions{registry: registry, info: info})
 }
 
 func setupDBEncryption(db *gorm.DB, logger lager.Logger) storage.Encryptor {
+	encryptor, _ := setupDBEncryptionWithLabel(db, logger)
+	return encryptor
+}
+
+// setupDBEncryptionWithLabel is setupDBEncryption that also returns the label of the primary encryption password
+func setupDBEncryptionWithLabel(db *gorm.DB, logger lager.Logger) (storage.Encryptor, string) {
 	config, err := encryption.ParseConfiguration(db, viper.GetBool(encryptionEnabled), viper.Get(encryptionPasswords))
 	if err != nil {
 		logger.Fatal("Error parsing encryption configuration", err)
@@ -200,27 +256,57 @@ func setupDBEncryption(db *gorm.DB, logger lager.Logger) storage.Encryptor {
 	}
 
 	logger.Info("database-encryption", lager.Data{"primary": labelName(config.ConfiguredPrimaryLabel)})
-	return config.Encryptor
+	return config.Encryptor, labelName(config.ConfiguredPrimaryLabel)
 }
 
-func startServer(registry pakBroker.BrokerRegistry, db *sql.DB, brokerapi http.Handler, store *storage.Storage, credentials brokerapi.BrokerCredentials) *http.Server {
-	logger := utils.NewLogger("cloud-service-broker")
+// serverOptions are what the HTTP server serves. When serving only the docs, the fields other than the
+// registry and info are left empty.
+type serverOptions struct {
+	registry        pakBroker.BrokerRegistry
+	db              *sql.DB
+	brokerAPI       http.Handler
+	bindingUpdater  server.BindingUpdater
+	reloader        *brokerpakReloader
+	store           *storage.Storage
+	credentials     brokerapi.BrokerCredentials
+	info            infohandler.Config
+	readinessChecks []server.ReadinessCheck
+}
 
-	docsHandler := server.DocsHandler(registry)
+func startServer(opts serverOptions) *http.Server {
+	logger := utils.NewLogger("cloud-service-broker")
+	credentials := opts.credentials
+
+	docsHandler := server.DocsHandler(opts.registry)
+	examplesHandler := server.NewExampleHandler(opts.registry)
+	sbomHandler := server.NewSBOMHandler(opts.registry)
+	if opts.reloader != nil {
+		docsHandler = opts.reloader.serveDocs
+		examplesHandler = opts.reloader.serveExamples
+		sbomHandler = opts.reloader.serveSBOM
+	}
 
 	router := http.NewServeMux()
 	router.Handle("/docs", docsHandler)
-	router.HandleFunc("/examples", server.NewExampleHandler(registry))
-	server.AddHealthHandler(router, db)
-	router.HandleFunc("/info", infohandler.NewDefault())
-	router.Handle("/import_state/{guid}", auth.NewWrapper(credentials.Username, credentials.Password).Wrap(importStateHandler(store)))
+	router.HandleFunc("/examples", examplesHandler)
+	server.AddHealthHandler(router, opts.db, opts.readinessChecks...)
+	router.HandleFunc("/info", infohandler.NewDefault(opts.info))
+	router.HandleFunc("/info/sbom", sbomHandler)
+	router.Handle("/import_state/{guid}", auth.NewWrapper(credentials.Username, credentials.Password).Wrap(importStateHandler(opts.store)))
+	router.Handle("POST /release/{guid}", auth.NewWrapper(credentials.Username, credentials.Password).Wrap(releaseHandler(opts.store)))
+	if opts.reloader != nil {
+		router.Handle("POST /brokerpaks/reload", auth.NewWrapper(credentials.Username, credentials.Password).Wrap(server.NewBrokerpakReloadHandler(opts.reloader, logger)))
+	}
+	if opts.bindingUpdater != nil {
+		router.Handle("PATCH /v2/service_instances/{instance_id}/service_bindings/{binding_id}", auth.NewWrapper(credentials.Username, credentials.Password).Wrap(server.NewBindingUpdateHandler(opts.bindingUpdater, logger)))
+	}
 
 	router.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
 		switch {
 		case req.URL.Path == "/":
 			docsHandler.ServeHTTP(res, req)
-		case strings.HasPrefix(req.URL.Path, "/v2") && brokerapi != nil:
-			brokerapi.ServeHTTP(res, req)
+		case strings.HasPrefix(req.URL.Path, "/v2") && opts.brokerAPI != nil:
+			opts.brokerAPI.ServeHTTP(res, req)
 		default:
 			http.NotFound(res, req)
 		}
@@ -327,6 +413,40 @@ func importStateHandler(store *storage.Storage) http.Handler {
 	})
 }
 
+// releaseHandler marks a service instance as released, so that deleting it or its bindings retains
+// the resources instead of destroying them
+func releaseHandler(store *storage.Storage) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guid := r.PathValue("guid")
+		if err := uuid.Validate(guid); err != nil {
+			http.Error(w, "not a valid GUID", http.StatusBadRequest)
+			return
+		}
+
+		exists, err := store.ExistsServiceInstanceDetails(guid)
+		switch {
+		case err != nil:
+			http.Error(w, fmt.Sprintf("failed to find service instance: %s", err), http.StatusInternalServerError)
+			return
+		case !exists:
+			http.Error(w, fmt.Sprintf("could not find service instance: %s", guid), http.StatusNotFound)
+			return
+		}
+
+		instance, err := store.GetServiceInstanceDetails(guid)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read service instance: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		instance.Released = true
+		if err := store.StoreServiceInstanceDetails(instance); err != nil {
+			http.Error(w, fmt.Sprintf("failed to store service instance: %s", err), http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
 func labelName(label string) string {
 	switch label {
 	case "":
@@ -15,20 +15,25 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	osbapiBroker "github.com/cloudfoundry/cloud-service-broker/v2/brokerapi/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/offboard"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/invoker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
 )
 
@@ -68,11 +73,24 @@ func init() {
 		Use:   "dump",
 		Short: "dump a Terraform workspace",
 		Run: func(cmd *cobra.Command, args []string) {
-			deployment, err := store.GetTerraformDeployment(args[0])
+			var ws *workspace.TerraformWorkspace
+			retained, err := cmd.Flags().GetBool("retained")
 			if err != nil {
 				log.Fatal(err)
 			}
-			ws := deployment.TFWorkspace()
+			if retained {
+				deployment, err := store.GetRetainedDeployment(args[0])
+				if err != nil {
+					log.Fatal(err)
+				}
+				ws = deployment.TFWorkspace()
+			} else {
+				deployment, err := store.GetTerraformDeployment(args[0])
+				if err != nil {
+					log.Fatal(err)
+				}
+				ws = deployment.TFWorkspace()
+			}
 
 			onlyState, err := cmd.Flags().GetBool("only-state")
 			if err != nil {
@@ -88,8 +106,45 @@ func init() {
 		},
 	}
 	dumpCmd.Flags().BoolP("only-state", "s", false, "dump the tf state file")
+	dumpCmd.Flags().Bool("retained", false, "dump a workspace that was retained when its service instance or binding was deleted")
 	tfCmd.AddCommand(dumpCmd)
 
+	var shell string
+	shellCmd := &cobra.Command{
+		Use:   "shell <deployment-id>",
+		Short: "open a shell in a Terraform workspace",
+		Long: `Writes the modules, variables and state of a Terraform workspace to a temporary directory,
+and initializes it with the OpenTofu binary and providers from the brokerpak that the service
+instance uses, with the same environment as the broker. It then opens a shell in the directory
+so that tofu can be run by hand. When the shell exits, if the state was changed you are asked
+whether to write it back to the database. The brokerpaks are loaded from the broker configuration.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			openTerraformShell(store, args[0], shell)
+		},
+	}
+	shellCmd.Flags().StringVar(&shell, "shell", defaultShell(), "the shell to open in the workspace directory")
+	tfCmd.AddCommand(shellCmd)
+
+	var release bool
+	exportCmd := &cobra.Command{
+		Use:   "export <instance-guid> <archive.zip>",
+		Short: "export the Terraform workspaces of a service instance",
+		Long: `Writes the modules, variables and state of a service instance and its bindings to a zip archive,
+as a Terraform project that can be used to manage the resources outside the broker. The workspace of the
+instance is at the root of the archive, and the workspace of each binding is under bindings/<binding-guid>.
+
+With --release, the instance is also marked as released. Deleting a released instance or its bindings only
+removes them from the broker database, and does not destroy the resources. A released instance cannot be
+updated or bound to.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			exportServiceInstance(store, args[0], args[1], release)
+		},
+	}
+	exportCmd.Flags().BoolVar(&release, "release", false, "mark the instance as released so that deleting it does not destroy the resources")
+	tfCmd.AddCommand(exportCmd)
+
 	tfCmd.AddCommand(&cobra.Command{
 		Use:   "wait",
 		Short: "wait for a Terraform job",
@@ -101,6 +156,49 @@ func init() {
 		},
 	})
 
+	var deleteRetained string
+	retainedCmd := &cobra.Command{
+		Use:   "retained",
+		Short: "show the list of Terraform workspaces retained when their resources were not destroyed",
+		Long: `Lists the Terraform workspaces that were retained when their service instance or binding was deleted.
+
+With --delete, the retained workspace is removed from the database instead. Do this once the resources have
+been imported elsewhere or destroyed, as the broker no longer has any record of them afterwards.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if deleteRetained != "" {
+				if _, err := store.GetRetainedDeployment(deleteRetained); err != nil {
+					log.Fatal(err)
+				}
+				if err := store.DeleteRetainedDeployment(deleteRetained); err != nil {
+					log.Fatal(err)
+				}
+				log.Printf("deleted retained workspace %q", deleteRetained)
+				return
+			}
+
+			results, err := store.GetRetainedDeployments()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.StripEscape)
+			_, _ = fmt.Fprintln(w, "ID\tInstance\tVersion\tRetained")
+
+			for _, result := range results {
+				stateVersion := ""
+				if v, err := result.TFWorkspace().StateTFVersion(); err == nil {
+					stateVersion = v.String()
+				}
+
+				_, _ = fmt.Fprintf(w, "%q\t%s\t%s\t%s\n", result.ID, result.ServiceInstanceGUID, stateVersion, result.RetainedAt.Format(time.RFC822))
+			}
+			_ = w.Flush()
+		},
+	}
+	retainedCmd.Flags().StringVar(&deleteRetained, "delete", "", "delete the retained workspace with this ID instead of listing them")
+	tfCmd.AddCommand(retainedCmd)
+
 	tfCmd.AddCommand(&cobra.Command{
 		Use:   "list",
 		Short: "show the list of Terraform workspaces",
@@ -127,3 +225,182 @@ func init() {
 		},
 	})
 }
+
+// shellProvider is implemented by service providers that can open a shell in a workspace
+type shellProvider interface {
+	Shell(ctx context.Context, deploymentID string, terminal executor.Terminal) (*workspace.TerraformWorkspace, error)
+}
+
+func openTerraformShell(store *storage.Storage, deploymentID, shell string) {
+	instanceGUID, ok := instanceGUIDFromDeploymentID(deploymentID)
+	if !ok {
+		log.Fatalf("invalid deployment ID %q, expected tf:<instance-guid>:[<binding-guid>]", deploymentID)
+	}
+
+	deployment, err := store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	instance, err := store.GetServiceInstanceDetails(instanceGUID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	registry, err := osbapiBroker.NewRegistryFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defn, err := registry.GetServiceByID(instance.ServiceGUID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defn = defn.ForVersion(instance.DefinitionVersion)
+
+	provider, ok := defn.ProviderBuilder(utils.NewLogger("tf-shell"), store).(shellProvider)
+	if !ok {
+		log.Fatalf("service %q does not support opening a shell", defn.Name)
+	}
+
+	fmt.Printf("Opening %s in workspace %q of service %q, brokerpak version %q. Exit the shell when finished.\n", shell, deploymentID, defn.Name, defn.Version)
+	ws, err := provider.Shell(context.Background(), deploymentID, executor.Terminal{
+		Shell:  shell,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if bytes.Equal(ws.State, deployment.TFWorkspace().State) {
+		log.Print("the state was not changed")
+		return
+	}
+
+	if !confirm(fmt.Sprintf("The state of workspace %q was changed. Write it to the database?", deploymentID)) {
+		log.Print("the changed state was discarded")
+		return
+	}
+
+	// The broker may have run an operation on the deployment while the shell was open, so only the state is
+	// written, and only when the deployment is as it was when the shell was opened
+	current, err := store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch changed, err := deploymentChanged(deployment, current); {
+	case err != nil:
+		log.Fatal(err)
+	case current.LastOperationState == tf.InProgress:
+		log.Fatalf("an operation is in progress for deployment %q, the changed state was discarded", deploymentID)
+	case changed:
+		log.Fatalf("deployment %q was changed while the shell was open, the changed state was discarded", deploymentID)
+	}
+
+	tfw := current.TFWorkspace()
+	tfw.State = ws.State
+	current.Workspace = tfw
+	if err := store.StoreTerraformDeployment(current); err != nil {
+		log.Fatal(err)
+	}
+	log.Print("the changed state was written to the database")
+}
+
+// deploymentChanged compares the workspace and last operation of two reads of a deployment
+func deploymentChanged(before, after storage.TerraformDeployment) (bool, error) {
+	if before.LastOperationType != after.LastOperationType ||
+		before.LastOperationState != after.LastOperationState ||
+		before.LastOperationMessage != after.LastOperationMessage {
+		return true, nil
+	}
+
+	beforeWorkspace, err := before.TFWorkspace().Serialize()
+	if err != nil {
+		return false, err
+	}
+	afterWorkspace, err := after.TFWorkspace().Serialize()
+	if err != nil {
+		return false, err
+	}
+	return beforeWorkspace != afterWorkspace, nil
+}
+
+func exportServiceInstance(store *storage.Storage, instanceGUID, dest string, release bool) {
+	instance, err := store.GetServiceInstanceDetails(instanceGUID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	deployment, err := store.GetTerraformDeployment(fmt.Sprintf("tf:%s:", instanceGUID))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if deployment.LastOperationState == tf.InProgress {
+		log.Fatalf("an operation is in progress for service instance %q", instanceGUID)
+	}
+
+	bindingGUIDs, err := store.GetServiceBindingIDsForServiceInstance(instanceGUID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	project := offboard.Project{
+		InstanceGUID: instanceGUID,
+		ServiceGUID:  instance.ServiceGUID,
+		PlanGUID:     instance.PlanGUID,
+		Instance:     deployment.TFWorkspace(),
+		Bindings:     make(map[string]*workspace.TerraformWorkspace),
+	}
+	for _, bindingGUID := range bindingGUIDs {
+		bindingDeployment, err := store.GetTerraformDeployment(fmt.Sprintf("tf:%s:%s", instanceGUID, bindingGUID))
+		if err != nil {
+			log.Fatal(err)
+		}
+		project.Bindings[bindingGUID] = bindingDeployment.TFWorkspace()
+	}
+
+	if err := offboard.Write(project, dest); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("exported service instance %q with %d binding(s) to %q", instanceGUID, len(bindingGUIDs), dest)
+
+	if release {
+		releaseServiceInstance(store, instance)
+	}
+}
+
+// releaseServiceInstance asks for confirmation, then marks the instance as released so that deleting it
+// does not destroy its resources
+func releaseServiceInstance(store *storage.Storage, instance storage.ServiceInstanceDetails) {
+	if instance.Released {
+		return
+	}
+
+	if !confirm(fmt.Sprintf("Mark service instance %q as released? Deleting it will no longer destroy its resources.", instance.GUID)) {
+		log.Print("the service instance was not released")
+		return
+	}
+
+	instance.Released = true
+	if err := store.StoreServiceInstanceDetails(instance); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("released service instance %q", instance.GUID)
+}
+
+func instanceGUIDFromDeploymentID(deploymentID string) (string, bool) {
+	parts := strings.Split(deploymentID, ":")
+	if len(parts) != 3 || parts[0] != "tf" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func defaultShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
@@ -23,7 +23,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
 )
 
-const numMigrations = 18
+const numMigrations = 21
 
 // RunMigrations runs schema migrations on the provided service broker database to get it up to date
 func RunMigrations(db *gorm.DB) error {
@@ -148,15 +148,21 @@ func RunMigrations(db *gorm.DB) error {
 		return db.Migrator().AddColumn(&models.BindRequestDetailsV2{}, "bind_resource")
 	}
 
-	var lastMigrationNumber = -1
+	migrations[18] = func() error {
+		return db.Migrator().AddColumn(&models.ServiceInstanceDetailsV5{}, "definition_version")
+	}
 
-	// if we've run any migrations before, we should have a migrations table, so find the last one we ran
-	if db.Migrator().HasTable("migrations") {
-		var storedMigrations []models.Migration
-		if err := db.Order("migration_id desc").Find(&storedMigrations).Error; err != nil {
-			return fmt.Errorf("error getting last migration id even though migration table exists: %s", err)
-		}
-		lastMigrationNumber = storedMigrations[0].MigrationID
+	migrations[19] = func() error {
+		return db.Migrator().AddColumn(&models.ServiceInstanceDetailsV6{}, "released")
+	}
+
+	migrations[20] = func() error {
+		return autoMigrateTables(db, &models.RetainedDeploymentV1{})
+	}
+
+	lastMigrationNumber, err := LastMigration(db)
+	if err != nil {
+		return err
 	}
 
 	if err := ValidateLastMigration(lastMigrationNumber); err != nil {
@@ -187,6 +193,25 @@ func RunMigrations(db *gorm.DB) error {
 	return nil
 }
 
+// LastMigration returns the number of the last schema migration that was run on the database,
+// or -1 if no migrations have been run
+func LastMigration(db *gorm.DB) (int, error) {
+	// if we've run any migrations before, we should have a migrations table, so find the last one we ran
+	if !db.Migrator().HasTable("migrations") {
+		return -1, nil
+	}
+
+	var storedMigrations []models.Migration
+	if err := db.Order("migration_id desc").Limit(1).Find(&storedMigrations).Error; err != nil {
+		return 0, fmt.Errorf("error getting last migration id even though migration table exists: %s", err)
+	}
+	if len(storedMigrations) == 0 {
+		return -1, nil
+	}
+
+	return storedMigrations[0].MigrationID, nil
+}
+
 // ValidateLastMigration returns an error if the database version is newer than
 // this tool supports or is too old to be updated.
 func ValidateLastMigration(lastMigration int) error {
@@ -75,6 +75,13 @@ var _ = Describe("Migrations", func() {
 			Expect(lastMigrationNumber).To(Equal(numMigrations - 1))
 		})
 
+		It("reports the last migration", func() {
+			Expect(LastMigration(db)).To(Equal(-1))
+
+			Expect(RunMigrations(db)).To(Succeed())
+			Expect(LastMigration(db)).To(Equal(numMigrations - 1))
+		})
+
 		It("can run migrations multiple times", func() {
 			for range 10 {
 				Expect(RunMigrations(db)).To(Succeed())
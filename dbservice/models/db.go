@@ -20,13 +20,14 @@ package models
 // and will exist on a ServiceInstanceDetails with an operation ID that can be
 // used to look up the state of an operation.
 const (
-	ProvisionOperationType   = "provision"
-	DeprovisionOperationType = "deprovision"
-	UpdateOperationType      = "update"
-	UpgradeOperationType     = "upgrade"
-	BindOperationType        = "bind"
-	UnbindOperationType      = "unbind"
-	ClearOperationType       = ""
+	ProvisionOperationType     = "provision"
+	DeprovisionOperationType   = "deprovision"
+	UpdateOperationType        = "update"
+	UpgradeOperationType       = "upgrade"
+	BindOperationType          = "bind"
+	UnbindOperationType        = "unbind"
+	UpdateBindingOperationType = "update binding"
+	ClearOperationType         = ""
 )
 
 // ServiceBindingCredentials holds credentials returned to the users after
@@ -34,7 +35,7 @@ const (
 type ServiceBindingCredentials ServiceBindingCredentialsV2
 
 // ServiceInstanceDetails holds information about provisioned services.
-type ServiceInstanceDetails ServiceInstanceDetailsV4
+type ServiceInstanceDetails ServiceInstanceDetailsV6
 
 // ProvisionRequestDetails holds user-defined properties passed to a call
 // to provision a service.
@@ -56,6 +57,10 @@ type CloudOperation CloudOperationV1
 // that use that execution system.
 type TerraformDeployment TerraformDeploymentV3
 
+// RetainedDeployment holds the workspace of a Terraform deployment whose resources were
+// retained when it was deleted.
+type RetainedDeployment RetainedDeploymentV1
+
 // PasswordMetadata contains information about the passwords, but never the
 // passwords themselves
 type PasswordMetadata PasswordMetadataV1
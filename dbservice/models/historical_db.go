@@ -185,6 +185,61 @@ func (ServiceInstanceDetailsV4) TableName() string {
 	return "service_instance_details"
 }
 
+// ServiceInstanceDetailsV5 holds information about provisioned services.
+type ServiceInstanceDetailsV5 struct {
+	ID        string `gorm:"primary_key;type:varchar(255);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+
+	Name         string
+	OtherDetails []byte `gorm:"type:blob"`
+
+	ServiceID        string
+	PlanID           string
+	SpaceGUID        string
+	OrganizationGUID string
+
+	// DefinitionVersion is the version of the brokerpak whose service definition the instance uses
+	DefinitionVersion string
+}
+
+// TableName returns a consistent table name for
+// gorm so multiple structs from different versions of the database all operate
+// on the same table.
+func (ServiceInstanceDetailsV5) TableName() string {
+	return "service_instance_details"
+}
+
+// ServiceInstanceDetailsV6 holds information about provisioned services.
+type ServiceInstanceDetailsV6 struct {
+	ID        string `gorm:"primary_key;type:varchar(255);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+
+	Name         string
+	OtherDetails []byte `gorm:"type:blob"`
+
+	ServiceID        string
+	PlanID           string
+	SpaceGUID        string
+	OrganizationGUID string
+
+	// DefinitionVersion is the version of the brokerpak whose service definition the instance uses
+	DefinitionVersion string
+
+	// Released is set once the resources of the instance have been handed over to be managed outside the broker
+	Released bool
+}
+
+// TableName returns a consistent table name for
+// gorm so multiple structs from different versions of the database all operate
+// on the same table.
+func (ServiceInstanceDetailsV6) TableName() string {
+	return "service_instance_details"
+}
+
 // ProvisionRequestDetailsV1 holds user-defined properties passed to a call
 // to provision a service.
 type ProvisionRequestDetailsV1 struct {
@@ -442,3 +497,21 @@ type PasswordMetadataV1 struct {
 func (PasswordMetadataV1) TableName() string {
 	return "password_metadata"
 }
+
+// RetainedDeploymentV1 holds the workspace of a Terraform deployment whose resources were retained
+// when the service instance or binding was deleted, so that they can be imported again later
+type RetainedDeploymentV1 struct {
+	ID        string `gorm:"primary_key;type:varchar(1024)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	ServiceInstanceID string `gorm:"index"`
+
+	// Workspace contains a JSON serialized version of the Terraform workspace, encrypted in the same way as
+	// the workspace of a Terraform deployment.
+	Workspace []byte `gorm:"type:mediumblob"`
+}
+
+func (RetainedDeploymentV1) TableName() string {
+	return "retained_deployments"
+}
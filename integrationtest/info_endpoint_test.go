@@ -38,6 +38,12 @@ var _ = Describe("Info Endpoint", func() {
 		Expect(data).To(SatisfyAll(
 			HaveKeyWithValue("version", utils.Version),
 			HaveKeyWithValue("uptime", Not(BeEmpty())),
+			HaveKeyWithValue("brokerpaks", HaveLen(1)),
+			HaveKeyWithValue("feature_flags", Not(BeEmpty())),
+			HaveKeyWithValue("database", HaveKeyWithValue("type", "sqlite3")),
+			HaveKeyWithValue("encryption", HaveKeyWithValue("label", "none")),
+			HaveKeyWithValue("credhub", HaveKeyWithValue("enabled", false)),
 		))
+		Expect(resp).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
 	})
 })
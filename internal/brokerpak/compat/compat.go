@@ -0,0 +1,162 @@
+// Package compat checks that a brokerpak can replace a previous version without breaking existing service instances
+package compat
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/diff"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+)
+
+// Result holds the outcome of the rules. Errors are changes that break existing service instances,
+// and Warnings are changes that need attention but do not break instances.
+type Result struct {
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// Compatible is true when no rule found a breaking change
+func (r Result) Compatible() bool {
+	return len(r.Errors) == 0
+}
+
+// rule checks a service from the previous brokerpak against the service with the same ID in the new brokerpak
+type rule func(previous, current tf.TfServiceDefinitionV1) []string
+
+var rules = []rule{
+	plansRetained,
+	optionalInputsStayOptional,
+	noNewRequiredInputs,
+	bindComputedOutputsAvailable,
+}
+
+var instanceDetailsReference = regexp.MustCompile(`instance\.details\["([^"]+)"\]`)
+
+// Check applies the compatibility rules to the services in the previous and the new brokerpak.
+// Services are matched by ID, because that is what existing service instances refer to.
+func Check(previous, current diff.Brokerpak) (result Result) {
+	currentByID := make(map[string]tf.TfServiceDefinitionV1)
+	for _, svc := range current.Services {
+		currentByID[svc.ID] = svc
+	}
+
+	for _, prev := range previous.Services {
+		cur, ok := currentByID[prev.ID]
+		if !ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("service %q (%s) has been removed or its ID has changed", prev.Name, prev.ID))
+			continue
+		}
+
+		for _, r := range rules {
+			result.Errors = append(result.Errors, r(prev, cur)...)
+		}
+		result.Warnings = append(result.Warnings, maintenanceInfoWarnings(previous, current, prev, cur)...)
+	}
+
+	return result
+}
+
+func plansRetained(previous, current tf.TfServiceDefinitionV1) (errs []string) {
+	currentIDs := make(map[string]struct{})
+	for _, p := range current.Plans {
+		currentIDs[p.ID] = struct{}{}
+	}
+
+	for _, p := range previous.Plans {
+		if _, ok := currentIDs[p.ID]; !ok {
+			errs = append(errs, fmt.Sprintf("service %q: plan %q (%s) has been removed or its ID has changed", current.Name, p.Name, p.ID))
+		}
+	}
+	return errs
+}
+
+func optionalInputsStayOptional(previous, current tf.TfServiceDefinitionV1) (errs []string) {
+	check := func(action string, previousInputs, currentInputs []broker.BrokerVariable) {
+		previousByName := inputsByName(previousInputs)
+		for _, input := range currentInputs {
+			if prev, ok := previousByName[input.FieldName]; ok && !prev.Required && input.Required {
+				errs = append(errs, fmt.Sprintf("service %q: %s input %q was optional and is now required", current.Name, action, input.FieldName))
+			}
+		}
+	}
+
+	check("provision", previous.ProvisionSettings.UserInputs, current.ProvisionSettings.UserInputs)
+	check("bind", previous.BindSettings.UserInputs, current.BindSettings.UserInputs)
+	return errs
+}
+
+func noNewRequiredInputs(previous, current tf.TfServiceDefinitionV1) (errs []string) {
+	previousByName := inputsByName(previous.ProvisionSettings.UserInputs)
+	for _, input := range current.ProvisionSettings.UserInputs {
+		if _, ok := previousByName[input.FieldName]; !ok && input.Required {
+			errs = append(errs, fmt.Sprintf("service %q: new provision input %q is required, but existing instances do not have a value for it", current.Name, input.FieldName))
+		}
+	}
+	return errs
+}
+
+func bindComputedOutputsAvailable(previous, current tf.TfServiceDefinitionV1) (errs []string) {
+	previousOutputs := inputsByName(previous.ProvisionSettings.Outputs)
+	currentOutputs := inputsByName(current.ProvisionSettings.Outputs)
+
+	for _, computed := range current.BindSettings.Computed {
+		expression, ok := computed.Default.(string)
+		if !ok {
+			continue
+		}
+
+		for _, match := range instanceDetailsReference.FindAllStringSubmatch(expression, -1) {
+			output := match[1]
+			_, inPrevious := previousOutputs[output]
+			_, inCurrent := currentOutputs[output]
+
+			switch {
+			case !inPrevious:
+				errs = append(errs, fmt.Sprintf("service %q: bind computed input %q uses provision output %q, which existing instances do not have", current.Name, computed.Name, output))
+			case !inCurrent:
+				errs = append(errs, fmt.Sprintf("service %q: provision output %q has been removed, but is used by bind computed input %q", current.Name, output, computed.Name))
+			}
+		}
+	}
+	return errs
+}
+
+// maintenanceInfoWarnings flags changes to the provision template or its inputs that existing instances only pick up
+// when they are upgraded. The maintenance_info version is the default OpenTofu version, so unless that changes
+// the platform will not offer an upgrade.
+func maintenanceInfoWarnings(previousPak, currentPak diff.Brokerpak, previous, current tf.TfServiceDefinitionV1) (warnings []string) {
+	previousTofu, err := previousPak.Manifest.DefaultTerraformVersion()
+	if err != nil {
+		return nil
+	}
+	currentTofu, err := currentPak.Manifest.DefaultTerraformVersion()
+	if err != nil || !previousTofu.Equal(currentTofu) {
+		return nil
+	}
+
+	templateChanged := previous.ProvisionSettings.Template != current.ProvisionSettings.Template ||
+		!maps.Equal(previous.ProvisionSettings.Templates, current.ProvisionSettings.Templates)
+	inputsChanged := !slices.Equal(inputNames(previous.ProvisionSettings.PlanInputs), inputNames(current.ProvisionSettings.PlanInputs)) ||
+		!slices.Equal(inputNames(previous.ProvisionSettings.UserInputs), inputNames(current.ProvisionSettings.UserInputs))
+
+	if templateChanged || inputsChanged {
+		warnings = append(warnings, fmt.Sprintf("service %q: the provision template or its inputs changed, but the default OpenTofu version is still %s so maintenance_info will not change and existing instances will not be offered an upgrade", current.Name, currentTofu))
+	}
+	return warnings
+}
+
+func inputsByName(inputs []broker.BrokerVariable) map[string]broker.BrokerVariable {
+	result := make(map[string]broker.BrokerVariable)
+	for _, input := range inputs {
+		result[input.FieldName] = input
+	}
+	return result
+}
+
+func inputNames(inputs []broker.BrokerVariable) []string {
+	return slices.Sorted(maps.Keys(inputsByName(inputs)))
+}
@@ -0,0 +1,129 @@
+package compat_test
+
+import (
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/compat"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/diff"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+)
+
+const (
+	serviceID = "3d5a2f18-6a54-11ee-8b3e-5f2a1d0c9b8e"
+	planID    = "5b0c4fe8-6a55-11ee-9e66-8f1a2c3d4e5f"
+)
+
+var _ = Describe("Check", func() {
+	var previous, current diff.Brokerpak
+
+	BeforeEach(func() {
+		previous = fakeBrokerpak("1.6.0")
+		current = fakeBrokerpak("1.6.1")
+	})
+
+	It("passes when nothing breaks existing instances", func() {
+		current.Services[0].Plans = append(current.Services[0].Plans, tf.TfServiceDefinitionV1Plan{Name: "large", ID: "b0d1c0b6-6a55-11ee-8c2f-1f2d8f3a6d27"})
+		current.Services[0].ProvisionSettings.UserInputs = append(current.Services[0].ProvisionSettings.UserInputs, broker.BrokerVariable{FieldName: "region"})
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Compatible()).To(BeTrue())
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.Warnings).To(BeEmpty())
+	})
+
+	It("fails when a service ID is missing", func() {
+		current.Services[0].ID = "e1f7b3e6-6a55-11ee-8f62-7b3c2d1e0f9a"
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Compatible()).To(BeFalse())
+		Expect(result.Errors).To(ConsistOf(`service "fake-service" (` + serviceID + `) has been removed or its ID has changed`))
+	})
+
+	It("fails when a plan ID is missing", func() {
+		current.Services[0].Plans[0].ID = "a5ec1b44-6a55-11ee-a2b5-0b7e5a9b1f36"
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Errors).To(ConsistOf(`service "fake-service": plan "small" (` + planID + `) has been removed or its ID has changed`))
+	})
+
+	It("fails when an optional input becomes required", func() {
+		current.Services[0].ProvisionSettings.UserInputs[0].Required = true
+		current.Services[0].BindSettings.UserInputs[0].Required = true
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Errors).To(ConsistOf(
+			`service "fake-service": provision input "name" was optional and is now required`,
+			`service "fake-service": bind input "role" was optional and is now required`,
+		))
+	})
+
+	It("fails when a new required provision input is added", func() {
+		current.Services[0].ProvisionSettings.UserInputs = append(current.Services[0].ProvisionSettings.UserInputs, broker.BrokerVariable{FieldName: "region", Required: true})
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Errors).To(ConsistOf(`service "fake-service": new provision input "region" is required, but existing instances do not have a value for it`))
+	})
+
+	It("fails when an output used by bind computed inputs is not available", func() {
+		current.Services[0].ProvisionSettings.Outputs = []broker.BrokerVariable{{FieldName: "port"}}
+		current.Services[0].BindSettings.Computed = append(current.Services[0].BindSettings.Computed, varcontext.DefaultVariable{
+			Name:    "port",
+			Default: `${instance.details["port"]}`,
+		})
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Errors).To(ConsistOf(
+			`service "fake-service": provision output "hostname" has been removed, but is used by bind computed input "host"`,
+			`service "fake-service": bind computed input "port" uses provision output "port", which existing instances do not have`,
+		))
+	})
+
+	It("warns when the provision template changes without a maintenance_info bump", func() {
+		current.Manifest.TerraformVersions[0].Version = version.Must(version.NewVersion("1.6.0"))
+		current.Services[0].ProvisionSettings.Template = `resource "random_string" "new" {}`
+
+		result := compat.Check(previous, current)
+
+		Expect(result.Compatible()).To(BeTrue())
+		Expect(result.Warnings).To(ConsistOf(`service "fake-service": the provision template or its inputs changed, but the default OpenTofu version is still 1.6.0 so maintenance_info will not change and existing instances will not be offered an upgrade`))
+	})
+})
+
+func fakeBrokerpak(tofuVersion string) diff.Brokerpak {
+	return diff.Brokerpak{
+		Manifest: &manifest.Manifest{
+			TerraformVersions: []manifest.TerraformVersion{
+				{Version: version.Must(version.NewVersion(tofuVersion)), Default: true},
+			},
+		},
+		Services: []tf.TfServiceDefinitionV1{{
+			Name: "fake-service",
+			ID:   serviceID,
+			Plans: []tf.TfServiceDefinitionV1Plan{
+				{Name: "small", ID: planID},
+			},
+			ProvisionSettings: tf.TfServiceDefinitionV1Action{
+				Template:   `resource "random_string" "name" {}`,
+				UserInputs: []broker.BrokerVariable{{FieldName: "name"}},
+				Outputs:    []broker.BrokerVariable{{FieldName: "hostname"}},
+			},
+			BindSettings: tf.TfServiceDefinitionV1Action{
+				UserInputs: []broker.BrokerVariable{{FieldName: "role"}},
+				Computed: []varcontext.DefaultVariable{
+					{Name: "host", Default: `${instance.details["hostname"]}`},
+				},
+			},
+		}},
+	}
+}
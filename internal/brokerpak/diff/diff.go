@@ -0,0 +1,313 @@
+// Package diff compares two brokerpaks for release review
+package diff
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+)
+
+// Brokerpak holds the parts of a brokerpak that are compared
+type Brokerpak struct {
+	Manifest *manifest.Manifest
+	Services []tf.TfServiceDefinitionV1
+}
+
+// Report describes the differences between two brokerpaks
+type Report struct {
+	Old             Version         `json:"old"`
+	New             Version         `json:"new"`
+	Binaries        []BinaryChange  `json:"binaries,omitempty"`
+	AddedServices   []string        `json:"added_services,omitempty"`
+	RemovedServices []string        `json:"removed_services,omitempty"`
+	ChangedServices []ServiceChange `json:"changed_services,omitempty"`
+
+	// BreaksInstances is true when existing service instances would no longer be manageable,
+	// because their service or plan has been removed or had its ID changed.
+	BreaksInstances bool `json:"breaks_instances"`
+}
+
+type Version struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// BinaryChange lists the versions of a bundled binary in each brokerpak. No versions means the binary is not bundled.
+type BinaryChange struct {
+	Name        string   `json:"name"`
+	OldVersions []string `json:"old_versions"`
+	NewVersions []string `json:"new_versions"`
+}
+
+// ServiceChange describes the differences in a service that is in both brokerpaks, matched by name.
+// Its plans are matched by ID, as that is what existing service instances refer to.
+type ServiceChange struct {
+	Name                     string           `json:"name"`
+	OldID                    string           `json:"old_id,omitempty"`
+	NewID                    string           `json:"new_id,omitempty"`
+	AddedPlans               []string         `json:"added_plans,omitempty"`
+	RemovedPlans             []string         `json:"removed_plans,omitempty"`
+	RenamedPlans             []PlanRename     `json:"renamed_plans,omitempty"`
+	ChangedPlanIDs           []PlanIDChange   `json:"changed_plan_ids,omitempty"`
+	Variables                []VariableChange `json:"variables,omitempty"`
+	ProvisionTemplateChanged bool             `json:"provision_template_changed,omitempty"`
+	BindTemplateChanged      bool             `json:"bind_template_changed,omitempty"`
+}
+
+// PlanIDChange is a plan that was removed, and a plan with the same name but a different ID that was added.
+// Existing instances of the plan can no longer be managed once the new brokerpak is deployed.
+type PlanIDChange struct {
+	Plan  string `json:"plan"`
+	OldID string `json:"old_id"`
+	NewID string `json:"new_id"`
+}
+
+// PlanRename is a plan that is in both brokerpaks, matched by ID, with a different name.
+// Existing instances of the plan can still be managed.
+type PlanRename struct {
+	ID      string `json:"id"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+const (
+	VariableAdded   = "added"
+	VariableRemoved = "removed"
+	VariableChanged = "changed"
+)
+
+// VariableChange describes a user input that was added, removed, or changed
+type VariableChange struct {
+	Action  string   `json:"action"`
+	Field   string   `json:"field"`
+	Change  string   `json:"change"`
+	Details []string `json:"details,omitempty"`
+}
+
+// Empty is true when no differences were found
+func (r Report) Empty() bool {
+	return r.Old == r.New &&
+		len(r.Binaries) == 0 &&
+		len(r.AddedServices) == 0 &&
+		len(r.RemovedServices) == 0 &&
+		len(r.ChangedServices) == 0
+}
+
+func (r Report) breaksInstances() bool {
+	if len(r.RemovedServices) > 0 {
+		return true
+	}
+	for _, s := range r.ChangedServices {
+		if s.OldID != s.NewID || len(s.RemovedPlans) > 0 || len(s.ChangedPlanIDs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare reports the differences between the old and new brokerpak
+func Compare(oldPak, newPak Brokerpak) Report {
+	report := Report{
+		Old:      Version{Name: oldPak.Manifest.Name, Version: oldPak.Manifest.Version},
+		New:      Version{Name: newPak.Manifest.Name, Version: newPak.Manifest.Version},
+		Binaries: compareBinaries(oldPak.Manifest, newPak.Manifest),
+	}
+
+	oldServices := byKey(oldPak.Services, func(s tf.TfServiceDefinitionV1) string { return s.Name })
+	newServices := byKey(newPak.Services, func(s tf.TfServiceDefinitionV1) string { return s.Name })
+	report.AddedServices, report.RemovedServices = addedAndRemoved(oldServices, newServices)
+
+	for _, name := range slices.Sorted(maps.Keys(newServices)) {
+		oldService, ok := oldServices[name]
+		if !ok {
+			continue
+		}
+		if change, changed := compareService(oldService, newServices[name]); changed {
+			report.ChangedServices = append(report.ChangedServices, change)
+		}
+	}
+
+	report.BreaksInstances = report.breaksInstances()
+	return report
+}
+
+func compareBinaries(oldManifest, newManifest *manifest.Manifest) (result []BinaryChange) {
+	oldVersions := binaryVersions(oldManifest)
+	newVersions := binaryVersions(newManifest)
+
+	names := slices.Sorted(maps.Keys(oldVersions))
+	for name := range newVersions {
+		if _, ok := oldVersions[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !slices.Equal(oldVersions[name], newVersions[name]) {
+			result = append(result, BinaryChange{
+				Name:        name,
+				OldVersions: oldVersions[name],
+				NewVersions: newVersions[name],
+			})
+		}
+	}
+
+	return result
+}
+
+func binaryVersions(m *manifest.Manifest) map[string][]string {
+	result := make(map[string][]string)
+	for _, r := range m.TerraformVersions {
+		result["tofu"] = append(result["tofu"], r.Version.String())
+	}
+	for _, r := range m.TerraformProviders {
+		result[r.Name] = append(result[r.Name], r.Version.String())
+	}
+	for _, r := range m.Binaries {
+		result[r.Name] = append(result[r.Name], r.Version)
+	}
+	for _, v := range result {
+		slices.Sort(v)
+	}
+	return result
+}
+
+func compareService(oldService, newService tf.TfServiceDefinitionV1) (ServiceChange, bool) {
+	change := ServiceChange{Name: newService.Name}
+	if oldService.ID != newService.ID {
+		change.OldID = oldService.ID
+		change.NewID = newService.ID
+	}
+
+	oldPlans := byKey(oldService.Plans, func(p tf.TfServiceDefinitionV1Plan) string { return p.ID })
+	newPlans := byKey(newService.Plans, func(p tf.TfServiceDefinitionV1Plan) string { return p.ID })
+	change.AddedPlans, change.RemovedPlans, change.ChangedPlanIDs = comparePlanIDs(oldPlans, newPlans)
+	for _, id := range slices.Sorted(maps.Keys(newPlans)) {
+		if oldPlan, ok := oldPlans[id]; ok && oldPlan.Name != newPlans[id].Name {
+			change.RenamedPlans = append(change.RenamedPlans, PlanRename{ID: id, OldName: oldPlan.Name, NewName: newPlans[id].Name})
+		}
+	}
+
+	change.Variables = append(
+		compareVariables("provision", oldService.ProvisionSettings.UserInputs, newService.ProvisionSettings.UserInputs),
+		compareVariables("bind", oldService.BindSettings.UserInputs, newService.BindSettings.UserInputs)...,
+	)
+
+	change.ProvisionTemplateChanged = templateChanged(oldService.ProvisionSettings, newService.ProvisionSettings)
+	change.BindTemplateChanged = templateChanged(oldService.BindSettings, newService.BindSettings)
+
+	changed := change.OldID != change.NewID ||
+		len(change.AddedPlans) > 0 ||
+		len(change.RemovedPlans) > 0 ||
+		len(change.RenamedPlans) > 0 ||
+		len(change.ChangedPlanIDs) > 0 ||
+		len(change.Variables) > 0 ||
+		change.ProvisionTemplateChanged ||
+		change.BindTemplateChanged
+
+	return change, changed
+}
+
+// comparePlanIDs lists the names of the plans whose IDs were added or removed. A plan whose ID was removed
+// and that was added back under the same name with a different ID is reported as an ID change instead.
+func comparePlanIDs(oldPlans, newPlans map[string]tf.TfServiceDefinitionV1Plan) (added, removed []string, changedIDs []PlanIDChange) {
+	addedIDs, removedIDs := addedAndRemoved(oldPlans, newPlans)
+
+	removedByName := make(map[string]string)
+	for _, id := range removedIDs {
+		removedByName[oldPlans[id].Name] = id
+	}
+
+	for _, id := range addedIDs {
+		name := newPlans[id].Name
+		if oldID, ok := removedByName[name]; ok {
+			changedIDs = append(changedIDs, PlanIDChange{Plan: name, OldID: oldID, NewID: id})
+			delete(removedByName, name)
+			continue
+		}
+		added = append(added, name)
+	}
+	removed = slices.Collect(maps.Keys(removedByName))
+
+	slices.Sort(added)
+	slices.Sort(removed)
+	slices.SortFunc(changedIDs, func(a, b PlanIDChange) int { return strings.Compare(a.Plan, b.Plan) })
+	return added, removed, changedIDs
+}
+
+func compareVariables(action string, oldVars, newVars []broker.BrokerVariable) (result []VariableChange) {
+	oldByName := byKey(oldVars, func(v broker.BrokerVariable) string { return v.FieldName })
+	newByName := byKey(newVars, func(v broker.BrokerVariable) string { return v.FieldName })
+
+	added, removed := addedAndRemoved(oldByName, newByName)
+	for _, name := range added {
+		result = append(result, VariableChange{Action: action, Field: name, Change: VariableAdded})
+	}
+	for _, name := range removed {
+		result = append(result, VariableChange{Action: action, Field: name, Change: VariableRemoved})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(newByName)) {
+		oldVar, ok := oldByName[name]
+		if !ok {
+			continue
+		}
+		if details := variableDetails(oldVar, newByName[name]); len(details) > 0 {
+			result = append(result, VariableChange{Action: action, Field: name, Change: VariableChanged, Details: details})
+		}
+	}
+
+	return result
+}
+
+func variableDetails(oldVar, newVar broker.BrokerVariable) (details []string) {
+	compare := func(property string, oldValue, newValue any) {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			details = append(details, fmt.Sprintf("%s: %v -> %v", property, oldValue, newValue))
+		}
+	}
+
+	compare("type", oldVar.Type, newVar.Type)
+	compare("required", oldVar.Required, newVar.Required)
+	compare("nullable", oldVar.Nullable, newVar.Nullable)
+	compare("default", oldVar.Default, newVar.Default)
+	compare("enum", oldVar.Enum, newVar.Enum)
+	compare("constraints", oldVar.Constraints, newVar.Constraints)
+	compare("prohibit_update", oldVar.ProhibitUpdate, newVar.ProhibitUpdate)
+	compare("tf_attribute", oldVar.TFAttribute, newVar.TFAttribute)
+
+	return details
+}
+
+func templateChanged(oldAction, newAction tf.TfServiceDefinitionV1Action) bool {
+	return oldAction.Template != newAction.Template || !maps.Equal(oldAction.Templates, newAction.Templates)
+}
+
+func byKey[A any](items []A, key func(A) string) map[string]A {
+	result := make(map[string]A)
+	for _, item := range items {
+		result[key(item)] = item
+	}
+	return result
+}
+
+func addedAndRemoved[A any](oldItems, newItems map[string]A) (added, removed []string) {
+	for _, name := range slices.Sorted(maps.Keys(newItems)) {
+		if _, ok := oldItems[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(oldItems)) {
+		if _, ok := newItems[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,166 @@
+package diff_test
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/diff"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+)
+
+var _ = Describe("Compare", func() {
+	var oldPak, newPak diff.Brokerpak
+
+	BeforeEach(func() {
+		oldPak = fakeBrokerpak("1.0.0")
+		newPak = fakeBrokerpak("1.0.0")
+	})
+
+	It("reports no differences for identical brokerpaks", func() {
+		report := diff.Compare(oldPak, newPak)
+
+		Expect(report.Empty()).To(BeTrue())
+		Expect(report.BreaksInstances).To(BeFalse())
+	})
+
+	It("reports changed binary versions", func() {
+		newPak.Manifest.TerraformVersions[0].Version = version.Must(version.NewVersion("1.6.1"))
+		newPak.Manifest.TerraformProviders = nil
+
+		report := diff.Compare(oldPak, newPak)
+
+		Expect(report.Binaries).To(Equal([]diff.BinaryChange{
+			{Name: "terraform-provider-random", OldVersions: []string{"3.1.0"}},
+			{Name: "tofu", OldVersions: []string{"1.6.0"}, NewVersions: []string{"1.6.1"}},
+		}))
+		Expect(report.BreaksInstances).To(BeFalse())
+	})
+
+	It("reports added and removed services", func() {
+		oldPak.Services = append(oldPak.Services, fakeService("old-service", "c3ed4b1a-6a54-11ee-9d1d-3b7b1c0bcd5a"))
+		newPak.Services = append(newPak.Services, fakeService("new-service", "cf8f2bf2-6a54-11ee-9d5f-6b1f8c7c5f4e"))
+
+		report := diff.Compare(oldPak, newPak)
+
+		Expect(report.AddedServices).To(Equal([]string{"new-service"}))
+		Expect(report.RemovedServices).To(Equal([]string{"old-service"}))
+		Expect(report.BreaksInstances).To(BeTrue())
+	})
+
+	It("reports added, removed and changed plans", func() {
+		newPak.Services[0].Plans = []tf.TfServiceDefinitionV1Plan{
+			{Name: "small", ID: "a5ec1b44-6a55-11ee-a2b5-0b7e5a9b1f36"},
+			{Name: "large", ID: "b0d1c0b6-6a55-11ee-8c2f-1f2d8f3a6d27"},
+		}
+
+		report := diff.Compare(oldPak, newPak)
+
+		Expect(report.ChangedServices).To(HaveLen(1))
+		Expect(report.ChangedServices[0].AddedPlans).To(Equal([]string{"large"}))
+		Expect(report.ChangedServices[0].RemovedPlans).To(Equal([]string{"medium"}))
+		Expect(report.ChangedServices[0].ChangedPlanIDs).To(Equal([]diff.PlanIDChange{{
+			Plan:  "small",
+			OldID: "5b0c4fe8-6a55-11ee-9e66-8f1a2c3d4e5f",
+			NewID: "a5ec1b44-6a55-11ee-a2b5-0b7e5a9b1f36",
+		}}))
+		Expect(report.BreaksInstances).To(BeTrue())
+	})
+
+	It("reports renamed plans without breaking instances", func() {
+		newPak.Services[0].Plans[1].Name = "standard"
+
+		report := diff.Compare(oldPak, newPak)
+
+		Expect(report.ChangedServices).To(HaveLen(1))
+		Expect(report.ChangedServices[0].AddedPlans).To(BeEmpty())
+		Expect(report.ChangedServices[0].RemovedPlans).To(BeEmpty())
+		Expect(report.ChangedServices[0].RenamedPlans).To(Equal([]diff.PlanRename{{
+			ID:      "6f3e2d1c-6a55-11ee-b4a7-2c9d8e7f6a5b",
+			OldName: "medium",
+			NewName: "standard",
+		}}))
+		Expect(report.BreaksInstances).To(BeFalse())
+
+		var out strings.Builder
+		Expect(report.WriteText(&out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("plan renamed: medium -> standard (6f3e2d1c-6a55-11ee-b4a7-2c9d8e7f6a5b)\n"))
+	})
+
+	It("reports changed variables and templates", func() {
+		newPak.Services[0].ProvisionSettings.UserInputs = []broker.BrokerVariable{
+			{FieldName: "size", Type: broker.JSONTypeInteger, Required: true, Constraints: map[string]any{"maximum": 10}},
+			{FieldName: "region", Type: broker.JSONTypeString},
+		}
+		newPak.Services[0].ProvisionSettings.Template = "resource {}"
+
+		report := diff.Compare(oldPak, newPak)
+
+		Expect(report.ChangedServices).To(HaveLen(1))
+		Expect(report.ChangedServices[0].Variables).To(Equal([]diff.VariableChange{
+			{Action: "provision", Field: "region", Change: diff.VariableAdded},
+			{Action: "provision", Field: "name", Change: diff.VariableRemoved},
+			{Action: "provision", Field: "size", Change: diff.VariableChanged, Details: []string{
+				"required: false -> true",
+				"constraints: map[maximum:5] -> map[maximum:10]",
+			}},
+		}))
+		Expect(report.ChangedServices[0].ProvisionTemplateChanged).To(BeTrue())
+		Expect(report.ChangedServices[0].BindTemplateChanged).To(BeFalse())
+		Expect(report.BreaksInstances).To(BeFalse())
+	})
+
+	It("can write a human-readable report", func() {
+		newPak.Manifest.Version = "1.1.0"
+		newPak.Services[0].ID = "e1f7b3e6-6a55-11ee-8f62-7b3c2d1e0f9a"
+		newPak.Services[0].BindSettings.Template = "output {}"
+
+		var out strings.Builder
+		Expect(diff.Compare(oldPak, newPak).WriteText(&out)).To(Succeed())
+
+		Expect(out.String()).To(Equal(`Brokerpak: fake-pak 1.0.0 -> fake-pak 1.1.0
+
+Services
+  ~ fake-service
+      service ID changed: 3d5a2f18-6a54-11ee-8b3e-5f2a1d0c9b8e -> e1f7b3e6-6a55-11ee-8f62-7b3c2d1e0f9a (breaks existing instances)
+      bind template changed
+`))
+	})
+})
+
+func fakeBrokerpak(pakVersion string) diff.Brokerpak {
+	return diff.Brokerpak{
+		Manifest: &manifest.Manifest{
+			Name:    "fake-pak",
+			Version: pakVersion,
+			TerraformVersions: []manifest.TerraformVersion{
+				{Version: version.Must(version.NewVersion("1.6.0"))},
+			},
+			TerraformProviders: []manifest.TerraformProvider{
+				{Name: "terraform-provider-random", Version: version.Must(version.NewVersion("3.1.0"))},
+			},
+		},
+		Services: []tf.TfServiceDefinitionV1{fakeService("fake-service", "3d5a2f18-6a54-11ee-8b3e-5f2a1d0c9b8e")},
+	}
+}
+
+func fakeService(name, id string) tf.TfServiceDefinitionV1 {
+	return tf.TfServiceDefinitionV1{
+		Name: name,
+		ID:   id,
+		Plans: []tf.TfServiceDefinitionV1Plan{
+			{Name: "small", ID: "5b0c4fe8-6a55-11ee-9e66-8f1a2c3d4e5f"},
+			{Name: "medium", ID: "6f3e2d1c-6a55-11ee-b4a7-2c9d8e7f6a5b"},
+		},
+		ProvisionSettings: tf.TfServiceDefinitionV1Action{
+			UserInputs: []broker.BrokerVariable{
+				{FieldName: "name", Type: broker.JSONTypeString},
+				{FieldName: "size", Type: broker.JSONTypeInteger, Constraints: map[string]any{"maximum": 5}},
+			},
+		},
+	}
+}
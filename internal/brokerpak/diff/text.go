@@ -0,0 +1,76 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText writes the report in a human-readable form
+func (r Report) WriteText(out io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Brokerpak: %s %s -> %s %s\n", r.Old.Name, r.Old.Version, r.New.Name, r.New.Version)
+	if r.Empty() {
+		fmt.Fprintln(&b, "No differences found")
+		_, err := io.WriteString(out, b.String())
+		return err
+	}
+
+	if len(r.Binaries) > 0 {
+		fmt.Fprintln(&b, "\nBinaries")
+		for _, c := range r.Binaries {
+			fmt.Fprintf(&b, "  %s: %s -> %s\n", c.Name, versions(c.OldVersions), versions(c.NewVersions))
+		}
+	}
+
+	if len(r.AddedServices) > 0 || len(r.RemovedServices) > 0 || len(r.ChangedServices) > 0 {
+		fmt.Fprintln(&b, "\nServices")
+	}
+	for _, name := range r.AddedServices {
+		fmt.Fprintf(&b, "  + %s\n", name)
+	}
+	for _, name := range r.RemovedServices {
+		fmt.Fprintf(&b, "  - %s (breaks existing instances)\n", name)
+	}
+	for _, s := range r.ChangedServices {
+		fmt.Fprintf(&b, "  ~ %s\n", s.Name)
+		if s.OldID != s.NewID {
+			fmt.Fprintf(&b, "      service ID changed: %s -> %s (breaks existing instances)\n", s.OldID, s.NewID)
+		}
+		for _, p := range s.AddedPlans {
+			fmt.Fprintf(&b, "      plan added: %s\n", p)
+		}
+		for _, p := range s.RemovedPlans {
+			fmt.Fprintf(&b, "      plan removed: %s (breaks existing instances)\n", p)
+		}
+		for _, p := range s.RenamedPlans {
+			fmt.Fprintf(&b, "      plan renamed: %s -> %s (%s)\n", p.OldName, p.NewName, p.ID)
+		}
+		for _, p := range s.ChangedPlanIDs {
+			fmt.Fprintf(&b, "      plan ID changed: %s: %s -> %s (breaks existing instances)\n", p.Plan, p.OldID, p.NewID)
+		}
+		for _, v := range s.Variables {
+			fmt.Fprintf(&b, "      %s input %s: %s\n", v.Action, v.Field, v.Change)
+			for _, d := range v.Details {
+				fmt.Fprintf(&b, "        %s\n", d)
+			}
+		}
+		if s.ProvisionTemplateChanged {
+			fmt.Fprintln(&b, "      provision template changed")
+		}
+		if s.BindTemplateChanged {
+			fmt.Fprintln(&b, "      bind template changed")
+		}
+	}
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func versions(v []string) string {
+	if len(v) == 0 {
+		return "(none)"
+	}
+	return strings.Join(v, ", ")
+}
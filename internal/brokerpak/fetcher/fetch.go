@@ -9,13 +9,17 @@ import (
 	"path/filepath"
 
 	"github.com/hashicorp/go-getter"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
 )
 
 func FetchArchive(src, dest string) error {
 	return newFileGetterClient(src, dest).Get()
 }
 
-func FetchBrokerpak(src, dest string) error {
+// FetchBrokerpak fetches a brokerpak from any go-getter source, or from an OCI registry with an oci:// source.
+// The credentials are used to log in to OCI registries.
+func FetchBrokerpak(src, dest string, credentials oci.Credentials) error {
 	execWd := filepath.Dir(os.Args[0])
 	execDir, err := filepath.Abs(execWd)
 	if err != nil {
@@ -24,6 +28,7 @@ func FetchBrokerpak(src, dest string) error {
 
 	client := newFileGetterClient(src, dest)
 	client.Pwd = execDir
+	client.Getters[oci.Scheme] = oci.NewGetter(credentials)
 
 	return client.Get()
 }
@@ -31,6 +31,7 @@ type TerraformVersion struct {
 	Default     bool
 	Source      string
 	URLTemplate string
+	SHA256      map[string]string
 }
 
 type TerraformProvider struct {
@@ -39,6 +40,7 @@ type TerraformProvider struct {
 	Source      string
 	Provider    tfproviderfqn.TfProviderFQN
 	URLTemplate string
+	SHA256      map[string]string
 }
 
 type Binary struct {
@@ -46,4 +48,5 @@ type Binary struct {
 	Version     string
 	Source      string
 	URLTemplate string
+	SHA256      map[string]string
 }
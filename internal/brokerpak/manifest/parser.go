@@ -3,6 +3,9 @@ package manifest
 import (
 	"bytes"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 
 	"github.com/hashicorp/go-version"
 	"gopkg.in/yaml.v3"
@@ -31,6 +34,8 @@ type parser struct {
 
 const binaryName = "tofu"
 
+var sha256Regex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
 func Parse(input []byte) (*Manifest, error) {
 	decoder := yaml.NewDecoder(bytes.NewReader(input))
 	decoder.KnownFields(true)
@@ -160,6 +165,8 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 			}).ViaFieldIndex("terraform_binaries", i))
 		}
 
+		errs = errs.Also(validateSHA256(r.SHA256).ViaFieldIndex("terraform_binaries", i))
+
 		if r.resourceType() == terraformVersion {
 			errs = errs.Also(validation.ErrIfDuplicate(r.Version, "version", terraformVersionCache))
 		}
@@ -171,6 +178,7 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 				Default:     r.Default,
 				Source:      r.Source,
 				URLTemplate: r.URLTemplate,
+				SHA256:      r.SHA256,
 			})
 		case terraformProvider:
 			providers = append(providers, TerraformProvider{
@@ -179,6 +187,7 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 				Source:      r.Source,
 				Provider:    providerFQN,
 				URLTemplate: r.URLTemplate,
+				SHA256:      r.SHA256,
 			})
 		case otherBinary:
 			binaries = append(binaries, Binary{
@@ -186,6 +195,7 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 				Version:     r.Version,
 				Source:      r.Source,
 				URLTemplate: r.URLTemplate,
+				SHA256:      r.SHA256,
 			})
 		}
 	}
@@ -222,6 +232,22 @@ func parseTerraformResources(p parser) (versions []TerraformVersion, providers [
 	return versions, providers, binaries, errs
 }
 
+func validateSHA256(checksums map[string]string) (errs *validation.FieldError) {
+	for _, plat := range slices.Sorted(maps.Keys(checksums)) {
+		if platform.Parse(plat).Empty() {
+			errs = errs.Also((&validation.FieldError{
+				Message: fmt.Sprintf("expected platform in the form os/arch, got %q", plat),
+				Paths:   []string{"sha256"},
+			}))
+		}
+		if !sha256Regex.MatchString(checksums[plat]) {
+			errs = errs.Also(validation.ErrInvalidValue(checksums[plat], "").ViaFieldKey("sha256", plat))
+		}
+	}
+
+	return errs
+}
+
 var _ validation.Validatable = (*parser)(nil)
 
 func (m *parser) Validate() (errs *validation.FieldError) {
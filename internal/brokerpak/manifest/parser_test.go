@@ -60,6 +60,9 @@ var _ = Describe("Parser", func() {
 					Version:     "latest",
 					Source:      "nothing-important",
 					URLTemplate: "./tools/${name}/build/${name}_${version}_${os}_${arch}.zip",
+					SHA256: map[string]string{
+						"linux/amd64": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+					},
 				},
 			},
 			ServiceDefinitions: []string{
@@ -364,6 +367,47 @@ var _ = Describe("Parser", func() {
 		Entry("fully qualified", "mything.io/mycorp/lala", "mything.io/mycorp/lala"),
 	)
 
+	Context("sha256", func() {
+		const checksum = "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+
+		It("can parse the checksums", func() {
+			m, err := manifest.Parse(fakeManifest(withAdditionalEntry("terraform_binaries", map[string]any{
+				"name":    "terraform-provider-foo",
+				"version": "1.0.0",
+				"sha256":  map[string]string{"linux/amd64": checksum},
+			})))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.TerraformProviders).To(ContainElement(HaveField("SHA256", Equal(map[string]string{"linux/amd64": checksum}))))
+		})
+
+		When("the checksum is not a SHA-256 hex digest", func() {
+			It("fails", func() {
+				m, err := manifest.Parse(fakeManifest(withAdditionalEntry("terraform_binaries", map[string]any{
+					"name":    "terraform-provider-foo",
+					"version": "1.0.0",
+					"sha256":  map[string]string{"linux/amd64": "not-a-checksum"},
+				})))
+
+				Expect(err).To(MatchError(ContainSubstring("invalid value: not-a-checksum: terraform_binaries[3].sha256[linux/amd64]")))
+				Expect(m).To(BeNil())
+			})
+		})
+
+		When("the platform is not in the form os/arch", func() {
+			It("fails", func() {
+				m, err := manifest.Parse(fakeManifest(withAdditionalEntry("terraform_binaries", map[string]any{
+					"name":    "terraform-provider-foo",
+					"version": "1.0.0",
+					"sha256":  map[string]string{"linux": checksum},
+				})))
+
+				Expect(err).To(MatchError(ContainSubstring(`expected platform in the form os/arch, got "linux": terraform_binaries[3].sha256`)))
+				Expect(m).To(BeNil())
+			})
+		})
+	})
+
 	When("yaml is invalid", func() {
 		It("fails", func() {
 			m, err := manifest.Parse([]byte(`not yam-ls:`))
@@ -26,6 +26,7 @@ func (m *Manifest) Serialize() ([]byte, error) {
 			Version:     v.Version.String(),
 			Source:      v.Source,
 			URLTemplate: v.URLTemplate,
+			SHA256:      v.SHA256,
 			Default:     v.Default,
 		})
 	}
@@ -36,6 +37,7 @@ func (m *Manifest) Serialize() ([]byte, error) {
 			Source:      v.Source,
 			Provider:    v.Provider.String(),
 			URLTemplate: v.URLTemplate,
+			SHA256:      v.SHA256,
 		})
 	}
 	for _, v := range m.Binaries {
@@ -44,6 +46,7 @@ func (m *Manifest) Serialize() ([]byte, error) {
 			Version:     v.Version,
 			Source:      v.Source,
 			URLTemplate: v.URLTemplate,
+			SHA256:      v.SHA256,
 		})
 	}
 
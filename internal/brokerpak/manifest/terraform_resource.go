@@ -26,6 +26,10 @@ type TerraformResource struct {
 
 	// Default is used to mark the default Terraform version when there is more than one
 	Default bool `yaml:"default,omitempty"`
+
+	// SHA256 holds the expected hex encoded SHA-256 checksum of the binary for each platform,
+	// keyed by platform in the form "os/arch", e.g. "linux/amd64".
+	SHA256 map[string]string `yaml:"sha256,omitempty"`
 }
 
 type terraformResourceType int
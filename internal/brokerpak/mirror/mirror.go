@@ -0,0 +1,257 @@
+// Package mirror writes the providers bundled in a brokerpak as an OpenTofu provider mirror
+package mirror
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
+)
+
+const (
+	// ChecksumsFileName is the file in the root of the mirror that lists the SHA256 checksums of the provider archives
+	ChecksumsFileName = "SHA256SUMS"
+	// CLIConfigFileName is the file in the root of the mirror with an OpenTofu CLI configuration that uses the mirror
+	CLIConfigFileName = ".terraformrc"
+)
+
+// Source is a brokerpak that providers can be read from
+type Source interface {
+	Manifest() (*manifest.Manifest, error)
+	OpenProvider(plat platform.Platform, r manifest.TerraformProvider) (string, io.ReadCloser, error)
+}
+
+// Provider is a provider version that was written to the mirror
+type Provider struct {
+	Address   string
+	Version   string
+	Platforms []string
+}
+
+// Result describes the mirror that was written
+type Result struct {
+	Directory string
+	Providers []Provider
+}
+
+// Addresses returns the sorted, distinct addresses of the providers in the mirror
+func (r Result) Addresses() []string {
+	var result []string
+	for _, p := range r.Providers {
+		if !slices.Contains(result, p.Address) {
+			result = append(result, p.Address)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+type archive struct {
+	URL    string   `json:"url"`
+	Hashes []string `json:"hashes"`
+}
+
+type versionIndex struct {
+	Archives map[string]archive `json:"archives"`
+}
+
+type providerIndex struct {
+	Versions map[string]struct{} `json:"versions"`
+}
+
+// Write writes the providers in the brokerpak for all of its platforms to dir, in the packed layout that OpenTofu
+// uses for filesystem mirrors. It also writes the JSON files of the provider network mirror protocol, so the
+// directory can be served over HTTPS as a network mirror. Providers already in dir from other brokerpaks are kept,
+// but the CLI configuration that is written only covers the providers of this brokerpak.
+func Write(pak Source, dir string) (Result, error) {
+	mf, err := pak.Manifest()
+	if err != nil {
+		return Result{}, err
+	}
+
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Directory: dir}
+	checksums := make(map[string]string)
+
+	for _, r := range mf.TerraformProviders {
+		providerDir := filepath.Join(dir, r.Provider.Hostname, r.Provider.Namespace, r.Provider.Type)
+		if err := os.MkdirAll(providerDir, 0o755); err != nil {
+			return Result{}, err
+		}
+
+		provider := Provider{Address: r.Provider.String(), Version: r.Version.String()}
+		index := versionIndex{Archives: make(map[string]archive)}
+
+		for _, plat := range mf.Platforms {
+			target := fmt.Sprintf("%s_%s", plat.Os, plat.Arch)
+			archiveName := fmt.Sprintf("terraform-provider-%s_%s_%s.zip", r.Provider.Type, r.Version, target)
+
+			a, sum, err := writeArchive(pak, plat, r, filepath.Join(providerDir, archiveName))
+			if err != nil {
+				return Result{}, err
+			}
+			a.URL = archiveName
+
+			index.Archives[target] = a
+			checksums[path.Join(r.Provider.Hostname, r.Provider.Namespace, r.Provider.Type, archiveName)] = sum
+			provider.Platforms = append(provider.Platforms, target)
+		}
+
+		if err := writeJSON(filepath.Join(providerDir, r.Version.String()+".json"), index); err != nil {
+			return Result{}, err
+		}
+		if err := addToProviderIndex(filepath.Join(providerDir, "index.json"), r.Version.String()); err != nil {
+			return Result{}, err
+		}
+
+		result.Providers = append(result.Providers, provider)
+	}
+
+	if err := writeChecksums(filepath.Join(dir, ChecksumsFileName), checksums); err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, CLIConfigFileName), []byte(CLIConfig(result)), 0o644); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// CLIConfig is an OpenTofu CLI configuration that installs the providers in the mirror from the mirror,
+// and all other providers from their registries
+func CLIConfig(result Result) string {
+	var include strings.Builder
+	for _, a := range result.Addresses() {
+		fmt.Fprintf(&include, "      %q,\n", a)
+	}
+
+	return fmt.Sprintf(`# Use with: export TF_CLI_CONFIG_FILE=%[3]q
+provider_installation {
+  filesystem_mirror {
+    path    = %[1]q
+    include = [
+%[2]s    ]
+  }
+  direct {
+    exclude = [
+%[2]s    ]
+  }
+}
+
+# To serve the mirror over HTTPS instead, replace the filesystem_mirror block with:
+#
+#   network_mirror {
+#     url     = "https://mirror.example.com/"
+#     include = [...]
+#   }
+`, result.Directory, include.String(), filepath.Join(result.Directory, CLIConfigFileName))
+}
+
+// writeArchive zips the provider binary the way it is published in a registry. The archive has fixed
+// timestamps so that its checksum only depends on the provider.
+func writeArchive(pak Source, plat platform.Platform, r manifest.TerraformProvider, dest string) (archive, string, error) {
+	name, rc, err := pak.OpenProvider(plat, r)
+	if err != nil {
+		return archive{}, "", err
+	}
+	defer rc.Close()
+
+	contents, err := io.ReadAll(rc)
+	if err != nil {
+		return archive{}, "", fmt.Errorf("error reading provider %q: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	header.SetMode(0o755)
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return archive{}, "", err
+	}
+	if _, err := w.Write(contents); err != nil {
+		return archive{}, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return archive{}, "", err
+	}
+
+	if err := os.WriteFile(dest, buf.Bytes(), 0o644); err != nil {
+		return archive{}, "", err
+	}
+
+	zipSum := sha256.Sum256(buf.Bytes())
+	zipHex := hex.EncodeToString(zipSum[:])
+	return archive{Hashes: []string{h1Hash(name, contents), "zh:" + zipHex}}, zipHex, nil
+}
+
+// h1Hash is the hash OpenTofu records in the dependency lock file for the unpacked contents of
+// a provider archive: the SHA256 of a summary of the SHA256 of each file, base64 encoded
+func h1Hash(name string, contents []byte) string {
+	fileSum := sha256.Sum256(contents)
+	summary := sha256.Sum256(fmt.Appendf(nil, "%x  %s\n", fileSum, name))
+	return "h1:" + base64.StdEncoding.EncodeToString(summary[:])
+}
+
+func addToProviderIndex(indexPath, version string) error {
+	index := providerIndex{Versions: make(map[string]struct{})}
+
+	data, err := os.ReadFile(indexPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &index); err != nil {
+			return fmt.Errorf("error parsing existing mirror index %q: %w", indexPath, err)
+		}
+		if index.Versions == nil {
+			index.Versions = make(map[string]struct{})
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	index.Versions[version] = struct{}{}
+	return writeJSON(indexPath, index)
+}
+
+func writeChecksums(checksumsPath string, checksums map[string]string) error {
+	existing := make(map[string]string)
+	if data, err := os.ReadFile(checksumsPath); err == nil {
+		for line := range strings.Lines(string(data)) {
+			if sum, name, ok := strings.Cut(strings.TrimSpace(line), "  "); ok {
+				existing[name] = sum
+			}
+		}
+	}
+	maps.Copy(existing, checksums)
+
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(existing)) {
+		fmt.Fprintf(&b, "%s  %s\n", existing[name], name)
+	}
+	return os.WriteFile(checksumsPath, []byte(b.String()), 0o644)
+}
+
+func writeJSON(dest string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest, append(data, '\n'), 0o644)
+}
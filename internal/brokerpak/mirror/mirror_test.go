@@ -0,0 +1,181 @@
+package mirror_test
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/mirror"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/tfproviderfqn"
+)
+
+type fakeSource struct {
+	manifest *manifest.Manifest
+}
+
+func (f fakeSource) Manifest() (*manifest.Manifest, error) {
+	return f.manifest, nil
+}
+
+func (f fakeSource) OpenProvider(plat platform.Platform, r manifest.TerraformProvider) (string, io.ReadCloser, error) {
+	name := fmt.Sprintf("%s_v%s", r.Name, r.Version)
+	return name, io.NopCloser(strings.NewReader(fmt.Sprintf("%s for %s", name, plat))), nil
+}
+
+var _ = Describe("Write", func() {
+	var (
+		dir    string
+		source fakeSource
+	)
+
+	linux := platform.Platform{Os: "linux", Arch: "amd64"}
+	darwin := platform.Platform{Os: "darwin", Arch: "arm64"}
+
+	provider := func(name, provider, v string) manifest.TerraformProvider {
+		return manifest.TerraformProvider{
+			Name:     name,
+			Version:  version.Must(version.NewVersion(v)),
+			Provider: tfproviderfqn.Must(name, provider),
+		}
+	}
+
+	readJSON := func(path string) map[string]any {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		var result map[string]any
+		Expect(json.Unmarshal(data, &result)).To(Succeed())
+		return result
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		source = fakeSource{manifest: &manifest.Manifest{
+			Platforms: []platform.Platform{linux, darwin},
+			TerraformProviders: []manifest.TerraformProvider{
+				provider("terraform-provider-random", "hashicorp/random", "3.1.0"),
+				provider("terraform-provider-mysql", "petoju/mysql", "3.0.12"),
+			},
+		}}
+	})
+
+	It("writes the providers in the packed mirror layout", func() {
+		result, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Directory).To(Equal(dir))
+		Expect(result.Providers).To(ConsistOf(
+			mirror.Provider{Address: "registry.terraform.io/hashicorp/random", Version: "3.1.0", Platforms: []string{"linux_amd64", "darwin_arm64"}},
+			mirror.Provider{Address: "registry.terraform.io/petoju/mysql", Version: "3.0.12", Platforms: []string{"linux_amd64", "darwin_arm64"}},
+		))
+
+		archivePath := filepath.Join(dir, "registry.terraform.io", "hashicorp", "random", "terraform-provider-random_3.1.0_linux_amd64.zip")
+		zr, err := zip.OpenReader(archivePath)
+		Expect(err).NotTo(HaveOccurred())
+		defer zr.Close()
+		Expect(zr.File).To(HaveLen(1))
+		Expect(zr.File[0].Name).To(Equal("terraform-provider-random_v3.1.0"))
+		Expect(zr.File[0].Mode().Perm()).To(Equal(os.FileMode(0o755)))
+
+		rc, err := zr.File[0].Open()
+		Expect(err).NotTo(HaveOccurred())
+		defer rc.Close()
+		Expect(io.ReadAll(rc)).To(BeEquivalentTo("terraform-provider-random_v3.1.0 for linux/amd64"))
+
+		Expect(filepath.Join(dir, "registry.terraform.io", "petoju", "mysql", "terraform-provider-mysql_3.0.12_darwin_arm64.zip")).To(BeAnExistingFile())
+	})
+
+	It("writes the network mirror index files", func() {
+		_, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		providerDir := filepath.Join(dir, "registry.terraform.io", "hashicorp", "random")
+		Expect(readJSON(filepath.Join(providerDir, "index.json"))).To(Equal(map[string]any{
+			"versions": map[string]any{"3.1.0": map[string]any{}},
+		}))
+
+		data, err := os.ReadFile(filepath.Join(providerDir, "terraform-provider-random_3.1.0_linux_amd64.zip"))
+		Expect(err).NotTo(HaveOccurred())
+		sum := sha256.Sum256(data)
+
+		versionIndex := readJSON(filepath.Join(providerDir, "3.1.0.json"))
+		Expect(versionIndex).To(HaveKeyWithValue("archives", HaveKeyWithValue("linux_amd64", SatisfyAll(
+			HaveKeyWithValue("url", "terraform-provider-random_3.1.0_linux_amd64.zip"),
+			HaveKeyWithValue("hashes", ConsistOf(
+				MatchRegexp(`^h1:[A-Za-z0-9+/]{43}=$`),
+				"zh:"+hex.EncodeToString(sum[:]),
+			)),
+		))))
+		Expect(versionIndex).To(HaveKeyWithValue("archives", HaveKey("darwin_arm64")))
+	})
+
+	It("writes archives that do not depend on when they were written", func() {
+		other := GinkgoT().TempDir()
+		_, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = mirror.Write(source, other)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.ReadFile(filepath.Join(dir, mirror.ChecksumsFileName))).To(Equal(must(os.ReadFile(filepath.Join(other, mirror.ChecksumsFileName)))))
+	})
+
+	It("writes the checksums of the archives", func() {
+		_, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(filepath.Join(dir, "registry.terraform.io", "petoju", "mysql", "terraform-provider-mysql_3.0.12_linux_amd64.zip"))
+		Expect(err).NotTo(HaveOccurred())
+		sum := sha256.Sum256(data)
+
+		checksums, err := os.ReadFile(filepath.Join(dir, mirror.ChecksumsFileName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Split(strings.TrimSpace(string(checksums)), "\n")).To(HaveLen(4))
+		Expect(string(checksums)).To(ContainSubstring(hex.EncodeToString(sum[:]) + "  registry.terraform.io/petoju/mysql/terraform-provider-mysql_3.0.12_linux_amd64.zip\n"))
+	})
+
+	It("keeps providers already in the mirror", func() {
+		_, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		source.manifest.Platforms = []platform.Platform{linux}
+		source.manifest.TerraformProviders = []manifest.TerraformProvider{provider("terraform-provider-random", "hashicorp/random", "3.2.0")}
+		result, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Addresses()).To(Equal([]string{"registry.terraform.io/hashicorp/random"}))
+
+		Expect(readJSON(filepath.Join(dir, "registry.terraform.io", "hashicorp", "random", "index.json"))).To(Equal(map[string]any{
+			"versions": map[string]any{"3.1.0": map[string]any{}, "3.2.0": map[string]any{}},
+		}))
+
+		checksums, err := os.ReadFile(filepath.Join(dir, mirror.ChecksumsFileName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Split(strings.TrimSpace(string(checksums)), "\n")).To(HaveLen(5))
+	})
+
+	It("writes an OpenTofu CLI configuration that uses the mirror", func() {
+		_, err := mirror.Write(source, dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		config, err := os.ReadFile(filepath.Join(dir, mirror.CLIConfigFileName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(config)).To(ContainSubstring(fmt.Sprintf("filesystem_mirror {\n    path    = %q\n    include = [\n      \"registry.terraform.io/hashicorp/random\",\n      \"registry.terraform.io/petoju/mysql\",\n    ]\n  }", dir)))
+		Expect(string(config)).To(ContainSubstring("direct {\n    exclude = [\n      \"registry.terraform.io/hashicorp/random\","))
+		Expect(string(config)).To(ContainSubstring(fmt.Sprintf("export TF_CLI_CONFIG_FILE=%q", filepath.Join(dir, mirror.CLIConfigFileName))))
+	})
+})
+
+func must[A any](a A, err error) A {
+	Expect(err).NotTo(HaveOccurred())
+	return a
+}
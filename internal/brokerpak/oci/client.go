@@ -0,0 +1,491 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ArtifactType identifies brokerpaks in an OCI registry
+	ArtifactType = "application/vnd.cloudfoundry.brokerpak.v1"
+	// LayerMediaType is the media type of the layer that holds the brokerpak file
+	LayerMediaType = "application/vnd.cloudfoundry.brokerpak.layer.v1+zip"
+
+	manifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	emptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+	titleAnnotation      = "org.opencontainers.image.title"
+)
+
+const (
+	// responseHeaderTimeout limits how long a registry can take to start responding to a request
+	responseHeaderTimeout = 30 * time.Second
+	// requestTimeout limits how long a request can take, including transferring a brokerpak
+	requestTimeout = 30 * time.Minute
+)
+
+var emptyConfig = []byte("{}")
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	ArtifactType  string       `json:"artifactType,omitempty"`
+	Config        descriptor   `json:"config"`
+	Layers        []descriptor `json:"layers"`
+}
+
+// Client talks to OCI registries using the distribution API
+type Client struct {
+	credentials Credentials
+	httpClient  *http.Client
+
+	lock           sync.Mutex
+	authorizations map[string]string
+}
+
+// NewClient creates a client that logs in to registries with the given credentials
+func NewClient(credentials Credentials) *Client {
+	return &Client{
+		credentials:    credentials,
+		httpClient:     newHTTPClient(),
+		authorizations: make(map[string]string),
+	}
+}
+
+// newHTTPClient creates a client that does not wait forever for a registry that stops responding
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = responseHeaderTimeout
+	return &http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+	}
+}
+
+// Pull downloads the brokerpak referenced by ref to the file dest. The manifest is checked against the digest
+// in the reference if there is one, and the brokerpak is always checked against the digest in the manifest.
+func (c *Client) Pull(ctx context.Context, ref Reference, dest string) error {
+	body, err := c.getManifest(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	if ref.Digest != "" {
+		if actual := digestOf(body); actual != ref.Digest {
+			return fmt.Errorf("manifest digest mismatch for %s: got %s", ref, actual)
+		}
+	}
+
+	var m manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return fmt.Errorf("error parsing manifest for %s: %w", ref, err)
+	}
+
+	layer, err := brokerpakLayer(m)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ref, err)
+	}
+
+	return c.downloadBlob(ctx, ref, layer, dest)
+}
+
+// Push uploads the brokerpak file at path to the tag in ref, and returns the digest of the manifest so that
+// the brokerpak can be referenced immutably
+func (c *Client) Push(ctx context.Context, ref Reference, path string) (string, error) {
+	if ref.Digest != "" {
+		return "", fmt.Errorf("cannot push to %s: the reference must have a tag and no digest", ref)
+	}
+
+	layer, err := fileDescriptor(path)
+	if err != nil {
+		return "", err
+	}
+
+	open := func() (io.ReadCloser, error) { return os.Open(path) }
+	if err := c.uploadBlob(ctx, ref, layer, open); err != nil {
+		return "", err
+	}
+
+	config := descriptor{MediaType: emptyConfigMediaType, Digest: digestOf(emptyConfig), Size: int64(len(emptyConfig))}
+	openConfig := func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(emptyConfig)), nil }
+	if err := c.uploadBlob(ctx, ref, config, openConfig); err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(manifest{
+		SchemaVersion: 2,
+		MediaType:     manifestMediaType,
+		ArtifactType:  ArtifactType,
+		Config:        config,
+		Layers:        []descriptor{layer},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.do(ctx, ref, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(ref, "manifests", ref.Tag), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", manifestMediaType)
+		return req, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return "", unexpectedStatus("pushing manifest for", ref, res)
+	}
+
+	return digestOf(body), nil
+}
+
+func (c *Client) getManifest(ctx context.Context, ref Reference) ([]byte, error) {
+	res, err := c.do(ctx, ref, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ref, "manifests", ref.manifestReference()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", manifestMediaType)
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus("getting manifest for", ref, res)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func (c *Client) downloadBlob(ctx context.Context, ref Reference, layer descriptor, dest string) error {
+	res, err := c.do(ctx, ref, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, c.url(ref, "blobs", layer.Digest), nil)
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return unexpectedStatus("getting brokerpak layer for", ref, res)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), res.Body); err != nil {
+		return fmt.Errorf("error downloading brokerpak layer for %s: %w", ref, err)
+	}
+
+	if actual := "sha256:" + hex.EncodeToString(h.Sum(nil)); actual != layer.Digest {
+		_ = os.Remove(dest)
+		return fmt.Errorf("brokerpak layer digest mismatch for %s: expected %s, got %s", ref, layer.Digest, actual)
+	}
+
+	return nil
+}
+
+func (c *Client) uploadBlob(ctx context.Context, ref Reference, blob descriptor, open func() (io.ReadCloser, error)) error {
+	head, err := c.do(ctx, ref, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodHead, c.url(ref, "blobs", blob.Digest), nil)
+	})
+	if err != nil {
+		return err
+	}
+	head.Body.Close()
+	if head.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	res, err := c.do(ctx, ref, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodPost, c.url(ref, "blobs", "uploads")+"/", nil)
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return unexpectedStatus("starting blob upload for", ref, res)
+	}
+
+	location, err := res.Request.URL.Parse(res.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid blob upload location for %s: %w", ref, err)
+	}
+	query := location.Query()
+	query.Set("digest", blob.Digest)
+	location.RawQuery = query.Encode()
+
+	put, err := c.do(ctx, ref, func() (*http.Request, error) {
+		body, err := open()
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, location.String(), body)
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
+		req.ContentLength = blob.Size
+		req.Header.Set("Content-Type", "application/octet-stream")
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer put.Body.Close()
+	if put.StatusCode != http.StatusCreated {
+		return unexpectedStatus("uploading blob for", ref, put)
+	}
+
+	return nil
+}
+
+// do sends the request built by newRequest. When the registry asks for authentication, it logs in with
+// the credentials for the registry and sends the request again, so newRequest must be able to build it twice.
+func (c *Client) do(ctx context.Context, ref Reference, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if authorization := c.authorization(ref.Registry); authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return c.httpClient.Do(req)
+	}
+
+	res, err := send()
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusUnauthorized {
+		return res, nil
+	}
+
+	challenge := res.Header.Get("WWW-Authenticate")
+	res.Body.Close()
+	if err := c.login(ctx, ref, challenge); err != nil {
+		return nil, err
+	}
+
+	return send()
+}
+
+func (c *Client) login(ctx context.Context, ref Reference, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	credential, hasCredential := c.credentials[ref.Registry]
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !hasCredential {
+			return fmt.Errorf("registry %q requires authentication, but no credentials are configured for it", ref.Registry)
+		}
+		c.setAuthorization(ref.Registry, "Basic "+basicAuth(credential))
+		return nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, credential, hasCredential)
+		if err != nil {
+			return fmt.Errorf("error logging in to registry %q: %w", ref.Registry, err)
+		}
+		c.setAuthorization(ref.Registry, "Bearer "+token)
+		return nil
+	default:
+		return fmt.Errorf("registry %q requires authentication with an unsupported scheme %q", ref.Registry, scheme)
+	}
+}
+
+func (c *Client) fetchToken(ctx context.Context, params map[string]string, credential Credential, hasCredential bool) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	// The credentials are sent to the realm, so like the registry itself it must use HTTPS unless it is local
+	if realm.Scheme != "https" && (realm.Scheme != "http" || !isLocal(realm.Host)) {
+		return "", fmt.Errorf("token realm %q does not use HTTPS", params["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCredential {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed with status %d", res.StatusCode)
+	}
+
+	var receiver struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&receiver); err != nil {
+		return "", fmt.Errorf("error parsing token response: %w", err)
+	}
+
+	switch {
+	case receiver.Token != "":
+		return receiver.Token, nil
+	case receiver.AccessToken != "":
+		return receiver.AccessToken, nil
+	default:
+		return "", errors.New("token response did not contain a token")
+	}
+}
+
+func (c *Client) authorization(registry string) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.authorizations[registry]
+}
+
+func (c *Client) setAuthorization(registry, authorization string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.authorizations[registry] = authorization
+}
+
+// url builds a distribution API URL. Like other registry clients, plain HTTP is used for registries on
+// the local machine, so that a local registry can be used for testing.
+func (c *Client) url(ref Reference, kind, name string) string {
+	scheme := "https"
+	if isLocal(ref.Registry) {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s/%s", scheme, ref.Registry, ref.Repository, kind, name)
+}
+
+func isLocal(registry string) bool {
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func brokerpakLayer(m manifest) (descriptor, error) {
+	for _, l := range m.Layers {
+		if l.MediaType == LayerMediaType {
+			return l, nil
+		}
+	}
+	return descriptor{}, fmt.Errorf("manifest has no layer with media type %q", LayerMediaType)
+}
+
+func fileDescriptor(path string) (descriptor, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return descriptor{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return descriptor{}, fmt.Errorf("error reading %q: %w", path, err)
+	}
+
+	return descriptor{
+		MediaType:   LayerMediaType,
+		Digest:      "sha256:" + hex.EncodeToString(h.Sum(nil)),
+		Size:        size,
+		Annotations: map[string]string{titleAnnotation: filepath.Base(path)},
+	}, nil
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func unexpectedStatus(action string, ref Reference, res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("error %s %s: unexpected status %d: %s", action, ref, res.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// parseChallenge parses a WWW-Authenticate header such as: Bearer realm="https://auth.example.com/token",service="registry"
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimLeft(rest, ", ") {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			params[key], rest, _ = strings.Cut(value, ",")
+		}
+	}
+
+	return scheme, params
+}
+
+func basicAuth(c Credential) string {
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+}
@@ -0,0 +1,175 @@
+package oci_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		registry *fakeRegistry
+		pakPath  string
+		pakData  []byte
+		tempDir  string
+	)
+
+	BeforeEach(func() {
+		registry = newFakeRegistry()
+		DeferCleanup(registry.close)
+
+		tempDir = GinkgoT().TempDir()
+		pakData = []byte("fake brokerpak contents")
+		pakPath = filepath.Join(tempDir, "my-pak.brokerpak")
+		Expect(os.WriteFile(pakPath, pakData, 0o600)).To(Succeed())
+	})
+
+	reference := func(suffix string) oci.Reference {
+		ref, err := oci.ParseReference("oci://" + registry.host() + "/paks/my-pak" + suffix)
+		Expect(err).NotTo(HaveOccurred())
+		return ref
+	}
+
+	push := func(client *oci.Client) string {
+		digest, err := client.Push(context.Background(), reference(":1.0.0"), pakPath)
+		Expect(err).NotTo(HaveOccurred())
+		return digest
+	}
+
+	It("pushes a brokerpak as an OCI artifact", func() {
+		digest := push(oci.NewClient(nil))
+
+		manifest := registry.manifest("paks/my-pak:1.0.0")
+		Expect(sha256Digest(manifest)).To(Equal(digest))
+
+		var receiver struct {
+			ArtifactType string `json:"artifactType"`
+			Layers       []struct {
+				MediaType   string            `json:"mediaType"`
+				Digest      string            `json:"digest"`
+				Size        int               `json:"size"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"layers"`
+		}
+		Expect(json.Unmarshal(manifest, &receiver)).To(Succeed())
+		Expect(receiver.ArtifactType).To(Equal(oci.ArtifactType))
+		Expect(receiver.Layers).To(HaveLen(1))
+		Expect(receiver.Layers[0].MediaType).To(Equal(oci.LayerMediaType))
+		Expect(receiver.Layers[0].Digest).To(Equal(sha256Digest(pakData)))
+		Expect(receiver.Layers[0].Size).To(Equal(len(pakData)))
+		Expect(receiver.Layers[0].Annotations).To(HaveKeyWithValue("org.opencontainers.image.title", "my-pak.brokerpak"))
+		Expect(registry.blob(sha256Digest(pakData))).To(Equal(pakData))
+	})
+
+	It("does not push to a digest", func() {
+		_, err := oci.NewClient(nil).Push(context.Background(), reference("@"+sha256Digest(pakData)), pakPath)
+		Expect(err).To(MatchError(ContainSubstring("the reference must have a tag and no digest")))
+	})
+
+	It("pulls a brokerpak by tag", func() {
+		push(oci.NewClient(nil))
+
+		dest := filepath.Join(tempDir, "pulled", "pack.brokerpak")
+		Expect(oci.NewClient(nil).Pull(context.Background(), reference(":1.0.0"), dest)).To(Succeed())
+		Expect(os.ReadFile(dest)).To(Equal(pakData))
+	})
+
+	It("pulls a brokerpak pinned by digest", func() {
+		digest := push(oci.NewClient(nil))
+
+		dest := filepath.Join(tempDir, "pack.brokerpak")
+		Expect(oci.NewClient(nil).Pull(context.Background(), reference(":1.0.0@"+digest), dest)).To(Succeed())
+		Expect(os.ReadFile(dest)).To(Equal(pakData))
+	})
+
+	It("fails when the manifest does not match the pinned digest", func() {
+		push(oci.NewClient(nil))
+		other := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		registry.setManifest("paks/my-pak@"+other, registry.manifest("paks/my-pak:1.0.0"))
+
+		dest := filepath.Join(tempDir, "pack.brokerpak")
+		err := oci.NewClient(nil).Pull(context.Background(), reference("@"+other), dest)
+		Expect(err).To(MatchError(ContainSubstring("manifest digest mismatch")))
+		Expect(dest).NotTo(BeAnExistingFile())
+	})
+
+	It("fails when the brokerpak does not match the digest in the manifest", func() {
+		push(oci.NewClient(nil))
+		registry.setBlob(sha256Digest(pakData), []byte("tampered"))
+
+		dest := filepath.Join(tempDir, "pack.brokerpak")
+		err := oci.NewClient(nil).Pull(context.Background(), reference(":1.0.0"), dest)
+		Expect(err).To(MatchError(ContainSubstring("brokerpak layer digest mismatch")))
+		Expect(dest).NotTo(BeAnExistingFile())
+	})
+
+	It("fails when the manifest has no brokerpak layer", func() {
+		push(oci.NewClient(nil))
+		manifest := strings.Replace(string(registry.manifest("paks/my-pak:1.0.0")), oci.LayerMediaType, "application/vnd.oci.image.layer.v1.tar+gzip", 1)
+		registry.setManifest("paks/my-pak:1.0.0", []byte(manifest))
+
+		dest := filepath.Join(tempDir, "pack.brokerpak")
+		err := oci.NewClient(nil).Pull(context.Background(), reference(":1.0.0"), dest)
+		Expect(err).To(MatchError(ContainSubstring(`manifest has no layer with media type "` + oci.LayerMediaType + `"`)))
+		Expect(dest).NotTo(BeAnExistingFile())
+	})
+
+	It("fails when the tag does not exist", func() {
+		err := oci.NewClient(nil).Pull(context.Background(), reference(":2.0.0"), filepath.Join(tempDir, "pack.brokerpak"))
+		Expect(err).To(MatchError(ContainSubstring("error getting manifest for oci://" + registry.host() + "/paks/my-pak:2.0.0: unexpected status 404")))
+	})
+
+	When("the registry requires authentication", func() {
+		BeforeEach(func() {
+			registry.requireToken("user", "secret", "fake-token")
+		})
+
+		It("logs in with the credentials for the registry", func() {
+			client := oci.NewClient(oci.Credentials{registry.host(): {Username: "user", Password: "secret"}})
+			push(client)
+
+			dest := filepath.Join(tempDir, "pack.brokerpak")
+			Expect(client.Pull(context.Background(), reference(":1.0.0"), dest)).To(Succeed())
+			Expect(os.ReadFile(dest)).To(Equal(pakData))
+		})
+
+		It("fails with the wrong credentials", func() {
+			client := oci.NewClient(oci.Credentials{registry.host(): {Username: "user", Password: "wrong"}})
+
+			_, err := client.Push(context.Background(), reference(":1.0.0"), pakPath)
+			Expect(err).To(MatchError(ContainSubstring("error logging in to registry")))
+		})
+
+		It("refuses to send the credentials to a token realm without HTTPS", func() {
+			registry.realm = "http://auth.example.com/token"
+			client := oci.NewClient(oci.Credentials{registry.host(): {Username: "user", Password: "secret"}})
+
+			_, err := client.Push(context.Background(), reference(":1.0.0"), pakPath)
+			Expect(err).To(MatchError(ContainSubstring(`token realm "http://auth.example.com/token" does not use HTTPS`)))
+		})
+	})
+
+	Describe("Getter", func() {
+		It("fetches oci:// sources with go-getter", func() {
+			push(oci.NewClient(nil))
+
+			dest := filepath.Join(tempDir, "fetched.brokerpak")
+			client := &getter.Client{
+				Src:     "oci://" + registry.host() + "/paks/my-pak:1.0.0",
+				Dst:     dest,
+				Mode:    getter.ClientModeFile,
+				Getters: map[string]getter.Getter{oci.Scheme: oci.NewGetter(nil)},
+			}
+			Expect(client.Get()).To(Succeed())
+			Expect(os.ReadFile(dest)).To(Equal(pakData))
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Credential is a username and password, or token, used to log in to a registry
+type Credential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Credentials maps registry hosts, including the port if there is one, to their credentials
+type Credentials map[string]Credential
+
+// ParseCredentials parses a JSON object of registry hosts to credentials, for example:
+// {"registry.example.com": {"username": "user", "password": "secret"}}
+func ParseCredentials(data string) (Credentials, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var result Credentials
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, fmt.Errorf("invalid registry credentials: %w", err)
+	}
+
+	for registry, c := range result {
+		if c.Username == "" && c.Password == "" {
+			return nil, fmt.Errorf("invalid registry credentials: no username or password for %q", registry)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/hashicorp/go-getter"
+)
+
+// Getter lets go-getter fetch oci:// brokerpak sources
+type Getter struct {
+	client *getter.Client
+	oci    *Client
+}
+
+var _ getter.Getter = (*Getter)(nil)
+
+// NewGetter creates a Getter that logs in to registries with the given credentials
+func NewGetter(credentials Credentials) *Getter {
+	return &Getter{oci: NewClient(credentials)}
+}
+
+// ClientMode implements getter.Getter. A reference is always a single brokerpak file.
+func (g *Getter) ClientMode(*url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeFile, nil
+}
+
+// Get implements getter.Getter
+func (g *Getter) Get(string, *url.URL) error {
+	return errors.New("an OCI reference is a single brokerpak file and cannot be fetched as a directory")
+}
+
+// GetFile implements getter.Getter
+func (g *Getter) GetFile(dst string, u *url.URL) error {
+	ref, err := ParseReference(u.String())
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if g.client != nil && g.client.Ctx != nil {
+		ctx = g.client.Ctx
+	}
+
+	return g.oci.Pull(ctx, ref, dst)
+}
+
+// SetClient implements getter.Getter
+func (g *Getter) SetClient(c *getter.Client) {
+	g.client = c
+}
@@ -0,0 +1,89 @@
+// Package oci pulls brokerpaks from, and pushes them to, OCI registries as artifacts
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Scheme is the URI scheme of brokerpak sources in an OCI registry
+const Scheme = "oci"
+
+const defaultTag = "latest"
+
+var (
+	digestRegex = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+	tagRegex    = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+)
+
+// Reference identifies a brokerpak in an OCI registry, in the form oci://registry/repository[:tag][@digest].
+// When a digest is given, the pulled manifest must match it.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an oci:// brokerpak source
+func ParseReference(ref string) (Reference, error) {
+	rest, ok := strings.CutPrefix(ref, Scheme+"://")
+	if !ok {
+		return Reference{}, fmt.Errorf("OCI reference %q must start with %s://", ref, Scheme)
+	}
+
+	registry, repository, ok := strings.Cut(rest, "/")
+	if !ok || registry == "" || repository == "" {
+		return Reference{}, fmt.Errorf("OCI reference %q must be in the form %s://registry/repository[:tag][@digest]", ref, Scheme)
+	}
+
+	var result Reference
+	result.Registry = registry
+
+	if name, digest, ok := strings.Cut(repository, "@"); ok {
+		if !digestRegex.MatchString(digest) {
+			return Reference{}, fmt.Errorf("OCI reference %q has an invalid digest, expected sha256:<64 hex characters>", ref)
+		}
+		repository = name
+		result.Digest = digest
+	}
+
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		result.Tag = repository[i+1:]
+		repository = repository[:i]
+		if !tagRegex.MatchString(result.Tag) {
+			return Reference{}, fmt.Errorf("OCI reference %q has an invalid tag %q", ref, result.Tag)
+		}
+	}
+
+	if repository == "" || repository != strings.ToLower(repository) {
+		return Reference{}, fmt.Errorf("OCI reference %q has an invalid repository, it must be lower case", ref)
+	}
+	result.Repository = repository
+
+	if result.Tag == "" && result.Digest == "" {
+		result.Tag = defaultTag
+	}
+
+	return result, nil
+}
+
+func (r Reference) String() string {
+	s := fmt.Sprintf("%s://%s/%s", Scheme, r.Registry, r.Repository)
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// manifestReference is the digest when there is one, as that pins the content, otherwise the tag
+func (r Reference) manifestReference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
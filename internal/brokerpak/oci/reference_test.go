@@ -0,0 +1,67 @@
+package oci_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
+)
+
+var _ = Describe("ParseReference", func() {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	DescribeTable(
+		"valid references",
+		func(input string, expected oci.Reference) {
+			ref, err := oci.ParseReference(input)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref).To(Equal(expected))
+		},
+		Entry("tag", "oci://registry.example.com/paks/my-pak:1.0.0", oci.Reference{Registry: "registry.example.com", Repository: "paks/my-pak", Tag: "1.0.0"}),
+		Entry("no tag", "oci://registry.example.com/my-pak", oci.Reference{Registry: "registry.example.com", Repository: "my-pak", Tag: "latest"}),
+		Entry("port", "oci://localhost:5000/my-pak:v1", oci.Reference{Registry: "localhost:5000", Repository: "my-pak", Tag: "v1"}),
+		Entry("digest", "oci://registry.example.com/my-pak@"+digest, oci.Reference{Registry: "registry.example.com", Repository: "my-pak", Digest: digest}),
+		Entry("tag and digest", "oci://registry.example.com/my-pak:1.0.0@"+digest, oci.Reference{Registry: "registry.example.com", Repository: "my-pak", Tag: "1.0.0", Digest: digest}),
+	)
+
+	DescribeTable(
+		"invalid references",
+		func(input, expectedError string) {
+			_, err := oci.ParseReference(input)
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("wrong scheme", "https://registry.example.com/my-pak", "must start with oci://"),
+		Entry("no repository", "oci://registry.example.com", "must be in the form"),
+		Entry("bad digest", "oci://registry.example.com/my-pak@sha256:abc", "invalid digest"),
+		Entry("bad tag", "oci://registry.example.com/my-pak:-bad", `invalid tag "-bad"`),
+		Entry("upper case repository", "oci://registry.example.com/My-Pak", "must be lower case"),
+	)
+
+	It("formats the reference", func() {
+		ref, err := oci.ParseReference("oci://registry.example.com/my-pak:1.0.0@" + digest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref.String()).To(Equal("oci://registry.example.com/my-pak:1.0.0@" + digest))
+	})
+})
+
+var _ = Describe("ParseCredentials", func() {
+	It("parses credentials by registry", func() {
+		credentials, err := oci.ParseCredentials(`{"registry.example.com":{"username":"user","password":"secret"}}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials).To(Equal(oci.Credentials{"registry.example.com": {Username: "user", Password: "secret"}}))
+	})
+
+	It("accepts no credentials", func() {
+		Expect(oci.ParseCredentials("")).To(BeNil())
+	})
+
+	It("fails for invalid JSON", func() {
+		_, err := oci.ParseCredentials(`not-json`)
+		Expect(err).To(MatchError(ContainSubstring("invalid registry credentials")))
+	})
+
+	It("fails for empty credentials", func() {
+		_, err := oci.ParseCredentials(`{"registry.example.com":{}}`)
+		Expect(err).To(MatchError(`invalid registry credentials: no username or password for "registry.example.com"`))
+	})
+})
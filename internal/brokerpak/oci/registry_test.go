@@ -0,0 +1,153 @@
+package oci_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// fakeRegistry is an in-memory stand-in for an OCI registry that implements enough of the
+// distribution API to push and pull artifacts. When a token is set, requests need a bearer token
+// that is issued to clients logging in with the username and password.
+type fakeRegistry struct {
+	server *httptest.Server
+
+	username, password, token string
+	// realm overrides where clients are told to request tokens from
+	realm string
+
+	lock      sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	r := &fakeRegistry{
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string][]byte),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+func (r *fakeRegistry) host() string {
+	return strings.TrimPrefix(r.server.URL, "http://")
+}
+
+func (r *fakeRegistry) close() {
+	r.server.Close()
+}
+
+func (r *fakeRegistry) requireToken(username, password, token string) {
+	r.username, r.password, r.token = username, password, token
+}
+
+func (r *fakeRegistry) blob(digest string) []byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.blobs[digest]
+}
+
+func (r *fakeRegistry) setBlob(digest string, data []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.blobs[digest] = data
+}
+
+func (r *fakeRegistry) manifest(repoAndReference string) []byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.manifests[repoAndReference]
+}
+
+func (r *fakeRegistry) setManifest(repoAndReference string, data []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.manifests[repoAndReference] = data
+}
+
+func (r *fakeRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != r.username || password != r.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"token":%q}`, r.token)
+		return
+	}
+
+	if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		realm := r.realm
+		if realm == "" {
+			realm = r.server.URL + "/token"
+		}
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s",service="fake",scope="repository:paks:pull,push"`, realm))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch {
+	case strings.HasSuffix(path, "/blobs/uploads/") && req.Method == http.MethodPost:
+		r.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/upload/%d?state=abc", r.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/") && (req.Method == http.MethodGet || req.Method == http.MethodHead):
+		digest := path[strings.LastIndex(path, "/")+1:]
+		data, ok := r.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case strings.HasPrefix(req.URL.Path, "/upload/") && req.Method == http.MethodPut:
+		if req.URL.Query().Get("state") != "abc" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(req.Body)
+		digest := req.URL.Query().Get("digest")
+		if digest != sha256Digest(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.blobs[digest] = data
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/manifests/") && req.Method == http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		repository, reference, _ := strings.Cut(path, "/manifests/")
+		r.manifests[repository+":"+reference] = data
+		r.manifests[repository+"@"+sha256Digest(data)] = data
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/manifests/") && req.Method == http.MethodGet:
+		repository, reference, _ := strings.Cut(path, "/manifests/")
+		separator := ":"
+		if strings.HasPrefix(reference, "sha256:") {
+			separator = "@"
+		}
+		data, ok := r.manifests[repository+separator+reference]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"MANIFEST_UNKNOWN"}]}`)
+			return
+		}
+		w.Write(data)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
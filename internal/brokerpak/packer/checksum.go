@@ -0,0 +1,99 @@
+package packer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
+)
+
+// checkChecksumPlatforms fails when a checksum is for a platform that is not packed, as that checksum would never
+// be verified, which is usually a typo in the platform
+func checkChecksumPlatforms(m *manifest.Manifest) error {
+	platforms := make(map[string]struct{})
+	for _, plat := range m.Platforms {
+		platforms[plat.String()] = struct{}{}
+	}
+
+	check := func(name string, checksums map[string]string) error {
+		for _, plat := range slices.Sorted(maps.Keys(checksums)) {
+			if _, ok := platforms[plat]; !ok {
+				return fmt.Errorf("sha256 checksum of %q is for platform %q, which is not in the platforms of the manifest", name, plat)
+			}
+		}
+		return nil
+	}
+
+	for _, resource := range m.TerraformVersions {
+		if err := check(fmt.Sprintf("%s %s", binaryName, resource.Version), resource.SHA256); err != nil {
+			return err
+		}
+	}
+	for _, resource := range m.TerraformProviders {
+		if err := check(fmt.Sprintf("%s %s", resource.Name, resource.Version), resource.SHA256); err != nil {
+			return err
+		}
+	}
+	for _, resource := range m.Binaries {
+		if err := check(fmt.Sprintf("%s %s", resource.Name, resource.Version), resource.SHA256); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyChecksum checks the file in dir starting with prefix against the checksum in the manifest for the platform.
+// On mismatch the cache entry for the source is evicted, so that the next build fetches the file again.
+func verifyChecksum(checksums map[string]string, plat platform.Platform, dir, prefix, source, cachePath string) error {
+	expected, ok := checksums[plat.String()]
+	if !ok {
+		return nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+"*"))
+	if err != nil {
+		return err
+	}
+	if len(matches) != 1 {
+		return fmt.Errorf("expected exactly one file with prefix %q in %q to verify checksum, found %d", prefix, dir, len(matches))
+	}
+
+	actual, err := fileSHA256(matches[0])
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(actual, expected) {
+		if cachePath != "" {
+			_ = os.RemoveAll(buildCacheKey(cachePath, source))
+		}
+		return fmt.Errorf("sha256 checksum mismatch for %q on platform %q: expected %s, got %s", source, plat, expected, actual)
+	}
+
+	logger.Println("\t", "verified sha256 checksum of", matches[0])
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("error computing checksum of %q: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/brokerpakurl"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/fetcher"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/sbom"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/zippy"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
@@ -37,6 +38,10 @@ func Pack(m *manifest.Manifest, base, dest, cachePath string, includeSource, com
 		logger.Printf("Packing %q version %q with CSB version %q...\n", base, m.Version, utils.Version)
 	}
 
+	if err := checkChecksumPlatforms(m); err != nil {
+		return err
+	}
+
 	dir, err := os.MkdirTemp("", "brokerpak")
 	if err != nil {
 		return err
@@ -63,6 +68,11 @@ func Pack(m *manifest.Manifest, base, dest, cachePath string, includeSource, com
 		return err
 	}
 
+	logger.Println("Generating SBOM...")
+	if err := packSBOM(m, dir); err != nil {
+		return err
+	}
+
 	logger.Println("Creating archive:", dest)
 	return zippy.Archive(dir, dest, compress)
 }
@@ -110,17 +120,30 @@ func packBinaries(m *manifest.Manifest, tmp string, cachePath string) error {
 		p := filepath.Join(tmp, "bin", platform.Os, platform.Arch)
 
 		for _, resource := range m.TerraformVersions {
-			if err := cachedFetchFile(getAny, brokerpakurl.TofuURL(binaryName, resource.Version.String(), resource.URLTemplate, platform), filepath.Join(p, resource.Version.String()), cachePath); err != nil {
+			source := brokerpakurl.TofuURL(binaryName, resource.Version.String(), resource.URLTemplate, platform)
+			destination := filepath.Join(p, resource.Version.String())
+			if err := cachedFetchFile(getAny, source, destination, cachePath); err != nil {
+				return err
+			}
+			if err := verifyChecksum(resource.SHA256, platform, destination, binaryName, source, cachePath); err != nil {
 				return err
 			}
 		}
 		for _, resource := range m.TerraformProviders {
-			if err := cachedFetchFile(getAny, brokerpakurl.HashicorpURL(resource.Name, resource.Version.String(), resource.URLTemplate, platform), p, cachePath); err != nil {
+			source := brokerpakurl.HashicorpURL(resource.Name, resource.Version.String(), resource.URLTemplate, platform)
+			if err := cachedFetchFile(getAny, source, p, cachePath); err != nil {
+				return err
+			}
+			if err := verifyChecksum(resource.SHA256, platform, p, fmt.Sprintf("%s_v%s", resource.Name, resource.Version), source, cachePath); err != nil {
 				return err
 			}
 		}
 		for _, resource := range m.Binaries {
-			if err := cachedFetchFile(getAny, brokerpakurl.HashicorpURL(resource.Name, resource.Version, resource.URLTemplate, platform), p, cachePath); err != nil {
+			source := brokerpakurl.HashicorpURL(resource.Name, resource.Version, resource.URLTemplate, platform)
+			if err := cachedFetchFile(getAny, source, p, cachePath); err != nil {
+				return err
+			}
+			if err := verifyChecksum(resource.SHA256, platform, p, resource.Name, source, cachePath); err != nil {
 				return err
 			}
 		}
@@ -190,6 +213,15 @@ func packDefinitions(m *manifest.Manifest, tmp, base string) error {
 	return nil
 }
 
+func packSBOM(m *manifest.Manifest, tmp string) error {
+	data, err := sbom.New(m).Marshal()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(tmp, sbom.FileName), data, 0600)
+}
+
 func clearRefs(sd *tf.TfServiceDefinitionV1Action) {
 	sd.TemplateRef = ""
 	sd.TemplateRefs = make(map[string]string)
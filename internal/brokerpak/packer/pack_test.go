@@ -1,6 +1,11 @@
 package packer_test
 
 import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 
@@ -89,8 +94,7 @@ var _ = Describe("Pack", func() {
 			Expect(err).ToNot(HaveOccurred())
 			reader, err := zippy.Open(zipOutputFile)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(reader.List()).To(HaveLen(1))
-			Expect(fileNames(reader)).To(ContainElements("manifest.yml"))
+			Expect(fileNames(reader)).To(ConsistOf("manifest.yml", "sbom.cdx.json"))
 
 			data, err := readManifest(reader)
 			Expect(err).NotTo(HaveOccurred())
@@ -177,6 +181,69 @@ var _ = Describe("Pack", func() {
 
 	})
 
+	Context("verifying checksums", func() {
+		var (
+			binaryURL string
+			cachePath string
+		)
+
+		BeforeEach(func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				zw := zip.NewWriter(w)
+				f, err := zw.Create("some-binary")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = f.Write([]byte("dummy-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zw.Close()).To(Succeed())
+			}))
+			DeferCleanup(server.Close)
+
+			binaryURL = server.URL + "/some-binary.zip"
+			cachePath = GinkgoT().TempDir()
+		})
+
+		fakeManifestBinary := func(checksum string) *manifest.Manifest {
+			return &manifest.Manifest{
+				Platforms: []platform.Platform{{Os: "linux", Arch: "amd64"}},
+				Binaries: []manifest.Binary{{
+					Name:        "some-binary",
+					Version:     "1.0.0",
+					URLTemplate: binaryURL,
+					SHA256:      map[string]string{"linux/amd64": checksum},
+				}},
+			}
+		}
+
+		It("packs binaries that match the checksum", func() {
+			zipOutputFile := filepath.Join(GinkgoT().TempDir(), "packdest")
+
+			err := packer.Pack(fakeManifestBinary(sha256Hex("dummy-file")), "testmanifest", zipOutputFile, cachePath, false, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			reader, err := zippy.Open(zipOutputFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileNames(reader)).To(ContainElement("bin/linux/amd64/some-binary"))
+		})
+
+		It("fails the build and evicts the cache entry on mismatch", func() {
+			zipOutputFile := filepath.Join(GinkgoT().TempDir(), "packdest")
+
+			err := packer.Pack(fakeManifestBinary(sha256Hex("something-else")), "testmanifest", zipOutputFile, cachePath, false, false)
+			Expect(err).To(MatchError(ContainSubstring(`sha256 checksum mismatch for "` + binaryURL + `" on platform "linux/amd64"`)))
+			Expect(zipOutputFile).NotTo(BeAnExistingFile())
+			Expect(os.ReadDir(cachePath)).To(BeEmpty())
+		})
+
+		It("fails the build when a checksum is for a platform that is not packed", func() {
+			zipOutputFile := filepath.Join(GinkgoT().TempDir(), "packdest")
+			m := fakeManifestBinary(sha256Hex("dummy-file"))
+			m.Binaries[0].SHA256["linux/arm46"] = sha256Hex("dummy-file")
+
+			err := packer.Pack(m, "testmanifest", zipOutputFile, cachePath, false, false)
+			Expect(err).To(MatchError(`sha256 checksum of "some-binary 1.0.0" is for platform "linux/arm46", which is not in the platforms of the manifest`))
+			Expect(zipOutputFile).NotTo(BeAnExistingFile())
+		})
+	})
 })
 
 func readManifest(reader zippy.ZipReader) ([]byte, error) {
@@ -193,3 +260,8 @@ func fileNames(reader zippy.ZipReader) []string {
 	}
 	return fileNames
 }
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -17,17 +17,25 @@ package reader
 
 import (
 	"archive/zip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/fetcher"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/sbom"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/signature"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/zippy"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils/stream"
@@ -46,8 +54,8 @@ func OpenBrokerPak(pakPath string) (*BrokerPakReader, error) {
 }
 
 // DownloadAndOpenBrokerpak downloads a (potentially remote) brokerpak to
-// the local filesystem and opens it.
-func DownloadAndOpenBrokerpak(pakURI string) (*BrokerPakReader, error) {
+// the local filesystem and opens it. The credentials are used for brokerpaks in OCI registries.
+func DownloadAndOpenBrokerpak(pakURI string, credentials oci.Credentials) (*BrokerPakReader, error) {
 	if isLocalFile(pakURI) {
 		return OpenBrokerPak(pakURI)
 	}
@@ -60,7 +68,7 @@ func DownloadAndOpenBrokerpak(pakURI string) (*BrokerPakReader, error) {
 
 	// Download the brokerpak
 	localLocation := filepath.Join(pakDir, "pack.brokerpak")
-	if err := fetcher.FetchBrokerpak(pakURI, localLocation); err != nil {
+	if err := fetcher.FetchBrokerpak(pakURI, localLocation, credentials); err != nil {
 		return nil, fmt.Errorf("couldn't download brokerpak %q: %v", pakURI, err)
 	}
 
@@ -81,6 +89,20 @@ func (pak *BrokerPakReader) Manifest() (*manifest.Manifest, error) {
 	return manifest.Parse(data)
 }
 
+// SBOM fetches the software bill of materials out of the package. Brokerpaks built
+// before SBOMs were included get one generated from the manifest.
+func (pak *BrokerPakReader) SBOM() ([]byte, error) {
+	if pak.contents.Find(sbom.FileName) != nil {
+		return pak.readBytes(sbom.FileName)
+	}
+
+	mf, err := pak.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	return sbom.New(mf).Marshal()
+}
+
 // Services gets the list of services included in the pack.
 func (pak *BrokerPakReader) Services() ([]tf.TfServiceDefinitionV1, error) {
 	pakManifest, err := pak.Manifest()
@@ -114,11 +136,75 @@ func (pak *BrokerPakReader) Validate() error {
 		if err := svc.Validate(); err != nil {
 			return fmt.Errorf("service %q failed validation: %v", svc.Name, err)
 		}
+
+		warnings, err := svc.ResourceTagWarnings()
+		if err != nil {
+			return err
+		}
+		for _, w := range warnings {
+			log.Printf("warning: %s", w)
+		}
+	}
+
+	return pak.verifyChecksums()
+}
+
+// verifyChecksums checks the embedded binaries against the sha256 checksums in the manifest.
+func (pak *BrokerPakReader) verifyChecksums() error {
+	mf, err := pak.Manifest()
+	if err != nil {
+		return err
+	}
+
+	verify := func(checksums map[string]string, find func(plat platform.Platform) (string, error)) error {
+		for _, key := range slices.Sorted(maps.Keys(checksums)) {
+			filePath, err := find(platform.Parse(key))
+			if err != nil {
+				return err
+			}
+
+			actual, err := pak.fileSHA256(filePath)
+			if err != nil {
+				return err
+			}
+
+			if !strings.EqualFold(actual, checksums[key]) {
+				return fmt.Errorf("sha256 checksum mismatch for %q: expected %s, got %s", filePath, checksums[key], actual)
+			}
+		}
+		return nil
+	}
+
+	for _, r := range mf.TerraformVersions {
+		if err := verify(r.SHA256, func(plat platform.Platform) (string, error) {
+			return pak.findTerraformInZip(plat, r.Version.String())
+		}); err != nil {
+			return err
+		}
+	}
+	for _, r := range mf.TerraformProviders {
+		if err := verify(r.SHA256, func(plat platform.Platform) (string, error) {
+			return pak.findFileInZip(plat, fmt.Sprintf("%s_v%s", r.Name, r.Version))
+		}); err != nil {
+			return err
+		}
+	}
+	for _, r := range mf.Binaries {
+		if err := verify(r.SHA256, func(plat platform.Platform) (string, error) {
+			return pak.findFileInZip(plat, r.Name)
+		}); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// VerifySignature checks that the brokerpak was signed by one of the trusted keys and has not been modified since.
+func (pak *BrokerPakReader) VerifySignature(trustedKeys []ed25519.PublicKey) error {
+	return signature.Verify(pak.contents.List(), trustedKeys)
+}
+
 // Close closes the underlying reader for the BrokerPakReader.
 func (pak *BrokerPakReader) Close() error {
 	pak.contents.Close()
@@ -161,7 +247,7 @@ func (pak *BrokerPakReader) ExtractPlatformBins(destination string) error {
 }
 
 func (pak *BrokerPakReader) extractProvider(r manifest.TerraformProvider, destination string) error {
-	filePath, err := pak.findFileInZip(fmt.Sprintf("%s_v%s", r.Name, r.Version))
+	filePath, err := pak.findFileInZip(platform.CurrentPlatform(), fmt.Sprintf("%s_v%s", r.Name, r.Version))
 	if err != nil {
 		return err
 	}
@@ -173,8 +259,23 @@ func (pak *BrokerPakReader) extractProvider(r manifest.TerraformProvider, destin
 	return nil
 }
 
+// OpenProvider opens the binary of the provider for the given platform, and returns the name of the file
+func (pak *BrokerPakReader) OpenProvider(plat platform.Platform, r manifest.TerraformProvider) (string, io.ReadCloser, error) {
+	filePath, err := pak.findFileInZip(plat, fmt.Sprintf("%s_v%s", r.Name, r.Version))
+	if err != nil {
+		return "", nil, err
+	}
+
+	rc, err := pak.contents.Find(filePath).Open()
+	if err != nil {
+		return "", nil, fmt.Errorf("error opening %q: %w", filePath, err)
+	}
+
+	return path.Base(filePath), rc, nil
+}
+
 func (pak *BrokerPakReader) extractBinary(r manifest.Binary, destination string) error {
-	filePath, err := pak.findFileInZip(r.Name)
+	filePath, err := pak.findFileInZip(platform.CurrentPlatform(), r.Name)
 	if err != nil {
 		return err
 	}
@@ -187,31 +288,34 @@ func (pak *BrokerPakReader) extractBinary(r manifest.Binary, destination string)
 }
 
 func (pak *BrokerPakReader) extractTerraform(r manifest.TerraformVersion, destination string) error {
-	plat := platform.CurrentPlatform()
-	versionedPath := path.Join("bin", plat.Os, plat.Arch, r.Version.String(), binaryName)
-	if pak.fileExistsInZip(versionedPath) {
-		if err := pak.contents.ExtractFile(versionedPath, filepath.Join(destination, "versions", r.Version.String())); err != nil {
-			return fmt.Errorf("error extracting versioned %s binary: %w", binaryName, err)
-		}
+	filePath, err := pak.findTerraformInZip(platform.CurrentPlatform(), r.Version.String())
+	if err != nil {
+		return err
+	}
 
-		return nil
+	if err := pak.contents.ExtractFile(filePath, filepath.Join(destination, "versions", r.Version.String())); err != nil {
+		return fmt.Errorf("error extracting %s binary: %w", binaryName, err)
+	}
+
+	return nil
+}
+
+func (pak *BrokerPakReader) findTerraformInZip(plat platform.Platform, version string) (string, error) {
+	versionedPath := path.Join("bin", plat.Os, plat.Arch, version, binaryName)
+	if pak.fileExistsInZip(versionedPath) {
+		return versionedPath, nil
 	}
 
 	// For compatibility with brokerpaks built with older versions
 	unversionedPath := path.Join("bin", plat.Os, plat.Arch, binaryName)
 	if pak.fileExistsInZip(unversionedPath) {
-		if err := pak.contents.ExtractFile(unversionedPath, filepath.Join(destination, "versions", r.Version.String())); err != nil {
-			return fmt.Errorf("error extracting %s binary: %w", binaryName, err)
-		}
-
-		return nil
+		return unversionedPath, nil
 	}
 
-	return fmt.Errorf("could not find %s version %s in brokerpak", binaryName, r.Version)
+	return "", fmt.Errorf("could not find %s version %s in brokerpak", binaryName, version)
 }
 
-func (pak *BrokerPakReader) findFileInZip(name string) (string, error) {
-	plat := platform.CurrentPlatform()
+func (pak *BrokerPakReader) findFileInZip(plat platform.Platform, name string) (string, error) {
 	prefix := path.Join("bin", plat.Os, plat.Arch, name)
 	var found []string
 
@@ -231,6 +335,26 @@ func (pak *BrokerPakReader) findFileInZip(name string) (string, error) {
 	}
 }
 
+func (pak *BrokerPakReader) fileSHA256(name string) (string, error) {
+	fd := pak.contents.Find(name)
+	if fd == nil {
+		return "", fmt.Errorf("couldn't find the file with the name %q", name)
+	}
+
+	rc, err := fd.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", fmt.Errorf("error computing checksum of %q: %w", name, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func (pak *BrokerPakReader) fileExistsInZip(path string) bool {
 	for _, f := range pak.contents.List() {
 		if f.Name == path {
@@ -1,7 +1,12 @@
 package reader_test
 
 import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -15,6 +20,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/packer"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/reader"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/sbom"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/tfproviderfqn"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/zippy"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
@@ -143,6 +149,100 @@ var _ = Describe("reader", func() {
 		})
 	})
 
+	Describe("Validate", func() {
+		It("verifies the embedded binaries against the checksums in the manifest", func() {
+			pk := fakeBrokerpak(
+				withTerraform("1.6.0"),
+				withProvider("", "terraform-provider-fake", "1.2.3", "x1"),
+				withChecksums(sha256Hex("dummy-file")),
+			)
+
+			pakReader, err := reader.OpenBrokerPak(pk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pakReader.Validate()).To(Succeed())
+		})
+
+		It("fails when an embedded binary does not match its checksum", func() {
+			pk := fakeBrokerpak(
+				withTerraform("1.6.0"),
+				withProvider("", "terraform-provider-fake", "1.2.3", "x1"),
+				withChecksums(sha256Hex("dummy-file")),
+			)
+			replaceFileInZip(pk, "bin/linux/amd64/terraform-provider-fake_v1.2.3_x1", "tampered")
+
+			pakReader, err := reader.OpenBrokerPak(pk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pakReader.Validate()).To(MatchError(fmt.Sprintf(
+				`sha256 checksum mismatch for "bin/linux/amd64/terraform-provider-fake_v1.2.3_x1": expected %s, got %s`,
+				sha256Hex("dummy-file"),
+				sha256Hex("tampered"),
+			)))
+		})
+	})
+
+	Describe("OpenProvider", func() {
+		It("opens the provider binary for the platform", func() {
+			pk := fakeBrokerpak(
+				withTerraform("1.6.0"),
+				withProvider("", "terraform-provider-fake", "1.2.3", "x1"),
+			)
+
+			pakReader, err := reader.OpenBrokerPak(pk)
+			Expect(err).NotTo(HaveOccurred())
+			m, err := pakReader.Manifest()
+			Expect(err).NotTo(HaveOccurred())
+
+			name, rc, err := pakReader.OpenProvider(platform.Platform{Os: "linux", Arch: "amd64"}, m.TerraformProviders[0])
+			Expect(err).NotTo(HaveOccurred())
+			defer rc.Close()
+			Expect(name).To(Equal("terraform-provider-fake_v1.2.3_x1"))
+			Expect(io.ReadAll(rc)).To(BeEquivalentTo("dummy-file"))
+		})
+
+		It("fails when the platform is not in the brokerpak", func() {
+			pk := fakeBrokerpak(
+				withTerraform("1.6.0"),
+				withProvider("", "terraform-provider-fake", "1.2.3", "x1"),
+			)
+
+			pakReader, err := reader.OpenBrokerPak(pk)
+			Expect(err).NotTo(HaveOccurred())
+			m, err := pakReader.Manifest()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = pakReader.OpenProvider(platform.Platform{Os: "windows", Arch: "amd64"}, m.TerraformProviders[0])
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("SBOM", func() {
+		It("reads the SBOM bundled in the brokerpak", func() {
+			pk := fakeBrokerpak(
+				withTerraform("1.6.0"),
+				withProvider("", "terraform-provider-fake", "1.2.3", "x1"),
+			)
+
+			pakReader, err := reader.OpenBrokerPak(pk)
+			Expect(err).NotTo(HaveOccurred())
+
+			data, err := pakReader.SBOM()
+			Expect(err).NotTo(HaveOccurred())
+
+			var bom sbom.BOM
+			Expect(json.Unmarshal(data, &bom)).To(Succeed())
+			Expect(bom.Metadata.Component.Name).To(Equal("fake-brokerpack"))
+			Expect(bom.Metadata.Component.Version).To(Equal("1.0.0"))
+			Expect(bom.Components).To(ConsistOf(
+				HaveField("Name", "tofu"),
+				HaveField("Name", "terraform-provider-fake"),
+			))
+
+			zipReader, err := zippy.Open(pk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zipReader.Find(sbom.FileName)).NotTo(BeNil())
+		})
+	})
+
 	Describe("including source", func() {
 		It("does not include source by default", func() {
 			pk := fakeBrokerpak(withProvider("", "terraform-provider-fake", "1.2.3", "x1"))
@@ -273,8 +373,50 @@ func withMissingProvider(name, providerVersion string) option {
 	}
 }
 
+func withChecksums(checksum string) option {
+	return func(c *config) {
+		for i := range c.manifest.TerraformProviders {
+			c.manifest.TerraformProviders[i].SHA256 = map[string]string{}
+			for _, plat := range c.manifest.Platforms {
+				c.manifest.TerraformProviders[i].SHA256[plat.String()] = checksum
+			}
+		}
+	}
+}
+
 func withSource() option {
 	return func(c *config) {
 		c.includeSource = true
 	}
 }
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func replaceFileInZip(pakPath, name, contents string) {
+	zr, err := zip.OpenReader(pakPath)
+	Expect(err).NotTo(HaveOccurred())
+	defer zr.Close()
+
+	tampered := filepath.Join(GinkgoT().TempDir(), "tampered.brokerpak")
+	out, err := os.Create(tampered)
+	Expect(err).NotTo(HaveOccurred())
+
+	zw := zip.NewWriter(out)
+	for _, f := range zr.File {
+		if f.Name != name {
+			Expect(zw.Copy(f)).To(Succeed())
+			continue
+		}
+
+		w, err := zw.Create(name)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(zw.Close()).To(Succeed())
+	Expect(out.Close()).To(Succeed())
+	Expect(os.Rename(tampered, pakPath)).To(Succeed())
+}
@@ -0,0 +1,147 @@
+// Package sbom generates a CycloneDX software bill of materials for a brokerpak
+package sbom
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+// FileName is the name of the SBOM file in the root of the brokerpak
+const FileName = "sbom.cdx.json"
+
+const (
+	bomFormat   = "CycloneDX"
+	specVersion = "1.5"
+	tofuName    = "tofu"
+	sha256Alg   = "SHA-256"
+)
+
+// BOM is the subset of the CycloneDX 1.5 JSON format that describes a brokerpak
+type BOM struct {
+	BOMFormat   string      `json:"bomFormat"`
+	SpecVersion string      `json:"specVersion"`
+	Version     int         `json:"version"`
+	Metadata    Metadata    `json:"metadata"`
+	Components  []Component `json:"components"`
+}
+
+type Metadata struct {
+	Tools      Tools      `json:"tools"`
+	Component  Component  `json:"component"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+type Component struct {
+	Type               string              `json:"type"`
+	BOMRef             string              `json:"bom-ref,omitempty"`
+	Name               string              `json:"name"`
+	Version            string              `json:"version,omitempty"`
+	Description        string              `json:"description,omitempty"`
+	Hashes             []Hash              `json:"hashes,omitempty"`
+	ExternalReferences []ExternalReference `json:"externalReferences,omitempty"`
+	Properties         []Property          `json:"properties,omitempty"`
+	Components         []Component         `json:"components,omitempty"`
+}
+
+type Hash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type ExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// New builds the SBOM from the brokerpak manifest. It is deterministic, so that building
+// the same manifest twice results in identical brokerpaks.
+func New(m *manifest.Manifest) BOM {
+	bom := BOM{
+		BOMFormat:   bomFormat,
+		SpecVersion: specVersion,
+		Version:     1,
+		Metadata: Metadata{
+			Tools: Tools{Components: []Component{{
+				Type:    "application",
+				Name:    "cloud-service-broker",
+				Version: utils.Version,
+			}}},
+			Component: Component{
+				Type:    "application",
+				BOMRef:  m.Name,
+				Name:    m.Name,
+				Version: m.Version,
+			},
+		},
+		Components: []Component{},
+	}
+
+	for _, p := range m.Platforms {
+		bom.Metadata.Properties = append(bom.Metadata.Properties, Property{Name: "csb:platform", Value: p.String()})
+	}
+
+	for _, r := range m.TerraformVersions {
+		c := component("application", tofuName, r.Version.String(), r.Source, r.URLTemplate, r.SHA256)
+		if r.Default {
+			c.Properties = append(c.Properties, Property{Name: "csb:default", Value: "true"})
+		}
+		bom.Components = append(bom.Components, c)
+	}
+	for _, r := range m.TerraformProviders {
+		c := component("library", r.Name, r.Version.String(), r.Source, r.URLTemplate, r.SHA256)
+		c.Properties = append(c.Properties, Property{Name: "csb:provider", Value: r.Provider.String()})
+		bom.Components = append(bom.Components, c)
+	}
+	for _, r := range m.Binaries {
+		bom.Components = append(bom.Components, component("application", r.Name, r.Version, r.Source, r.URLTemplate, r.SHA256))
+	}
+
+	return bom
+}
+
+// Marshal encodes the SBOM as indented JSON
+func (b BOM) Marshal() ([]byte, error) {
+	return json.MarshalIndent(b, "", "  ")
+}
+
+func component(componentType, name, version, source, urlTemplate string, checksums map[string]string) Component {
+	c := Component{
+		Type:    componentType,
+		BOMRef:  name + "@" + version,
+		Name:    name,
+		Version: version,
+	}
+
+	if source != "" {
+		c.ExternalReferences = append(c.ExternalReferences, ExternalReference{Type: "source-distribution", URL: source})
+	}
+	if urlTemplate != "" {
+		c.ExternalReferences = append(c.ExternalReferences, ExternalReference{Type: "distribution", URL: urlTemplate})
+	}
+	// The binary is different for each platform, so each checksum is the hash of a nested component for the platform
+	for _, plat := range slices.Sorted(maps.Keys(checksums)) {
+		c.Components = append(c.Components, Component{
+			Type:       c.Type,
+			BOMRef:     c.BOMRef + "/" + plat,
+			Name:       name,
+			Version:    version,
+			Hashes:     []Hash{{Alg: sha256Alg, Content: checksums[plat]}},
+			Properties: []Property{{Name: "csb:platform", Value: plat}},
+		})
+	}
+
+	return c
+}
@@ -0,0 +1,90 @@
+package sbom_test
+
+import (
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/sbom"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/tfproviderfqn"
+)
+
+var _ = Describe("SBOM", func() {
+	It("describes the brokerpak, its platforms and bundled binaries", func() {
+		m := &manifest.Manifest{
+			Name:    "fake-brokerpak",
+			Version: "1.2.3",
+			Platforms: []platform.Platform{
+				{Os: "linux", Arch: "amd64"},
+				{Os: "darwin", Arch: "arm64"},
+			},
+			TerraformVersions: []manifest.TerraformVersion{{
+				Version: version.Must(version.NewVersion("1.6.0")),
+				Default: true,
+				Source:  "https://github.com/opentofu/opentofu/archive/refs/tags/v1.6.0.zip",
+			}},
+			TerraformProviders: []manifest.TerraformProvider{{
+				Name:     "terraform-provider-random",
+				Version:  version.Must(version.NewVersion("3.1.0")),
+				Provider: tfproviderfqn.Must("terraform-provider-random", ""),
+				SHA256:   map[string]string{"linux/amd64": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"},
+			}},
+			Binaries: []manifest.Binary{{
+				Name:        "psqlcmd",
+				Version:     "0.1.0",
+				URLTemplate: "https://example.com/${name}_${version}_${os}_${arch}.zip",
+			}},
+		}
+
+		data, err := sbom.New(m).Marshal()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(MatchJSON(`{
+			"bomFormat": "CycloneDX",
+			"specVersion": "1.5",
+			"version": 1,
+			"metadata": {
+				"tools": {"components": [{"type": "application", "name": "cloud-service-broker", "version": "0.0.0"}]},
+				"component": {"type": "application", "bom-ref": "fake-brokerpak", "name": "fake-brokerpak", "version": "1.2.3"},
+				"properties": [
+					{"name": "csb:platform", "value": "linux/amd64"},
+					{"name": "csb:platform", "value": "darwin/arm64"}
+				]
+			},
+			"components": [
+				{
+					"type": "application",
+					"bom-ref": "tofu@1.6.0",
+					"name": "tofu",
+					"version": "1.6.0",
+					"externalReferences": [{"type": "source-distribution", "url": "https://github.com/opentofu/opentofu/archive/refs/tags/v1.6.0.zip"}],
+					"properties": [{"name": "csb:default", "value": "true"}]
+				},
+				{
+					"type": "library",
+					"bom-ref": "terraform-provider-random@3.1.0",
+					"name": "terraform-provider-random",
+					"version": "3.1.0",
+					"properties": [{"name": "csb:provider", "value": "registry.terraform.io/hashicorp/random"}],
+					"components": [{
+						"type": "library",
+						"bom-ref": "terraform-provider-random@3.1.0/linux/amd64",
+						"name": "terraform-provider-random",
+						"version": "3.1.0",
+						"hashes": [{"alg": "SHA-256", "content": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"}],
+						"properties": [{"name": "csb:platform", "value": "linux/amd64"}]
+					}]
+				},
+				{
+					"type": "application",
+					"bom-ref": "psqlcmd@0.1.0",
+					"name": "psqlcmd",
+					"version": "0.1.0",
+					"externalReferences": [{"type": "distribution", "url": "https://example.com/${name}_${version}_${os}_${arch}.zip"}]
+				}
+			]
+		}`))
+	})
+
+})
@@ -0,0 +1,257 @@
+// Package signature signs brokerpaks and verifies their signatures.
+//
+// A signed brokerpak contains a "signature.json" file listing the SHA256 hash of every other file
+// in the brokerpak, along with an ed25519 signature over that list. Keys are PEM encoded, as
+// generated by "openssl genpkey -algorithm ed25519" and "openssl pkey -pubout".
+package signature
+
+import (
+	"archive/zip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileName is the name of the signature file in the root of the brokerpak
+const FileName = "signature.json"
+
+// Signature is the content of the signature file
+type Signature struct {
+	// Files maps the path of each file in the brokerpak to the hex encoded SHA256 hash of its contents
+	Files map[string]string `json:"files"`
+	// Signature is the ed25519 signature of the Digest of the Files
+	Signature []byte `json:"signature"`
+}
+
+// Digest returns the canonical representation of the file hashes that is signed
+func Digest(files map[string]string) []byte {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, p := range paths {
+		fmt.Fprintf(&b, "%s  %s\n", files[p], p)
+	}
+	return []byte(b.String())
+}
+
+// Sign adds a signature file to the brokerpak at the given path, replacing any existing signature
+func Sign(pakPath string, key ed25519.PrivateKey) error {
+	zr, err := zip.OpenReader(pakPath)
+	if err != nil {
+		return fmt.Errorf("couldn't open brokerpak %q: %w", pakPath, err)
+	}
+	defer zr.Close()
+
+	files, err := hashFiles(zr.File)
+	if err != nil {
+		return err
+	}
+	sig := Signature{Files: files, Signature: ed25519.Sign(key, Digest(files))}
+	data, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(pakPath), ".signing-*.brokerpak")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := copyWithSignature(tmp, zr.File, data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("couldn't write signed brokerpak: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), pakPath)
+}
+
+// Verify checks that the files are signed by one of the trusted keys, and that every file
+// matches its signed hash. Files that are not covered by the signature are rejected.
+func Verify(contents []*zip.File, trustedKeys []ed25519.PublicKey) error {
+	var sigFile *zip.File
+	for _, f := range contents {
+		if f.Name == FileName {
+			if sigFile != nil {
+				return fmt.Errorf("duplicate entry %q", FileName)
+			}
+			sigFile = f
+		}
+	}
+	if sigFile == nil {
+		return errors.New("brokerpak is not signed")
+	}
+
+	var sig Signature
+	if err := readJSON(sigFile, &sig); err != nil {
+		return fmt.Errorf("couldn't read signature: %w", err)
+	}
+
+	if !verifiesWithAny(Digest(sig.Files), sig.Signature, trustedKeys) {
+		return errors.New("signature does not match any trusted public key")
+	}
+
+	actual, err := hashFiles(contents)
+	if err != nil {
+		return err
+	}
+	for name, hash := range actual {
+		expected, ok := sig.Files[name]
+		switch {
+		case !ok:
+			return fmt.Errorf("file %q is not covered by the signature", name)
+		case expected != hash:
+			return fmt.Errorf("file %q does not match its signed hash", name)
+		}
+	}
+	for name := range sig.Files {
+		if _, ok := actual[name]; !ok {
+			return fmt.Errorf("signed file %q is missing", name)
+		}
+	}
+
+	return nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 ed25519 private key
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found in private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse private key: %w", err)
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is of type %T, not ed25519", key)
+	}
+	return edKey, nil
+}
+
+// ParsePublicKeys parses one or more concatenated PEM encoded PKIX ed25519 public keys
+func ParsePublicKeys(data []byte) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse public key %d: %w", len(keys), err)
+		}
+
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key %d is of type %T, not ed25519", len(keys), key)
+		}
+		keys = append(keys, edKey)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM data found in public keys")
+	}
+	return keys, nil
+}
+
+func verifiesWithAny(message, sig []byte, keys []ed25519.PublicKey) bool {
+	for _, k := range keys {
+		if ed25519.Verify(k, message, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashFiles hashes every file except the signature. Archives with duplicate entries are rejected, as a
+// reader could extract a different entry to the one that was hashed.
+func hashFiles(contents []*zip.File) (map[string]string, error) {
+	files := make(map[string]string)
+	seen := make(map[string]bool)
+	for _, f := range contents {
+		if seen[f.Name] {
+			return nil, fmt.Errorf("duplicate entry %q", f.Name)
+		}
+		seen[f.Name] = true
+
+		if f.FileInfo().IsDir() || f.Name == FileName {
+			continue
+		}
+
+		hash, err := hashFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't hash %q: %w", f.Name, err)
+		}
+		files[f.Name] = hash
+	}
+	return files, nil
+}
+
+func hashFile(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return json.NewDecoder(rc).Decode(v)
+}
+
+func copyWithSignature(w io.Writer, contents []*zip.File, sig []byte) error {
+	zw := zip.NewWriter(w)
+	for _, f := range contents {
+		if f.Name == FileName {
+			continue
+		}
+		if err := zw.Copy(f); err != nil {
+			return err
+		}
+	}
+
+	fw, err := zw.Create(FileName)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(sig); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
@@ -0,0 +1,190 @@
+package signature_test
+
+import (
+	"archive/zip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/signature"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/zippy"
+)
+
+var _ = Describe("Signature", func() {
+	var (
+		pakPath    string
+		publicKey  ed25519.PublicKey
+		privateKey ed25519.PrivateKey
+	)
+
+	BeforeEach(func() {
+		var err error
+		publicKey, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		pakPath = fakeBrokerpak(map[string]string{
+			"manifest.yml":                  "name: fake",
+			"definitions/service0-fake.yml": "name: fake-service",
+		})
+	})
+
+	It("verifies a signed brokerpak", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{publicKey})).To(Succeed())
+	})
+
+	It("accepts a signature by any of the trusted keys", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{otherKey, publicKey})).To(Succeed())
+	})
+
+	It("rejects an unsigned brokerpak", func() {
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{publicKey})).To(MatchError("brokerpak is not signed"))
+	})
+
+	It("rejects a signature by an untrusted key", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{otherKey})).To(MatchError("signature does not match any trusted public key"))
+	})
+
+	It("rejects a brokerpak whose files have been changed after signing", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+		replaceFile(pakPath, "manifest.yml", "name: tampered")
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{publicKey})).To(MatchError(`file "manifest.yml" does not match its signed hash`))
+	})
+
+	It("rejects a brokerpak with files added after signing", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+		replaceFile(pakPath, "bin/linux/amd64/extra", "extra")
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{publicKey})).To(MatchError(`file "bin/linux/amd64/extra" is not covered by the signature`))
+	})
+
+	It("rejects a brokerpak with a duplicate entry prepended after signing", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+		prependFile(pakPath, "manifest.yml", "name: tampered")
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{publicKey})).To(MatchError(`duplicate entry "manifest.yml"`))
+	})
+
+	It("rejects a brokerpak with a duplicate signature", func() {
+		Expect(signature.Sign(pakPath, privateKey)).To(Succeed())
+		prependFile(pakPath, signature.FileName, "{}")
+
+		Expect(signature.Verify(contents(pakPath), []ed25519.PublicKey{publicKey})).To(MatchError(`duplicate entry "signature.json"`))
+	})
+
+	It("refuses to sign a brokerpak with duplicate entries", func() {
+		prependFile(pakPath, "manifest.yml", "name: duplicate")
+
+		Expect(signature.Sign(pakPath, privateKey)).To(MatchError(ContainSubstring(`duplicate entry "manifest.yml"`)))
+	})
+
+	Describe("keys", func() {
+		It("parses PEM encoded keys", func() {
+			privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsedPrivate, err := signature.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsedPrivate).To(Equal(privateKey))
+
+			publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+			parsedPublic, err := signature.ParsePublicKeys(append(publicPEM, publicPEM...))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsedPublic).To(Equal([]ed25519.PublicKey{publicKey, publicKey}))
+		})
+
+		It("fails when there is no PEM data", func() {
+			_, err := signature.ParsePrivateKey([]byte("not-a-key"))
+			Expect(err).To(MatchError("no PEM data found in private key"))
+
+			_, err = signature.ParsePublicKeys([]byte("not-a-key"))
+			Expect(err).To(MatchError("no PEM data found in public keys"))
+		})
+	})
+})
+
+func fakeBrokerpak(files map[string]string) string {
+	dir := GinkgoT().TempDir()
+	for name, content := range files {
+		Expect(os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)).To(Succeed())
+	}
+
+	pakPath := filepath.Join(GinkgoT().TempDir(), "fake.brokerpak")
+	Expect(zippy.Archive(dir, pakPath, false)).To(Succeed())
+	return pakPath
+}
+
+func contents(pakPath string) []*zip.File {
+	zr, err := zippy.Open(pakPath)
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(zr.Close)
+	return zr.List()
+}
+
+// replaceFile rewrites the brokerpak with the named file replaced or added
+func replaceFile(pakPath, name, content string) {
+	zr, err := zip.OpenReader(pakPath)
+	Expect(err).NotTo(HaveOccurred())
+	defer zr.Close()
+
+	output := filepath.Join(GinkgoT().TempDir(), "modified.brokerpak")
+	fd, err := os.Create(output)
+	Expect(err).NotTo(HaveOccurred())
+	zw := zip.NewWriter(fd)
+	for _, f := range zr.File {
+		if f.Name != name {
+			Expect(zw.Copy(f)).To(Succeed())
+		}
+	}
+	w, err := zw.Create(name)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = w.Write([]byte(content))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(zw.Close()).To(Succeed())
+	Expect(fd.Close()).To(Succeed())
+
+	Expect(os.Rename(output, pakPath)).To(Succeed())
+}
+
+// prependFile rewrites the brokerpak with the named file added before all the existing entries,
+// even if an entry with the same name exists
+func prependFile(pakPath, name, content string) {
+	zr, err := zip.OpenReader(pakPath)
+	Expect(err).NotTo(HaveOccurred())
+	defer zr.Close()
+
+	output := filepath.Join(GinkgoT().TempDir(), "modified.brokerpak")
+	fd, err := os.Create(output)
+	Expect(err).NotTo(HaveOccurred())
+	zw := zip.NewWriter(fd)
+	w, err := zw.Create(name)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = w.Write([]byte(content))
+	Expect(err).NotTo(HaveOccurred())
+	for _, f := range zr.File {
+		Expect(zw.Copy(f)).To(Succeed())
+	}
+	Expect(zw.Close()).To(Succeed())
+	Expect(fd.Close()).To(Succeed())
+
+	Expect(os.Rename(output, pakPath)).To(Succeed())
+}
@@ -76,7 +76,12 @@ func (r *Repo) Save(ctx context.Context, path string, cred any, actor string) (a
 		return nil, fmt.Errorf("failed to set permission on credential %q: %w", path, err)
 	}
 
-	return map[string]any{"credhub-ref": path}, nil
+	return r.Reference(path, cred), nil
+}
+
+// Reference returns the value that Save returns for a credential, without contacting CredHub
+func (r *Repo) Reference(path string, _ any) any {
+	return map[string]any{"credhub-ref": path}
 }
 
 // Delete will remove a credential and all its permissions from CredHub
@@ -125,6 +130,14 @@ func (r *Repo) Delete(ctx context.Context, path string) error {
 	return nil
 }
 
+// Ping checks that a token can be obtained from UAA and that CredHub reports that it is healthy
+func (r *Repo) Ping(ctx context.Context) error {
+	if err := r.http(ctx, http.MethodGet, "/health", nil, nil, http.StatusOK); err != nil {
+		return fmt.Errorf("credhub is not healthy: %w", err)
+	}
+	return nil
+}
+
 func (r *Repo) http(ctx context.Context, method, path string, requestBody, responseBody any, okCodes ...int) error {
 	tok, cachedToken, err := r.loadToken(ctx)
 	if err != nil {
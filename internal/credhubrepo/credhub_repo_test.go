@@ -136,6 +136,39 @@ var _ = Describe("CredHub Repository", func() {
 		})
 	})
 
+	Describe("Ping()", func() {
+		BeforeEach(func() {
+			fakeUAAServer = ghttp.NewServer()
+			appendUAATokenHandler(fakeUAAServer)
+
+			fakeCredHubServer = ghttp.NewServer()
+		})
+
+		It("succeeds when CredHub is healthy", func() {
+			fakeCredHubServer.RouteToHandler(http.MethodGet, "/health", ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer "+fakeUAAAccessToken),
+				ghttp.RespondWith(http.StatusOK, `{"status":"UP"}`),
+			))
+
+			Expect(repo.Ping(context.TODO())).To(Succeed())
+			Expect(fakeUAAServer.ReceivedRequests()).To(HaveLen(1))
+			Expect(fakeCredHubServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("fails when CredHub is not healthy", func() {
+			fakeCredHubServer.RouteToHandler(http.MethodGet, "/health", ghttp.RespondWith(http.StatusServiceUnavailable, `{"status":"DOWN"}`))
+
+			Expect(repo.Ping(context.TODO())).To(MatchError(ContainSubstring("credhub is not healthy: unexpected status code 503")))
+		})
+
+		It("fails when a token cannot be obtained from UAA", func() {
+			fakeUAAServer.SetHandler(0, ghttp.RespondWith(http.StatusUnauthorized, `{}`))
+
+			Expect(repo.Ping(context.TODO())).To(MatchError(ContainSubstring("unexpected status code 401")))
+			Expect(fakeCredHubServer.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
 	Describe("Delete()", func() {
 		BeforeEach(func() {
 			fakeUAAServer = ghttp.NewServer()
@@ -7,32 +7,78 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/featureflags"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/toggles"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
 )
 
+// Config describes the broker for troubleshooting. It must not contain secrets, as the
+// endpoint is not authenticated. Fields that are not set are omitted from the response.
 type Config struct {
 	BrokerVersion string
 	Uptime        func() time.Duration
+
+	// Brokerpaks returns the loaded brokerpaks. It is called for each request as the brokerpaks can be reloaded.
+	Brokerpaks func() []broker.Brokerpak
+
+	// FeatureToggles are reported, along with all the feature flags, keyed by the environment variable that sets them
+	FeatureToggles *toggles.ToggleSet
+
+	// Storage is not set when only the docs are being served
+	Storage *Storage
 }
 
-func New(cfg Config) http.HandlerFunc {
-	type payload struct {
-		Version string `json:"version"`
-		Uptime  string `json:"uptime"`
-	}
+// Storage describes where the broker keeps its state
+type Storage struct {
+	DatabaseType    string
+	MigrationLevel  int
+	EncryptionLabel string
+	CredHubEnabled  bool
+}
+
+type brokerpak struct {
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	URI          string   `json:"uri"`
+	TofuVersions []string `json:"tofu_versions"`
+	Providers    []string `json:"providers"`
+}
 
+type database struct {
+	Type           string `json:"type"`
+	MigrationLevel int    `json:"migration_level"`
+}
+
+type encryption struct {
+	Label string `json:"label"`
+}
+
+type credHub struct {
+	Enabled bool `json:"enabled"`
+}
+
+type payload struct {
+	Version        string          `json:"version"`
+	Uptime         string          `json:"uptime"`
+	Brokerpaks     []brokerpak     `json:"brokerpaks,omitempty"`
+	FeatureToggles map[string]bool `json:"feature_toggles,omitempty"`
+	FeatureFlags   map[string]bool `json:"feature_flags,omitempty"`
+	Database       *database       `json:"database,omitempty"`
+	Encryption     *encryption     `json:"encryption,omitempty"`
+	CredHub        *credHub        `json:"credhub,omitempty"`
+}
+
+func New(cfg Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		data, err := json.Marshal(payload{
-			Version: cfg.BrokerVersion,
-			Uptime:  cfg.Uptime().String(),
-		})
+		data, err := json.Marshal(newPayload(cfg))
 		if err != nil {
 			http.Error(w, fmt.Sprintf("error marshalling info payload: %s", err), http.StatusInternalServerError)
 			return
 		}
 
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Header().Set("Content-Type", "text/html")
 		_, err = w.Write(data)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
@@ -41,10 +87,46 @@ func New(cfg Config) http.HandlerFunc {
 	}
 }
 
-func NewDefault() http.HandlerFunc {
+// NewDefault reports the version of this broker and the time since NewDefault was called, along with the
+// rest of the configuration
+func NewDefault(cfg Config) http.HandlerFunc {
 	startTime := time.Now()
-	return New(Config{
-		BrokerVersion: utils.Version,
-		Uptime:        func() time.Duration { return time.Since(startTime) },
-	})
+	cfg.BrokerVersion = utils.Version
+	cfg.Uptime = func() time.Duration { return time.Since(startTime) }
+	return New(cfg)
+}
+
+func newPayload(cfg Config) payload {
+	p := payload{
+		Version: cfg.BrokerVersion,
+		Uptime:  cfg.Uptime().String(),
+	}
+
+	if cfg.Brokerpaks != nil {
+		for _, b := range cfg.Brokerpaks() {
+			p.Brokerpaks = append(p.Brokerpaks, brokerpak{
+				Name:         b.Name,
+				Version:      b.Version,
+				URI:          b.URI,
+				TofuVersions: b.TofuVersions,
+				Providers:    b.Providers,
+			})
+		}
+	}
+
+	if cfg.FeatureToggles != nil {
+		p.FeatureToggles = make(map[string]bool)
+		for _, t := range cfg.FeatureToggles.Toggles() {
+			p.FeatureToggles[t.EnvironmentVariable()] = t.IsActive()
+		}
+		p.FeatureFlags = featureflags.All()
+	}
+
+	if s := cfg.Storage; s != nil {
+		p.Database = &database{Type: s.DatabaseType, MigrationLevel: s.MigrationLevel}
+		p.Encryption = &encryption{Label: s.EncryptionLabel}
+		p.CredHub = &credHub{Enabled: s.CredHubEnabled}
+	}
+
+	return p
 }
@@ -1,14 +1,18 @@
 package infohandler_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/infohandler"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/toggles"
 )
 
 var _ = Describe("Info Handler", func() {
@@ -33,6 +37,78 @@ var _ = Describe("Info Handler", func() {
 		resp, err := client.Get(server.URL)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(resp).To(HaveHTTPStatus(http.StatusOK))
+		Expect(resp).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
 		Expect(resp).To(HaveHTTPBody(MatchJSON(`{"version":"fake-version","uptime":"1h0m0s"}`)))
 	})
+
+	When("the broker is fully configured", func() {
+		var loaded []broker.Brokerpak
+
+		BeforeEach(func() {
+			loaded = []broker.Brokerpak{{
+				Name:         "fake-pak",
+				Version:      "1.2.3",
+				URI:          "https://REDACTED@example.com/fake.brokerpak",
+				TofuVersions: []string{"1.6.2"},
+				Providers:    []string{"registry.terraform.io/hashicorp/random@3.6.0"},
+			}}
+
+			featureToggles := toggles.NewToggleSet("infohandler.test.")
+			featureToggles.Toggle("enable-fake", false, "a fake toggle")
+			viper.Set("infohandler.test.enable-fake", true)
+			DeferCleanup(viper.Reset)
+
+			server.Close()
+			server = httptest.NewServer(infohandler.New(infohandler.Config{
+				BrokerVersion:  "fake-version",
+				Uptime:         func() time.Duration { return time.Hour },
+				Brokerpaks:     func() []broker.Brokerpak { return loaded },
+				FeatureToggles: featureToggles,
+				Storage: &infohandler.Storage{
+					DatabaseType:    "mysql",
+					MigrationLevel:  20,
+					EncryptionLabel: "fake-label",
+					CredHubEnabled:  true,
+				},
+			}))
+			client = server.Client()
+		})
+
+		It("describes the broker", func() {
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(HaveHTTPStatus(http.StatusOK))
+
+			var body map[string]any
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body).To(HaveKeyWithValue("brokerpaks", ConsistOf(map[string]any{
+				"name":          "fake-pak",
+				"version":       "1.2.3",
+				"uri":           "https://REDACTED@example.com/fake.brokerpak",
+				"tofu_versions": []any{"1.6.2"},
+				"providers":     []any{"registry.terraform.io/hashicorp/random@3.6.0"},
+			})))
+			Expect(body).To(HaveKeyWithValue("feature_toggles", map[string]any{"GSB_INFOHANDLER_TEST_ENABLE_FAKE": true}))
+			Expect(body).To(HaveKeyWithValue("feature_flags", HaveKeyWithValue("CSB_ENABLE_ASYNC_BINDINGS", false)))
+			Expect(body).To(HaveKeyWithValue("database", map[string]any{"type": "mysql", "migration_level": float64(20)}))
+			Expect(body).To(HaveKeyWithValue("encryption", map[string]any{"label": "fake-label"}))
+			Expect(body).To(HaveKeyWithValue("credhub", map[string]any{"enabled": true}))
+		})
+
+		It("reports the brokerpaks loaded at the time of the request", func() {
+			loaded = append(loaded, broker.Brokerpak{Name: "other-pak", Version: "0.1.0"})
+
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+
+			var body struct {
+				Brokerpaks []struct {
+					Name string `json:"name"`
+				} `json:"brokerpaks"`
+			}
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.Brokerpaks).To(HaveLen(2))
+			Expect(body.Brokerpaks[1].Name).To(Equal("other-pak"))
+		})
+	})
 })
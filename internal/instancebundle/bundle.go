@@ -0,0 +1,254 @@
+// Package instancebundle moves a service instance and its bindings between brokers that have
+// different databases and encryption keys, without recreating the resources
+package instancebundle
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+)
+
+// Bundle holds the broker records of a service instance and its bindings. It contains credentials,
+// so it is only ever written to disk encrypted.
+type Bundle struct {
+	Instance                storage.ServiceInstanceDetails `json:"instance"`
+	ProvisionRequestDetails storage.JSONObject             `json:"provision_request_details"`
+	Deployment              Deployment                     `json:"deployment"`
+	Bindings                []Binding                      `json:"bindings"`
+}
+
+// Binding holds the broker records of a binding
+type Binding struct {
+	GUID               string             `json:"guid"`
+	Credentials        storage.JSONObject `json:"credentials"`
+	BindResource       storage.JSONObject `json:"bind_resource"`
+	BindRequestDetails storage.JSONObject `json:"bind_request_details"`
+	Deployment         Deployment         `json:"deployment"`
+}
+
+// Deployment is a Terraform deployment without its ID, which depends on the GUIDs
+type Deployment struct {
+	Workspace            *workspace.TerraformWorkspace `json:"workspace"`
+	LastOperationType    string                        `json:"last_operation_type"`
+	LastOperationState   string                        `json:"last_operation_state"`
+	LastOperationMessage string                        `json:"last_operation_message"`
+}
+
+// GUIDs maps the GUIDs in the source broker to the GUIDs to import them with. GUIDs that are not
+// mapped are kept. The organization and space are those of the instance on the target platform.
+type GUIDs struct {
+	Instance     string
+	Organization string
+	Space        string
+	Bindings     map[string]string
+}
+
+func (g GUIDs) instance(guid string) string {
+	return valueOr(g.Instance, guid)
+}
+
+func (g GUIDs) organization(guid string) string {
+	return valueOr(g.Organization, guid)
+}
+
+func (g GUIDs) space(guid string) string {
+	return valueOr(g.Space, guid)
+}
+
+func (g GUIDs) binding(guid string) string {
+	if mapped, ok := g.Bindings[guid]; ok {
+		return mapped
+	}
+	return guid
+}
+
+// Export reads the records of the service instance and its bindings. It fails if an operation is
+// in progress for any of them.
+func Export(store *storage.Storage, instanceGUID string) (Bundle, error) {
+	instance, err := store.GetServiceInstanceDetails(instanceGUID)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	provisionRequestDetails, err := store.GetProvisionRequestDetails(instanceGUID)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	deployment, err := exportDeployment(store, instanceDeploymentID(instanceGUID))
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	bundle := Bundle{
+		Instance:                instance,
+		ProvisionRequestDetails: provisionRequestDetails,
+		Deployment:              deployment,
+	}
+
+	bindingGUIDs, err := store.GetServiceBindingIDsForServiceInstance(instanceGUID)
+	if err != nil {
+		return Bundle{}, err
+	}
+	slices.Sort(bindingGUIDs)
+
+	for _, bindingGUID := range bindingGUIDs {
+		credentials, err := store.GetServiceBindingCredentials(bindingGUID, instanceGUID)
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		bindRequestDetails, err := store.GetBindRequestDetails(bindingGUID, instanceGUID)
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		deployment, err := exportDeployment(store, bindingDeploymentID(instanceGUID, bindingGUID))
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		bundle.Bindings = append(bundle.Bindings, Binding{
+			GUID:               bindingGUID,
+			Credentials:        credentials.Credentials,
+			BindResource:       bindRequestDetails.BindResource,
+			BindRequestDetails: bindRequestDetails.Parameters,
+			Deployment:         deployment,
+		})
+	}
+
+	return bundle, nil
+}
+
+// Import writes the records in the bundle with the GUIDs remapped. The records are encrypted with the
+// encryption key of the store. It fails if the instance, a binding, or a Terraform deployment for them already exists.
+// The records are written in a single transaction, so nothing is written if any of them fails.
+func Import(store *storage.Storage, bundle Bundle, guids GUIDs) error {
+	for guid := range guids.Bindings {
+		if !slices.ContainsFunc(bundle.Bindings, func(b Binding) bool { return b.GUID == guid }) {
+			return fmt.Errorf("binding %q is not in the bundle", guid)
+		}
+	}
+
+	instanceGUID := guids.instance(bundle.Instance.GUID)
+
+	exists, err := store.ExistsServiceInstanceDetails(instanceGUID)
+	switch {
+	case err != nil:
+		return err
+	case exists:
+		return fmt.Errorf("service instance %q already exists", instanceGUID)
+	}
+
+	deploymentIDs := []string{instanceDeploymentID(instanceGUID)}
+	for _, b := range bundle.Bindings {
+		deploymentIDs = append(deploymentIDs, bindingDeploymentID(instanceGUID, guids.binding(b.GUID)))
+	}
+	for _, id := range deploymentIDs {
+		exists, err := store.ExistsTerraformDeployment(id)
+		switch {
+		case err != nil:
+			return err
+		case exists:
+			return fmt.Errorf("terraform deployment %q already exists", id)
+		}
+	}
+
+	for _, b := range bundle.Bindings {
+		bindingGUID := guids.binding(b.GUID)
+		exists, err := store.ExistsBinding(bindingGUID)
+		switch {
+		case err != nil:
+			return err
+		case exists:
+			return fmt.Errorf("service binding %q already exists", bindingGUID)
+		}
+	}
+
+	return store.Transaction(func(tx *storage.Storage) error {
+		return writeRecords(tx, bundle, guids, instanceGUID)
+	})
+}
+
+func writeRecords(store *storage.Storage, bundle Bundle, guids GUIDs, instanceGUID string) error {
+	instance := bundle.Instance
+	instance.GUID = instanceGUID
+	instance.OrganizationGUID = guids.organization(instance.OrganizationGUID)
+	instance.SpaceGUID = guids.space(instance.SpaceGUID)
+	// The source broker may have released the instance so that deleting it there does not destroy the resources
+	instance.Released = false
+	if err := store.StoreServiceInstanceDetails(instance); err != nil {
+		return err
+	}
+	if err := store.StoreProvisionRequestDetails(instanceGUID, bundle.ProvisionRequestDetails); err != nil {
+		return err
+	}
+	if err := store.StoreTerraformDeployment(bundle.Deployment.toStorage(instanceDeploymentID(instanceGUID))); err != nil {
+		return err
+	}
+
+	for _, b := range bundle.Bindings {
+		bindingGUID := guids.binding(b.GUID)
+
+		if err := store.CreateServiceBindingCredentials(storage.ServiceBindingCredentials{
+			ServiceGUID:         instance.ServiceGUID,
+			ServiceInstanceGUID: instanceGUID,
+			BindingGUID:         bindingGUID,
+			Credentials:         b.Credentials,
+		}); err != nil {
+			return err
+		}
+		if err := store.StoreBindRequestDetails(bindingGUID, instanceGUID, b.BindResource, b.BindRequestDetails); err != nil {
+			return err
+		}
+		if err := store.StoreTerraformDeployment(b.Deployment.toStorage(bindingDeploymentID(instanceGUID, bindingGUID))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exportDeployment(store *storage.Storage, deploymentID string) (Deployment, error) {
+	deployment, err := store.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return Deployment{}, err
+	}
+	if deployment.LastOperationState == tf.InProgress {
+		return Deployment{}, fmt.Errorf("an operation is in progress for terraform deployment %q", deploymentID)
+	}
+	return Deployment{
+		Workspace:            deployment.TFWorkspace(),
+		LastOperationType:    deployment.LastOperationType,
+		LastOperationState:   deployment.LastOperationState,
+		LastOperationMessage: deployment.LastOperationMessage,
+	}, nil
+}
+
+func (d Deployment) toStorage(id string) storage.TerraformDeployment {
+	return storage.TerraformDeployment{
+		ID:                   id,
+		Workspace:            d.Workspace,
+		LastOperationType:    d.LastOperationType,
+		LastOperationState:   d.LastOperationState,
+		LastOperationMessage: d.LastOperationMessage,
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+func instanceDeploymentID(instanceGUID string) string {
+	return fmt.Sprintf("tf:%s:", instanceGUID)
+}
+
+func bindingDeploymentID(instanceGUID, bindingGUID string) string {
+	return fmt.Sprintf("tf:%s:%s", instanceGUID, bindingGUID)
+}
@@ -0,0 +1,75 @@
+package instancebundle
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/encryption/gcmencryptor"
+)
+
+const formatVersion = 1
+
+// envelope is the file format of an encrypted bundle. The key is derived from a passphrase, because the
+// source and target brokers do not share a database encryption key.
+type envelope struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Data    []byte `json:"data"`
+}
+
+// Encrypt encrypts the bundle with a key derived from the passphrase
+func Encrypt(bundle Bundle, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("a passphrase is required to encrypt the bundle")
+	}
+
+	plaintext, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding bundle: %w", err)
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	data, err := encryptor(passphrase, salt).Encrypt(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting bundle: %w", err)
+	}
+
+	return json.Marshal(envelope{Version: formatVersion, Salt: salt, Data: data})
+}
+
+// Decrypt decrypts a bundle that was encrypted with the passphrase
+func Decrypt(contents []byte, passphrase string) (Bundle, error) {
+	var e envelope
+	if err := json.Unmarshal(contents, &e); err != nil {
+		return Bundle{}, fmt.Errorf("error parsing bundle: %w", err)
+	}
+	if e.Version != formatVersion {
+		return Bundle{}, fmt.Errorf("unsupported bundle version %d, expected %d", e.Version, formatVersion)
+	}
+
+	plaintext, err := encryptor(passphrase, e.Salt).Decrypt(e.Data)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("error decrypting bundle, check the passphrase: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(plaintext, &bundle); err != nil {
+		return Bundle{}, fmt.Errorf("error decoding bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+func encryptor(passphrase string, salt []byte) gcmencryptor.GCMEncryptor {
+	var key [32]byte
+	copy(key[:], pbkdf2.Key([]byte(passphrase), salt, 100000, 32, sha256.New))
+	return gcmencryptor.New(key)
+}
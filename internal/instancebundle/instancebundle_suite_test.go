@@ -0,0 +1,13 @@
+package instancebundle_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestInstanceBundle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Instance Bundle Suite")
+}
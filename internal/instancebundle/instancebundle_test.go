@@ -0,0 +1,252 @@
+package instancebundle_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/encryption/gcmencryptor"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/encryption/noopencryptor"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/instancebundle"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("Instance bundles", func() {
+	const (
+		instanceGUID = "instance-guid"
+		bindingGUID  = "binding-guid"
+	)
+
+	var (
+		source   *storage.Storage
+		target   *storage.Storage
+		targetDB *gorm.DB
+	)
+
+	newDB := func() *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.Migrator().CreateTable(
+			&models.ServiceBindingCredentials{},
+			&models.ProvisionRequestDetails{},
+			&models.BindRequestDetails{},
+			&models.ServiceInstanceDetails{},
+			&models.TerraformDeployment{},
+		)).To(Succeed())
+		return db
+	}
+
+	newWorkspace := func(name string) *workspace.TerraformWorkspace {
+		ws, err := workspace.NewWorkspace(map[string]any{"name": name}, "", map[string]string{"main": `variable "name" { type = string }`}, nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		ws.State = []byte(`{"version":4,"resources":[]}`)
+		return ws
+	}
+
+	BeforeEach(func() {
+		source = storage.New(newDB(), noopencryptor.New())
+		targetDB = newDB()
+		target = storage.New(targetDB, gcmencryptor.New([32]byte{1, 2, 3}))
+
+		Expect(source.StoreServiceInstanceDetails(storage.ServiceInstanceDetails{
+			GUID:             instanceGUID,
+			Name:             "instance-name",
+			Outputs:          storage.JSONObject{"host": "example.com"},
+			ServiceGUID:      "service-guid",
+			PlanGUID:         "plan-guid",
+			SpaceGUID:        "space-guid",
+			OrganizationGUID: "org-guid",
+			Released:         true,
+		})).To(Succeed())
+		Expect(source.StoreProvisionRequestDetails(instanceGUID, storage.JSONObject{"size": "large"})).To(Succeed())
+		Expect(source.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID:                 "tf:instance-guid:",
+			Workspace:          newWorkspace("instance"),
+			LastOperationType:  "provision",
+			LastOperationState: "succeeded",
+		})).To(Succeed())
+
+		Expect(source.CreateServiceBindingCredentials(storage.ServiceBindingCredentials{
+			ServiceGUID:         "service-guid",
+			ServiceInstanceGUID: instanceGUID,
+			BindingGUID:         bindingGUID,
+			Credentials:         storage.JSONObject{"password": "secret"},
+		})).To(Succeed())
+		Expect(source.StoreBindRequestDetails(bindingGUID, instanceGUID, storage.JSONObject{"app_guid": "app-guid"}, storage.JSONObject{"read_only": true})).To(Succeed())
+		Expect(source.StoreTerraformDeployment(storage.TerraformDeployment{
+			ID:                 "tf:instance-guid:binding-guid",
+			Workspace:          newWorkspace("binding"),
+			LastOperationType:  "bind",
+			LastOperationState: "succeeded",
+		})).To(Succeed())
+	})
+
+	It("moves an instance and its bindings to another broker with new GUIDs", func() {
+		bundle, err := instancebundle.Export(source, instanceGUID)
+		Expect(err).NotTo(HaveOccurred())
+
+		contents, err := instancebundle.Encrypt(bundle, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("secret"))
+
+		decrypted, err := instancebundle.Decrypt(contents, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(instancebundle.Import(target, decrypted, instancebundle.GUIDs{
+			Instance:     "new-instance-guid",
+			Organization: "new-org-guid",
+			Space:        "new-space-guid",
+			Bindings:     map[string]string{bindingGUID: "new-binding-guid"},
+		})).To(Succeed())
+
+		instance, err := target.GetServiceInstanceDetails("new-instance-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).To(Equal(storage.ServiceInstanceDetails{
+			GUID:             "new-instance-guid",
+			Name:             "instance-name",
+			Outputs:          storage.JSONObject{"host": "example.com"},
+			ServiceGUID:      "service-guid",
+			PlanGUID:         "plan-guid",
+			SpaceGUID:        "new-space-guid",
+			OrganizationGUID: "new-org-guid",
+		}))
+
+		Expect(target.GetProvisionRequestDetails("new-instance-guid")).To(Equal(storage.JSONObject{"size": "large"}))
+
+		deployment, err := target.GetTerraformDeployment("tf:new-instance-guid:")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.LastOperationType).To(Equal("provision"))
+		Expect(deployment.LastOperationState).To(Equal("succeeded"))
+		Expect(deployment.TFWorkspace().Instances[0].Configuration).To(Equal(map[string]any{"name": "instance"}))
+		Expect(deployment.TFWorkspace().State).To(MatchJSON(`{"version":4,"resources":[]}`))
+
+		credentials, err := target.GetServiceBindingCredentials("new-binding-guid", "new-instance-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials.ServiceGUID).To(Equal("service-guid"))
+		Expect(credentials.Credentials).To(Equal(storage.JSONObject{"password": "secret"}))
+
+		bindRequestDetails, err := target.GetBindRequestDetails("new-binding-guid", "new-instance-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bindRequestDetails.BindResource).To(Equal(storage.JSONObject{"app_guid": "app-guid"}))
+		Expect(bindRequestDetails.Parameters).To(Equal(storage.JSONObject{"read_only": true}))
+
+		bindingDeployment, err := target.GetTerraformDeployment("tf:new-instance-guid:new-binding-guid")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bindingDeployment.LastOperationType).To(Equal("bind"))
+		Expect(bindingDeployment.TFWorkspace().Instances[0].Configuration).To(Equal(map[string]any{"name": "binding"}))
+
+		By("encrypting the records with the key of the target broker")
+		var m models.ServiceBindingCredentials
+		Expect(targetDB.First(&m).Error).To(Succeed())
+		Expect(json.Valid(m.OtherDetails)).To(BeFalse())
+	})
+
+	It("keeps the GUIDs that are not remapped", func() {
+		bundle, err := instancebundle.Export(source, instanceGUID)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(instancebundle.Import(target, bundle, instancebundle.GUIDs{})).To(Succeed())
+
+		instance, err := target.GetServiceInstanceDetails(instanceGUID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance.OrganizationGUID).To(Equal("org-guid"))
+		Expect(instance.SpaceGUID).To(Equal("space-guid"))
+		Expect(target.ExistsServiceBindingCredentials(bindingGUID, instanceGUID)).To(BeTrue())
+		Expect(target.ExistsTerraformDeployment("tf:instance-guid:binding-guid")).To(BeTrue())
+	})
+
+	When("a remapped binding is not in the bundle", func() {
+		It("fails to import", func() {
+			bundle, err := instancebundle.Export(source, instanceGUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = instancebundle.Import(target, bundle, instancebundle.GUIDs{Bindings: map[string]string{"other-guid": "new-guid"}})
+			Expect(err).To(MatchError(`binding "other-guid" is not in the bundle`))
+			Expect(target.ExistsServiceInstanceDetails(instanceGUID)).To(BeFalse())
+		})
+	})
+
+	When("an operation is in progress", func() {
+		It("fails to export", func() {
+			Expect(source.StoreTerraformDeployment(storage.TerraformDeployment{
+				ID:                 "tf:instance-guid:binding-guid",
+				Workspace:          newWorkspace("binding"),
+				LastOperationType:  "unbind",
+				LastOperationState: "in progress",
+			})).To(Succeed())
+
+			_, err := instancebundle.Export(source, instanceGUID)
+			Expect(err).To(MatchError(`an operation is in progress for terraform deployment "tf:instance-guid:binding-guid"`))
+		})
+	})
+
+	When("the instance already exists in the target broker", func() {
+		It("fails to import without writing anything", func() {
+			bundle, err := instancebundle.Export(source, instanceGUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instancebundle.Import(source, bundle, instancebundle.GUIDs{})).To(MatchError(`service instance "instance-guid" already exists`))
+		})
+	})
+
+	When("a deployment already exists in the target broker", func() {
+		It("fails to import without writing anything", func() {
+			Expect(target.StoreTerraformDeployment(storage.TerraformDeployment{ID: "tf:instance-guid:binding-guid", Workspace: newWorkspace("other")})).To(Succeed())
+
+			bundle, err := instancebundle.Export(source, instanceGUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instancebundle.Import(target, bundle, instancebundle.GUIDs{})).To(MatchError(`terraform deployment "tf:instance-guid:binding-guid" already exists`))
+			Expect(target.ExistsServiceInstanceDetails(instanceGUID)).To(BeFalse())
+		})
+	})
+
+	When("a binding already exists in the target broker", func() {
+		It("fails to import without writing anything", func() {
+			Expect(target.StoreBindRequestDetails(bindingGUID, "other-instance-guid", nil, nil)).To(Succeed())
+
+			bundle, err := instancebundle.Export(source, instanceGUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instancebundle.Import(target, bundle, instancebundle.GUIDs{})).To(MatchError(`service binding "binding-guid" already exists`))
+			Expect(target.ExistsServiceInstanceDetails(instanceGUID)).To(BeFalse())
+		})
+	})
+
+	When("a write fails partway through the import", func() {
+		It("writes nothing", func() {
+			Expect(targetDB.Migrator().DropTable(&models.ProvisionRequestDetails{})).To(Succeed())
+
+			bundle, err := instancebundle.Export(source, instanceGUID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instancebundle.Import(target, bundle, instancebundle.GUIDs{})).To(MatchError(ContainSubstring("provision_request_details")))
+			Expect(target.ExistsServiceInstanceDetails(instanceGUID)).To(BeFalse())
+		})
+	})
+
+	Describe("encryption", func() {
+		It("fails to decrypt with the wrong passphrase", func() {
+			contents, err := instancebundle.Encrypt(instancebundle.Bundle{}, "passphrase")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = instancebundle.Decrypt(contents, "wrong")
+			Expect(err).To(MatchError(ContainSubstring("error decrypting bundle, check the passphrase")))
+		})
+
+		It("requires a passphrase", func() {
+			_, err := instancebundle.Encrypt(instancebundle.Bundle{}, "")
+			Expect(err).To(MatchError("a passphrase is required to encrypt the bundle"))
+		})
+
+		It("rejects other versions of the format", func() {
+			_, err := instancebundle.Decrypt([]byte(`{"version":2}`), "passphrase")
+			Expect(err).To(MatchError("unsupported bundle version 2, expected 1"))
+		})
+	})
+})
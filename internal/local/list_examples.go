@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/client"
 )
@@ -22,9 +23,9 @@ func ListExamples(cachePath string) {
 		log.Fatal(err)
 	}
 
-	tp := newTablePrinter("Example Name", "Service Offering Name")
+	tp := newTablePrinter("Example Name", "Service Offering Name", "Assertions")
 	for _, e := range examples {
-		tp.row(e.Name, e.ServiceName)
+		tp.row(e.Name, e.ServiceName, strconv.Itoa(e.AssertionCount()))
 	}
 	tp.print()
 }
@@ -22,7 +22,16 @@ func pack(cachePath string) (string, func()) {
 		fmt.Printf("created: %v\n", pakPath)
 	}
 
-	pakPath, err = filepath.Abs(pakPath)
+	pakDir, cleanup := linkBrokerpak(pakPath)
+	return pakDir, func() {
+		cleanup()
+		os.RemoveAll(pakPath)
+	}
+}
+
+// linkBrokerpak creates a directory containing only the brokerpak, so that the broker serves just that brokerpak
+func linkBrokerpak(pakPath string) (string, func()) {
+	pakPath, err := filepath.Abs(pakPath)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -38,6 +47,5 @@ func pack(cachePath string) (string, func()) {
 
 	return pakDir, func() {
 		os.RemoveAll(pakDir)
-		os.RemoveAll(pakPath)
 	}
 }
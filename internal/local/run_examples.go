@@ -6,10 +6,25 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/client"
 )
 
-func RunExamples(serviceOfferingName, exampleName, cachePath string) {
+// RunExamples runs the examples of the brokerpak in the current directory. Examples that
+// upgrade start with previousBrokerpak, which is served by a second broker sharing the
+// database. If previousBrokerpak is blank, these examples are skipped.
+func RunExamples(serviceOfferingName, exampleName, cachePath, previousBrokerpak string, reports client.ReportPaths) {
 	pakDir, cleanup := pack(cachePath)
 	defer cleanup()
 
+	// Both brokers are started before any example runs, as a broker marks operations that
+	// are in progress as failed when it starts
+	var previous *client.Client
+	if previousBrokerpak != "" {
+		previousDir, cleanupPrevious := linkBrokerpak(previousBrokerpak)
+		defer cleanupPrevious()
+
+		previousBroker := startBroker(previousDir)
+		defer previousBroker.Stop()
+		previous = previousBroker.Client
+	}
+
 	broker := startBroker(pakDir)
 	defer broker.Stop()
 
@@ -19,5 +34,5 @@ func RunExamples(serviceOfferingName, exampleName, cachePath string) {
 	}
 
 	const jobCount = 1_000_000
-	client.RunExamplesForService(examples, broker.Client, serviceOfferingName, exampleName, jobCount)
+	client.RunExamplesForService(examples, broker.Client, previous, serviceOfferingName, exampleName, jobCount, reports)
 }
@@ -0,0 +1,93 @@
+// Package offboard writes the Terraform workspaces of a service instance as a standalone Terraform
+// project, so that the resources of the instance can be managed outside the broker
+package offboard
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/zippy"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+)
+
+const (
+	// BindingsDirectory is the directory in the project that the binding workspaces are written to
+	BindingsDirectory = "bindings"
+	// ReadmeFileName is the file in the root of the project that describes how to use it
+	ReadmeFileName = "README.md"
+)
+
+// Project is the Terraform workspaces of a service instance and its bindings
+type Project struct {
+	InstanceGUID string
+	ServiceGUID  string
+	PlanGUID     string
+	Instance     *workspace.TerraformWorkspace
+	// Bindings are the workspaces of the bindings, keyed by binding GUID
+	Bindings map[string]*workspace.TerraformWorkspace
+}
+
+// Write writes the project to a zip archive at dest. The workspace of the instance is written to the
+// root of the archive, and the workspace of each binding to a directory under BindingsDirectory.
+// Each directory holds the modules, variables and state of the workspace, and can be initialized
+// and applied with OpenTofu without changes.
+func Write(project Project, dest string) error {
+	dir, err := os.MkdirTemp("", "csb-export")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := project.Instance.Export(dir); err != nil {
+		return fmt.Errorf("error writing workspace of instance %q: %w", project.InstanceGUID, err)
+	}
+
+	for _, bindingGUID := range slices.Sorted(maps.Keys(project.Bindings)) {
+		bindingDir := filepath.Join(dir, BindingsDirectory, bindingGUID)
+		if err := os.MkdirAll(bindingDir, 0o755); err != nil {
+			return err
+		}
+		if err := project.Bindings[bindingGUID].Export(bindingDir); err != nil {
+			return fmt.Errorf("error writing workspace of binding %q: %w", bindingGUID, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ReadmeFileName), []byte(readme(project)), 0o644); err != nil {
+		return err
+	}
+
+	// The archive holds the variables and state of the workspaces, which may contain credentials
+	fd, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := zippy.WriteArchive(dir, fd, true); err != nil {
+		_ = fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+func readme(project Project) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Service instance %s\n\n", project.InstanceGUID)
+	fmt.Fprintf(&b, "Exported from the Cloud Service Broker. Service offering %q, plan %q.\n\n", project.ServiceGUID, project.PlanGUID)
+	b.WriteString("This directory holds the modules, variables and state of the service instance. ")
+	b.WriteString("The variables and state may contain credentials, so store this project securely.\n\n")
+	b.WriteString("To take over management of the resources:\n\n")
+	b.WriteString("    tofu init\n    tofu plan\n\n")
+	b.WriteString("The plan should show no changes. Consider moving the state to a remote backend before making changes.\n")
+
+	if len(project.Bindings) > 0 {
+		fmt.Fprintf(&b, "\n## Bindings\n\nThe workspace of each binding is in its own directory, and can be managed in the same way:\n\n")
+		for _, bindingGUID := range slices.Sorted(maps.Keys(project.Bindings)) {
+			fmt.Fprintf(&b, "- %s/%s\n", BindingsDirectory, bindingGUID)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,13 @@
+package offboard_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOffboard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Offboard Suite")
+}
@@ -0,0 +1,85 @@
+package offboard_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/offboard"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/zippy"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+)
+
+var _ = Describe("Write", func() {
+	newWorkspace := func(vars map[string]any, state string) *workspace.TerraformWorkspace {
+		ws, err := workspace.NewWorkspace(vars, "", map[string]string{"main": `variable "name" { type = string }`}, nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		ws.State = []byte(state)
+		return ws
+	}
+
+	It("writes the instance and binding workspaces to an archive", func() {
+		dest := filepath.Join(GinkgoT().TempDir(), "export.zip")
+
+		err := offboard.Write(offboard.Project{
+			InstanceGUID: "instance-guid",
+			ServiceGUID:  "fake-service",
+			PlanGUID:     "fake-plan",
+			Instance:     newWorkspace(map[string]any{"name": "instance"}, `{"version":4,"serial":1}`),
+			Bindings: map[string]*workspace.TerraformWorkspace{
+				"binding-guid": newWorkspace(map[string]any{"name": "binding"}, `{"version":4,"serial":2}`),
+			},
+		}, dest)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("restricting access to the archive, as the state may contain credentials")
+		info, err := os.Stat(dest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		archive, err := zippy.Open(dest)
+		Expect(err).NotTo(HaveOccurred())
+		defer archive.Close()
+
+		target := GinkgoT().TempDir()
+		Expect(archive.ExtractDirectory("", target)).To(Succeed())
+
+		Expect(filepath.Join(target, "main.tf")).To(BeAnExistingFile())
+		Expect(os.ReadFile(filepath.Join(target, "terraform.tfvars.json"))).To(MatchJSON(`{"name":"instance"}`))
+		Expect(os.ReadFile(filepath.Join(target, "terraform.tfstate"))).To(MatchJSON(`{"version":4,"serial":1}`))
+
+		bindingDir := filepath.Join(target, "bindings", "binding-guid")
+		Expect(filepath.Join(bindingDir, "main.tf")).To(BeAnExistingFile())
+		Expect(os.ReadFile(filepath.Join(bindingDir, "terraform.tfvars.json"))).To(MatchJSON(`{"name":"binding"}`))
+		Expect(os.ReadFile(filepath.Join(bindingDir, "terraform.tfstate"))).To(MatchJSON(`{"version":4,"serial":2}`))
+
+		readme, err := os.ReadFile(filepath.Join(target, offboard.ReadmeFileName))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(readme)).To(And(
+			ContainSubstring("instance-guid"),
+			ContainSubstring(`Service offering "fake-service", plan "fake-plan"`),
+			ContainSubstring("- bindings/binding-guid"),
+		))
+	})
+
+	It("does not mention bindings when there are none", func() {
+		dest := filepath.Join(GinkgoT().TempDir(), "export.zip")
+
+		err := offboard.Write(offboard.Project{
+			InstanceGUID: "instance-guid",
+			Instance:     newWorkspace(map[string]any{"name": "instance"}, `{"version":4}`),
+		}, dest)
+		Expect(err).NotTo(HaveOccurred())
+
+		archive, err := zippy.Open(dest)
+		Expect(err).NotTo(HaveOccurred())
+		defer archive.Close()
+
+		Expect(archive.Find("bindings/")).To(BeNil())
+		target := GinkgoT().TempDir()
+		Expect(archive.ExtractFile(offboard.ReadmeFileName, target)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(target, offboard.ReadmeFileName))).NotTo(ContainSubstring("Bindings"))
+	})
+})
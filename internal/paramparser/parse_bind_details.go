@@ -57,12 +57,8 @@ func ParseBindDetails(input domain.BindDetails) (BindDetails, error) {
 		result.CredentialClientID = val.(string)
 	}
 
-	switch {
-	case result.AppGUID != "":
-		result.CredHubActor = fmt.Sprintf("mtls-app:%s", result.AppGUID)
-	case result.CredentialClientID != "":
-		result.CredHubActor = fmt.Sprintf("uaa-client:%s", result.CredentialClientID)
-	default:
+	result.CredHubActor = CredHubActor(result.RequestBindResource)
+	if result.CredHubActor == "" {
 		return BindDetails{}, ErrNoAppGUIDOrCredentialClient
 	}
 
@@ -81,6 +77,18 @@ func ParseBindDetails(input domain.BindDetails) (BindDetails, error) {
 	return result, nil
 }
 
+// CredHubActor returns the CredHub actor that is granted access to the credentials of a binding, from the
+// app GUID or the credential client ID in its bind resource. It is empty when the bind resource has neither.
+func CredHubActor(bindResource map[string]any) string {
+	if appGUID, ok := bindResource["app_guid"].(string); ok && appGUID != "" {
+		return fmt.Sprintf("mtls-app:%s", appGUID)
+	}
+	if clientID, ok := bindResource["credential_client_id"].(string); ok && clientID != "" {
+		return fmt.Sprintf("uaa-client:%s", clientID)
+	}
+	return ""
+}
+
 func parseBindResource(input *domain.BindResource) map[string]any {
 
 	if input == nil {
@@ -257,6 +257,13 @@ var _ = Describe("ParseBindDetails", func() {
 				Expect(bindDetails).To(BeZero())
 			})
 		})
+
+		It("can be computed from a stored bind resource", func() {
+			Expect(paramparser.CredHubActor(storage.JSONObject{"app_guid": "fake-app-guid", "credential_client_id": "fake-client"})).To(Equal("mtls-app:fake-app-guid"))
+			Expect(paramparser.CredHubActor(storage.JSONObject{"app_guid": "", "credential_client_id": "fake-client"})).To(Equal("uaa-client:fake-client"))
+			Expect(paramparser.CredHubActor(storage.JSONObject{"app_guid": ""})).To(BeEmpty())
+			Expect(paramparser.CredHubActor(nil)).To(BeEmpty())
+		})
 	})
 })
 
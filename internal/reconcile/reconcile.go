@@ -0,0 +1,95 @@
+// Package reconcile compares the service instances known to the broker with those known to the platform,
+// so that records left behind on either side can be identified
+package reconcile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Report lists the service instance GUIDs that only one side knows about
+type Report struct {
+	// BrokerOrphans are known to the broker but not to the platform, for example after a `cf purge-service-instance`
+	BrokerOrphans []string
+	// PlatformOrphans are known to the platform but not to the broker, for example after a `csb purge`
+	PlatformOrphans []string
+}
+
+// Compare returns a Report of the GUIDs that are only in one of the lists. The results are sorted.
+func Compare(brokerGUIDs, platformGUIDs []string) Report {
+	brokerSet := toSet(brokerGUIDs)
+	platformSet := toSet(platformGUIDs)
+
+	return Report{
+		BrokerOrphans:   difference(brokerSet, platformSet),
+		PlatformOrphans: difference(platformSet, brokerSet),
+	}
+}
+
+// CheckOverlap returns an error when the platform GUIDs are empty, or when none of them are known to the broker.
+// This usually means that the wrong GUIDs were listed, and removing the broker orphans would remove every
+// service instance of the broker.
+func CheckOverlap(brokerGUIDs, platformGUIDs []string) error {
+	if len(platformGUIDs) == 0 {
+		return fmt.Errorf("no platform service instance GUIDs were given")
+	}
+	if len(brokerGUIDs) == 0 {
+		return nil
+	}
+
+	brokerSet := toSet(brokerGUIDs)
+	for key := range toSet(platformGUIDs) {
+		if _, ok := brokerSet[key]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("none of the platform service instance GUIDs are known to the broker")
+}
+
+// ReadGUIDs reads whitespace-separated GUIDs. Lines starting with "#" are treated as comments.
+// Any other value that is not a GUID is an error.
+func ReadGUIDs(r io.Reader) ([]string, error) {
+	var result []string
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if err := uuid.Validate(field); err != nil {
+				return nil, fmt.Errorf("invalid platform service instance GUID %q on line %d", field, lineNumber)
+			}
+			result = append(result, field)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading platform service instance GUIDs: %w", err)
+	}
+	return result, nil
+}
+
+// toSet maps the lower case form of each GUID to the GUID as given, so that the original can be reported
+func toSet(guids []string) map[string]string {
+	result := make(map[string]string, len(guids))
+	for _, guid := range guids {
+		result[strings.ToLower(guid)] = guid
+	}
+	return result
+}
+
+func difference(a, b map[string]string) []string {
+	var result []string
+	for key, guid := range a {
+		if _, ok := b[key]; !ok {
+			result = append(result, guid)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
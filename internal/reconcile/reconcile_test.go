@@ -0,0 +1,92 @@
+package reconcile_test
+
+import (
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/reconcile"
+)
+
+var _ = Describe("Compare", func() {
+	It("reports the GUIDs that are only known to one side", func() {
+		report := reconcile.Compare(
+			[]string{"c-broker-only", "shared", "a-broker-only"},
+			[]string{"shared", "platform-only"},
+		)
+
+		Expect(report).To(Equal(reconcile.Report{
+			BrokerOrphans:   []string{"a-broker-only", "c-broker-only"},
+			PlatformOrphans: []string{"platform-only"},
+		}))
+	})
+
+	It("ignores the case of GUIDs", func() {
+		report := reconcile.Compare([]string{"ABC-123"}, []string{"abc-123"})
+
+		Expect(report.BrokerOrphans).To(BeEmpty())
+		Expect(report.PlatformOrphans).To(BeEmpty())
+	})
+
+	It("reports GUIDs as they were given", func() {
+		report := reconcile.Compare([]string{"ABC-123"}, []string{"Def-456"})
+
+		Expect(report).To(Equal(reconcile.Report{
+			BrokerOrphans:   []string{"ABC-123"},
+			PlatformOrphans: []string{"Def-456"},
+		}))
+	})
+
+	It("reports nothing when both sides are empty", func() {
+		Expect(reconcile.Compare(nil, nil)).To(Equal(reconcile.Report{}))
+	})
+})
+
+var _ = Describe("CheckOverlap", func() {
+	It("accepts platform GUIDs that include a broker GUID", func() {
+		Expect(reconcile.CheckOverlap([]string{"ABC-123", "def-456"}, []string{"abc-123", "ghi-789"})).To(Succeed())
+	})
+
+	It("accepts any platform GUIDs when the broker has no service instances", func() {
+		Expect(reconcile.CheckOverlap(nil, []string{"abc-123"})).To(Succeed())
+	})
+
+	It("rejects empty platform GUIDs", func() {
+		Expect(reconcile.CheckOverlap([]string{"abc-123"}, nil)).To(MatchError("no platform service instance GUIDs were given"))
+	})
+
+	It("rejects platform GUIDs that match none of the broker GUIDs", func() {
+		Expect(reconcile.CheckOverlap([]string{"abc-123"}, []string{"def-456"})).
+			To(MatchError("none of the platform service instance GUIDs are known to the broker"))
+	})
+})
+
+var _ = Describe("ReadGUIDs", func() {
+	const (
+		first  = "0b4a1d5c-8ad5-4b1f-9f3c-6f0a3c0e2b11"
+		second = "7E9C2F40-3C1B-4D8B-A7A4-0D1E5B6C7F82"
+		third  = "c3a9e8f1-2b7d-4e6a-8c5f-9d0b1a2e3f44"
+	)
+
+	It("reads whitespace-separated GUIDs and skips comments", func() {
+		input := "# platform instances\n" + first + " " + second + "\n\n  " + third + "\n# fourth\n"
+
+		guids, err := reconcile.ReadGUIDs(strings.NewReader(input))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(guids).To(Equal([]string{first, second, third}))
+	})
+
+	It("rejects values that are not GUIDs", func() {
+		input := first + "\n" + second + " not-a-guid\n"
+
+		_, err := reconcile.ReadGUIDs(strings.NewReader(input))
+		Expect(err).To(MatchError(`invalid platform service instance GUID "not-a-guid" on line 2`))
+	})
+
+	It("returns read errors", func() {
+		_, err := reconcile.ReadGUIDs(iotest.ErrReader(iotest.ErrTimeout))
+		Expect(err).To(MatchError("error reading platform service instance GUIDs: timeout"))
+	})
+})
@@ -47,6 +47,25 @@ func (s *Storage) StoreBindRequestDetails(bindingID, instanceID string, bindReso
 	return nil
 }
 
+func (s *Storage) UpdateBindRequestDetails(bindingID, instanceID string, parameters JSONObject) error {
+	encodedParams, err := s.encodeJSON(parameters)
+	if err != nil {
+		return fmt.Errorf("error encoding bind request details parameters: %w", err)
+	}
+
+	var receiver models.BindRequestDetails
+	if err := s.db.Where("service_binding_id = ? AND service_instance_id = ?", bindingID, instanceID).First(&receiver).Error; err != nil {
+		return fmt.Errorf("error finding bind request details record: %w", err)
+	}
+
+	receiver.Parameters = encodedParams
+	if err := s.db.Save(&receiver).Error; err != nil {
+		return fmt.Errorf("error updating bind request details: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Storage) GetBindRequestDetails(bindingID string, instanceID string) (BindRequestDetails, error) {
 	exists, err := s.existsBindRequestDetails(bindingID, instanceID)
 	switch {
@@ -177,6 +177,38 @@ var _ = Describe("BindRequestDetails", func() {
 		)
 	})
 
+	Describe("UpdateBindRequestDetails", func() {
+		BeforeEach(func() {
+			addFakeBindRequestDetails()
+		})
+
+		It("updates the parameters of the existing record", func() {
+			err := store.UpdateBindRequestDetails("fake-binding-id", "fake-instance-id", storage.JSONObject{"foo": "qux"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var receiver models.BindRequestDetails
+			Expect(db.Where(`service_binding_id="fake-binding-id"`).First(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.Parameters).To(MatchJSON(`{"encrypted":{"foo":"qux"}}`))
+			Expect(receiver.BindResource).To(MatchJSON(`{"bar":"baz"}`))
+		})
+
+		When("encoding fails", func() {
+			It("returns an error", func() {
+				encryptor.EncryptReturns(nil, errors.New("bang"))
+
+				err := store.UpdateBindRequestDetails("fake-binding-id", "fake-instance-id", storage.JSONObject{"foo": "qux"})
+				Expect(err).To(MatchError("error encoding bind request details parameters: encryption error: bang"))
+			})
+		})
+
+		When("the record does not exist", func() {
+			It("returns an error", func() {
+				err := store.UpdateBindRequestDetails("not-there", "fake-instance-id", storage.JSONObject{"foo": "qux"})
+				Expect(err).To(MatchError(ContainSubstring("error finding bind request details record")))
+			})
+		})
+	})
+
 	Describe("DeleteBindRequestDetails", func() {
 		BeforeEach(func() {
 			addFakeBindRequestDetails()
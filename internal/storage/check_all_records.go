@@ -20,6 +20,7 @@ func (s *Storage) CheckAllRecords() error {
 		s.checkAllProvisionRequestDetails,
 		s.checkAllServiceInstanceDetails,
 		s.checkAllTerraformDeployments,
+		s.checkAllRetainedDeployments,
 	}
 	for _, e := range checkers {
 		if err := e(); err != nil {
@@ -123,3 +124,22 @@ func (s *Storage) checkAllTerraformDeployments() (errs *multierror.Error) {
 
 	return errs
 }
+
+func (s *Storage) checkAllRetainedDeployments() (errs *multierror.Error) {
+	var retainedDeploymentBatch []models.RetainedDeployment
+	result := s.db.FindInBatches(&retainedDeploymentBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+		for i := range retainedDeploymentBatch {
+			var tfWorkspace workspace.TerraformWorkspace
+			if err := s.decodeJSON(retainedDeploymentBatch[i].Workspace, &tfWorkspace); err != nil {
+				errs = multierror.Append(fmt.Errorf("decode error for retained deployment %q: %w", retainedDeploymentBatch[i].ID, err), errs)
+			}
+		}
+
+		return nil
+	})
+	if result.Error != nil {
+		errs = multierror.Append(fmt.Errorf("error re-encoding retained deployment: %w", result.Error), errs)
+	}
+
+	return errs
+}
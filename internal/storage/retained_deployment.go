@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+)
+
+// RetainedDeployment is the workspace of a Terraform deployment whose resources were not destroyed
+// when the service instance or binding was deleted
+type RetainedDeployment struct {
+	ID                  string
+	ServiceInstanceGUID string
+	Workspace           workspace.Workspace
+	RetainedAt          time.Time
+}
+
+func (deployment *RetainedDeployment) TFWorkspace() *workspace.TerraformWorkspace {
+	return deployment.Workspace.(*workspace.TerraformWorkspace)
+}
+
+// RetainTerraformDeployment copies the Terraform deployment to the retained deployments, replacing any
+// that was retained before with the same ID. The workspace is copied as it is stored, so it stays encrypted.
+func (s *Storage) RetainTerraformDeployment(serviceInstanceID, deploymentID string) error {
+	var deployment models.TerraformDeployment
+	if err := s.db.Where("id = ?", deploymentID).First(&deployment).Error; err != nil {
+		return fmt.Errorf("error finding terraform deployment %q: %w", deploymentID, err)
+	}
+
+	var m models.RetainedDeployment
+	if err := s.db.Where("id = ?", deploymentID).Limit(1).Find(&m).Error; err != nil {
+		return fmt.Errorf("error finding retained deployment: %w", err)
+	}
+
+	m.ServiceInstanceID = serviceInstanceID
+	m.Workspace = deployment.Workspace
+
+	switch m.ID {
+	case "":
+		m.ID = deploymentID
+		if err := s.db.Create(&m).Error; err != nil {
+			return fmt.Errorf("error creating retained deployment: %w", err)
+		}
+	default:
+		if err := s.db.Save(&m).Error; err != nil {
+			return fmt.Errorf("error saving retained deployment: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) GetRetainedDeployment(id string) (RetainedDeployment, error) {
+	var receiver models.RetainedDeployment
+	if err := s.db.Where("id = ?", id).Limit(1).Find(&receiver).Error; err != nil {
+		return RetainedDeployment{}, fmt.Errorf("error finding retained deployment: %w", err)
+	}
+	if receiver.ID == "" {
+		return RetainedDeployment{}, fmt.Errorf("could not find retained deployment: %s", id)
+	}
+
+	return s.decodeRetainedDeployment(receiver)
+}
+
+// GetRetainedDeployments returns the retained deployments, oldest first
+func (s *Storage) GetRetainedDeployments() ([]RetainedDeployment, error) {
+	var result []RetainedDeployment
+
+	var retainedDeploymentBatch []models.RetainedDeployment
+	status := s.db.Order("updated_at").FindInBatches(&retainedDeploymentBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+		for i := range retainedDeploymentBatch {
+			deployment, err := s.decodeRetainedDeployment(retainedDeploymentBatch[i])
+			if err != nil {
+				return err
+			}
+			result = append(result, deployment)
+		}
+
+		return nil
+	})
+	if status.Error != nil {
+		return nil, fmt.Errorf("error reading retained deployment batch: %w", status.Error)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) DeleteRetainedDeployment(id string) error {
+	if err := s.db.Where("id = ?", id).Delete(&models.RetainedDeployment{}).Error; err != nil {
+		return fmt.Errorf("error deleting retained deployment: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) decodeRetainedDeployment(m models.RetainedDeployment) (RetainedDeployment, error) {
+	var tfWorkspace workspace.TerraformWorkspace
+	if err := s.decodeJSON(m.Workspace, &tfWorkspace); err != nil {
+		return RetainedDeployment{}, fmt.Errorf("error decoding retained workspace %q: %w", m.ID, err)
+	}
+
+	return RetainedDeployment{
+		ID:                  m.ID,
+		ServiceInstanceGUID: m.ServiceInstanceID,
+		Workspace:           &tfWorkspace,
+		RetainedAt:          m.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package storage_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage/storagefakes"
+)
+
+var _ = Describe("RetainedDeployments", func() {
+	BeforeEach(func() {
+		By("overriding the default FakeEncryptor to not change the json on decryption")
+		encryptor = &storagefakes.FakeEncryptor{
+			DecryptStub: func(bytes []byte) ([]byte, error) {
+				if string(bytes) == `cannot-be-decrypted` {
+					return nil, errors.New("fake decryption error")
+				}
+				return bytes, nil
+			},
+			EncryptStub: func(bytes []byte) ([]byte, error) {
+				if strings.Contains(string(bytes), `cannot-be-encrypted`) {
+					return nil, errors.New("fake encryption error")
+				}
+				return []byte(`{"encrypted":` + string(bytes) + `}`), nil
+			},
+		}
+
+		store = storage.New(db, encryptor)
+		addFakeTerraformDeployments()
+	})
+
+	Describe("RetainTerraformDeployment", func() {
+		It("copies the stored workspace without decrypting it", func() {
+			Expect(store.RetainTerraformDeployment("fake-instance-id", "fake-id-1")).To(Succeed())
+
+			var receiver models.RetainedDeployment
+			Expect(db.Where("id = ?", "fake-id-1").First(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.ServiceInstanceID).To(Equal("fake-instance-id"))
+			Expect(receiver.Workspace).To(Equal(fakeWorkspace("fake-1", "1.2.3")))
+			Expect(encryptor.DecryptCallCount()).To(BeZero())
+			Expect(encryptor.EncryptCallCount()).To(BeZero())
+		})
+
+		It("replaces a deployment that was retained before", func() {
+			Expect(db.Create(&models.RetainedDeployment{ID: "fake-id-1", Workspace: fakeWorkspace("old", "")}).Error).NotTo(HaveOccurred())
+
+			Expect(store.RetainTerraformDeployment("fake-instance-id", "fake-id-1")).To(Succeed())
+
+			var receiver []models.RetainedDeployment
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(receiver[0].Workspace).To(Equal(fakeWorkspace("fake-1", "1.2.3")))
+		})
+
+		When("the deployment does not exist", func() {
+			It("returns an error", func() {
+				err := store.RetainTerraformDeployment("fake-instance-id", "not-there")
+				Expect(err).To(MatchError(ContainSubstring(`error finding terraform deployment "not-there"`)))
+			})
+		})
+	})
+
+	Describe("GetRetainedDeployment", func() {
+		It("reads the retained deployment", func() {
+			Expect(store.RetainTerraformDeployment("fake-instance-id", "fake-id-3")).To(Succeed())
+
+			r, err := store.GetRetainedDeployment("fake-id-3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.ID).To(Equal("fake-id-3"))
+			Expect(r.ServiceInstanceGUID).To(Equal("fake-instance-id"))
+			Expect(r.TFWorkspace().Modules[0].Name).To(Equal("fake-3"))
+			Expect(r.RetainedAt).NotTo(BeZero())
+		})
+
+		When("the retained deployment does not exist", func() {
+			It("returns an error", func() {
+				_, err := store.GetRetainedDeployment("not-there")
+				Expect(err).To(MatchError("could not find retained deployment: not-there"))
+			})
+		})
+
+		When("the workspace cannot be decoded", func() {
+			It("returns an error", func() {
+				Expect(db.Create(&models.RetainedDeployment{ID: "fake-bad-id", Workspace: []byte("cannot-be-decrypted")}).Error).NotTo(HaveOccurred())
+
+				_, err := store.GetRetainedDeployment("fake-bad-id")
+				Expect(err).To(MatchError(`error decoding retained workspace "fake-bad-id": decryption error: fake decryption error`))
+			})
+		})
+	})
+
+	Describe("GetRetainedDeployments", func() {
+		It("lists the retained deployments", func() {
+			Expect(store.RetainTerraformDeployment("fake-instance-id", "fake-id-1")).To(Succeed())
+			Expect(store.RetainTerraformDeployment("fake-instance-id", "fake-id-2")).To(Succeed())
+
+			r, err := store.GetRetainedDeployments()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).To(HaveLen(2))
+			Expect(r[0].ID).To(Equal("fake-id-1"))
+			Expect(r[1].ID).To(Equal("fake-id-2"))
+		})
+	})
+
+	Describe("DeleteRetainedDeployment", func() {
+		It("deletes the retained deployment", func() {
+			Expect(store.RetainTerraformDeployment("fake-instance-id", "fake-id-1")).To(Succeed())
+
+			Expect(store.DeleteRetainedDeployment("fake-id-1")).To(Succeed())
+
+			var count int64
+			Expect(db.Model(&models.RetainedDeployment{}).Count(&count).Error).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+		})
+	})
+})
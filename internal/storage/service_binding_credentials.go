@@ -33,6 +33,25 @@ func (s *Storage) CreateServiceBindingCredentials(binding ServiceBindingCredenti
 	return nil
 }
 
+func (s *Storage) UpdateServiceBindingCredentials(binding ServiceBindingCredentials) error {
+	encodedCreds, err := s.encodeJSON(binding.Credentials)
+	if err != nil {
+		return fmt.Errorf("error encoding credentials: %w", err)
+	}
+
+	var receiver models.ServiceBindingCredentials
+	if err := s.db.Where("service_instance_id = ? AND binding_id = ?", binding.ServiceInstanceGUID, binding.BindingGUID).First(&receiver).Error; err != nil {
+		return fmt.Errorf("error finding service credential binding: %w", err)
+	}
+
+	receiver.OtherDetails = encodedCreds
+	if err := s.db.Save(&receiver).Error; err != nil {
+		return fmt.Errorf("error updating service credential binding: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Storage) GetServiceBindingCredentials(bindingID, serviceInstanceID string) (ServiceBindingCredentials, error) {
 	exists, err := s.ExistsServiceBindingCredentials(bindingID, serviceInstanceID)
 	switch {
@@ -82,6 +101,23 @@ func (s *Storage) ExistsServiceBindingCredentials(bindingID, serviceInstanceID s
 	return count != 0, nil
 }
 
+// ExistsBinding is true when binding credentials or bind request details are stored for the binding,
+// for any service instance
+func (s *Storage) ExistsBinding(bindingID string) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.ServiceBindingCredentials{}).Where("binding_id = ?", bindingID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error counting service credential binding: %w", err)
+	}
+	if count != 0 {
+		return true, nil
+	}
+
+	if err := s.db.Model(&models.BindRequestDetails{}).Where("service_binding_id = ?", bindingID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error counting bind request details: %w", err)
+	}
+	return count != 0, nil
+}
+
 func (s *Storage) DeleteServiceBindingCredentials(bindingID, serviceInstanceID string) error {
 	err := s.db.Where("service_instance_id = ? AND binding_id = ?", serviceInstanceID, bindingID).Unscoped().Delete(&models.ServiceBindingCredentials{}).Error
 	if err != nil {
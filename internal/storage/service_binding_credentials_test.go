@@ -129,6 +129,45 @@ var _ = Describe("ServiceBindingCredentials", func() {
 		})
 	})
 
+	Describe("UpdateServiceBindingCredentials", func() {
+		BeforeEach(func() {
+			addFakeServiceCredentialBindings()
+		})
+
+		It("updates the credentials of the existing record", func() {
+			err := store.UpdateServiceBindingCredentials(storage.ServiceBindingCredentials{
+				ServiceInstanceGUID: "fake-instance-id",
+				BindingGUID:         "fake-binding-id",
+				Credentials:         storage.JSONObject{"fake-cred": "new-val"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var receiver models.ServiceBindingCredentials
+			Expect(db.Where("service_instance_id = ? AND binding_id = ?", "fake-instance-id", "fake-binding-id").First(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver.ServiceID).To(Equal("fake-service-id"))
+			Expect(receiver.OtherDetails).To(MatchJSON(`{"encrypted":{"fake-cred":"new-val"}}`))
+		})
+
+		When("encoding fails", func() {
+			It("returns an error", func() {
+				encryptor.EncryptReturns(nil, errors.New("bang"))
+
+				err := store.UpdateServiceBindingCredentials(storage.ServiceBindingCredentials{})
+				Expect(err).To(MatchError("error encoding credentials: encryption error: bang"))
+			})
+		})
+
+		When("the binding does not exist", func() {
+			It("returns an error", func() {
+				err := store.UpdateServiceBindingCredentials(storage.ServiceBindingCredentials{
+					ServiceInstanceGUID: "not-there",
+					BindingGUID:         "also-not-there",
+				})
+				Expect(err).To(MatchError(ContainSubstring("error finding service credential binding")))
+			})
+		})
+	})
+
 	Describe("DeleteServiceBindingCredentials", func() {
 		BeforeEach(func() {
 			addFakeServiceCredentialBindings()
@@ -15,6 +15,14 @@ type ServiceInstanceDetails struct {
 	PlanGUID         string
 	SpaceGUID        string
 	OrganizationGUID string
+
+	// DefinitionVersion is the version of the brokerpak whose service definition the instance is pinned to.
+	// It is empty for instances created before brokerpak versions were recorded.
+	DefinitionVersion string
+
+	// Released is true once the resources of the instance have been exported to be managed outside the broker.
+	// Deprovisioning a released instance only removes it from the broker.
+	Released bool
 }
 
 func (s *Storage) StoreServiceInstanceDetails(d ServiceInstanceDetails) error {
@@ -34,6 +42,8 @@ func (s *Storage) StoreServiceInstanceDetails(d ServiceInstanceDetails) error {
 	m.PlanID = d.PlanGUID
 	m.SpaceGUID = d.SpaceGUID
 	m.OrganizationGUID = d.OrganizationGUID
+	m.DefinitionVersion = d.DefinitionVersion
+	m.Released = d.Released
 
 	switch m.ID {
 	case "":
@@ -78,13 +88,15 @@ func (s *Storage) GetServiceInstanceDetails(guid string) (ServiceInstanceDetails
 	}
 
 	return ServiceInstanceDetails{
-		GUID:             guid,
-		Name:             receiver.Name,
-		Outputs:          decoded,
-		ServiceGUID:      receiver.ServiceID,
-		PlanGUID:         receiver.PlanID,
-		SpaceGUID:        receiver.SpaceGUID,
-		OrganizationGUID: receiver.OrganizationGUID,
+		GUID:              guid,
+		Name:              receiver.Name,
+		Outputs:           decoded,
+		ServiceGUID:       receiver.ServiceID,
+		PlanGUID:          receiver.PlanID,
+		SpaceGUID:         receiver.SpaceGUID,
+		OrganizationGUID:  receiver.OrganizationGUID,
+		DefinitionVersion: receiver.DefinitionVersion,
+		Released:          receiver.Released,
 	}, nil
 }
 
@@ -104,6 +116,23 @@ func (s *Storage) GetServiceInstancesIDs() (ids []string, err error) {
 	return ids, nil
 }
 
+// CountServiceInstanceDetails counts the service instances that match the service, plan, space and
+// organization of the filter. Fields that are empty in the filter match any value.
+func (s *Storage) CountServiceInstanceDetails(filter ServiceInstanceDetails) (int64, error) {
+	query := s.db.Model(&models.ServiceInstanceDetails{}).Where(&models.ServiceInstanceDetails{
+		ServiceID:        filter.ServiceGUID,
+		PlanID:           filter.PlanGUID,
+		SpaceGUID:        filter.SpaceGUID,
+		OrganizationGUID: filter.OrganizationGUID,
+	})
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("error counting service instance details: %w", err)
+	}
+	return count, nil
+}
+
 func (s *Storage) DeleteServiceInstanceDetails(guid string) error {
 	err := s.db.Where("id = ?", guid).Unscoped().Delete(&models.ServiceInstanceDetails{}).Error
 	if err != nil {
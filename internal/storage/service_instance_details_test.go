@@ -13,13 +13,15 @@ var _ = Describe("ServiceInstanceDetails", func() {
 	Describe("StoreServiceInstanceDetails", func() {
 		It("creates the right object in the database", func() {
 			err := store.StoreServiceInstanceDetails(storage.ServiceInstanceDetails{
-				GUID:             "fake-guid",
-				Name:             "fake-name",
-				Outputs:          map[string]any{"foo": "bar"},
-				ServiceGUID:      "fake-service-guid",
-				PlanGUID:         "fake-plan-guid",
-				SpaceGUID:        "fake-space-guid",
-				OrganizationGUID: "fake-org-guid",
+				GUID:              "fake-guid",
+				Name:              "fake-name",
+				Outputs:           map[string]any{"foo": "bar"},
+				ServiceGUID:       "fake-service-guid",
+				PlanGUID:          "fake-plan-guid",
+				SpaceGUID:         "fake-space-guid",
+				OrganizationGUID:  "fake-org-guid",
+				DefinitionVersion: "1.2.3",
+				Released:          true,
 			})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -32,6 +34,8 @@ var _ = Describe("ServiceInstanceDetails", func() {
 			Expect(receiver.PlanID).To(Equal("fake-plan-guid"))
 			Expect(receiver.SpaceGUID).To(Equal("fake-space-guid"))
 			Expect(receiver.OrganizationGUID).To(Equal("fake-org-guid"))
+			Expect(receiver.DefinitionVersion).To(Equal("1.2.3"))
+			Expect(receiver.Released).To(BeTrue())
 		})
 
 		When("encoding fails", func() {
@@ -88,6 +92,8 @@ var _ = Describe("ServiceInstanceDetails", func() {
 			Expect(r.PlanGUID).To(Equal("fake-plan-id-2"))
 			Expect(r.SpaceGUID).To(Equal("fake-space-guid-2"))
 			Expect(r.OrganizationGUID).To(Equal("fake-org-guid-2"))
+			Expect(r.DefinitionVersion).To(Equal("2.0.0"))
+			Expect(r.Released).To(BeTrue())
 		})
 
 		When("decoding fails", func() {
@@ -157,6 +163,32 @@ var _ = Describe("ServiceInstanceDetails", func() {
 		})
 	})
 
+	Describe("CountServiceInstanceDetails", func() {
+		BeforeEach(func() {
+			addFakeServiceInstanceDetails()
+			Expect(db.Create(&models.ServiceInstanceDetails{
+				ID:               "fake-id-4",
+				ServiceID:        "fake-service-id-1",
+				PlanID:           "fake-plan-id-4",
+				SpaceGUID:        "fake-space-guid-4",
+				OrganizationGUID: "fake-org-guid-1",
+			}).Error).NotTo(HaveOccurred())
+		})
+
+		DescribeTable(
+			"counts the matching service instances",
+			func(filter storage.ServiceInstanceDetails, expected int) {
+				Expect(store.CountServiceInstanceDetails(filter)).To(BeEquivalentTo(expected))
+			},
+			Entry("empty filter", storage.ServiceInstanceDetails{}, 4),
+			Entry("service", storage.ServiceInstanceDetails{ServiceGUID: "fake-service-id-1"}, 2),
+			Entry("service and plan", storage.ServiceInstanceDetails{ServiceGUID: "fake-service-id-1", PlanGUID: "fake-plan-id-4"}, 1),
+			Entry("service and organization", storage.ServiceInstanceDetails{ServiceGUID: "fake-service-id-1", OrganizationGUID: "fake-org-guid-1"}, 2),
+			Entry("service and space", storage.ServiceInstanceDetails{ServiceGUID: "fake-service-id-1", SpaceGUID: "fake-space-guid-1"}, 1),
+			Entry("no match", storage.ServiceInstanceDetails{ServiceGUID: "fake-service-id-2", SpaceGUID: "fake-space-guid-1"}, 0),
+		)
+	})
+
 	Describe("DeleteServiceInstanceDetails", func() {
 		BeforeEach(func() {
 			addFakeServiceInstanceDetails()
@@ -191,13 +223,15 @@ func addFakeServiceInstanceDetails() {
 		OrganizationGUID: "fake-org-guid-1",
 	}).Error).NotTo(HaveOccurred())
 	Expect(db.Create(&models.ServiceInstanceDetails{
-		ID:               "fake-id-2",
-		Name:             "fake-name-2",
-		OtherDetails:     []byte(`{"foo":"bar-2"}`),
-		ServiceID:        "fake-service-id-2",
-		PlanID:           "fake-plan-id-2",
-		SpaceGUID:        "fake-space-guid-2",
-		OrganizationGUID: "fake-org-guid-2",
+		ID:                "fake-id-2",
+		Name:              "fake-name-2",
+		OtherDetails:      []byte(`{"foo":"bar-2"}`),
+		ServiceID:         "fake-service-id-2",
+		PlanID:            "fake-plan-id-2",
+		SpaceGUID:         "fake-space-guid-2",
+		OrganizationGUID:  "fake-org-guid-2",
+		DefinitionVersion: "2.0.0",
+		Released:          true,
 	}).Error).NotTo(HaveOccurred())
 	Expect(db.Create(&models.ServiceInstanceDetails{
 		ID:               "fake-id-3",
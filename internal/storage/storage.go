@@ -36,4 +36,17 @@ func New(db *gorm.DB, encryptor Encryptor) *Storage {
 	}
 }
 
+// LockFileDir is the directory that lock files are written to while operations are in progress
+func (s *Storage) LockFileDir() string {
+	return s.lockFileDir
+}
+
+// Transaction calls fn with a Storage whose writes are made in a single database transaction. The
+// transaction is rolled back if fn returns an error.
+func (s *Storage) Transaction(fn func(tx *Storage) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&Storage{db: tx, encryptor: s.encryptor, lockFileDir: s.lockFileDir})
+	})
+}
+
 type JSONObject map[string]any
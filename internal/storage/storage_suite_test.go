@@ -37,6 +37,7 @@ var _ = BeforeEach(func() {
 	Expect(db.Migrator().CreateTable(&models.BindRequestDetails{})).NotTo(HaveOccurred())
 	Expect(db.Migrator().CreateTable(&models.ServiceInstanceDetails{})).NotTo(HaveOccurred())
 	Expect(db.Migrator().CreateTable(&models.TerraformDeployment{})).NotTo(HaveOccurred())
+	Expect(db.Migrator().CreateTable(&models.RetainedDeployment{})).NotTo(HaveOccurred())
 
 	encryptor = &storagefakes.FakeEncryptor{
 		DecryptStub: func(bytes []byte) ([]byte, error) {
@@ -16,6 +16,7 @@ func (s *Storage) UpdateAllRecords() error {
 		s.updateAllProvisionRequestDetails,
 		s.updateAllServiceInstanceDetails,
 		s.updateAllTerraformDeployments,
+		s.updateAllRetainedDeployments,
 	}
 	for _, e := range updaters {
 		if err := e(); err != nil {
@@ -154,3 +155,27 @@ func (s *Storage) updateAllTerraformDeployments() error {
 
 	return nil
 }
+
+func (s *Storage) updateAllRetainedDeployments() error {
+	var retainedDeploymentBatch []models.RetainedDeployment
+	result := s.db.FindInBatches(&retainedDeploymentBatch, 100, func(tx *gorm.DB, batchNumber int) error {
+		for i := range retainedDeploymentBatch {
+			data, err := s.decodeBytes(retainedDeploymentBatch[i].Workspace)
+			if err != nil {
+				return fmt.Errorf("decode error for %q: %w", retainedDeploymentBatch[i].ID, err)
+			}
+
+			retainedDeploymentBatch[i].Workspace, err = s.encodeBytes(data)
+			if err != nil {
+				return fmt.Errorf("encode error for %q: %w", retainedDeploymentBatch[i].ID, err)
+			}
+		}
+
+		return tx.Save(&retainedDeploymentBatch).Error
+	})
+	if result.Error != nil {
+		return fmt.Errorf("error re-encoding retained deployment: %w", result.Error)
+	}
+
+	return nil
+}
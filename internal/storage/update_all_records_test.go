@@ -15,6 +15,7 @@ var _ = Describe("UpdateAllRecords", func() {
 		addFakeBindRequestDetails()
 		addFakeServiceInstanceDetails()
 		addFakeTerraformDeployments()
+		Expect(db.Create(&models.RetainedDeployment{ID: "fake-retained-id", Workspace: fakeWorkspace("fake-retained", "1.2.3")}).Error).NotTo(HaveOccurred())
 	})
 
 	It("updates all the records with the latest encoding", func() {
@@ -71,6 +72,13 @@ var _ = Describe("UpdateAllRecords", func() {
 			Expect(receiver[1].Workspace).To(Equal(fakeEncryptedWorkspace("fake-2", "")))
 			Expect(receiver[2].Workspace).To(Equal(fakeEncryptedWorkspace("fake-3", "1.2.4")))
 		})
+
+		By("checking retained deployments", func() {
+			var receiver []models.RetainedDeployment
+			Expect(db.Find(&receiver).Error).NotTo(HaveOccurred())
+			Expect(receiver).To(HaveLen(1))
+			Expect(receiver[0].Workspace).To(Equal(fakeEncryptedWorkspace("fake-retained", "1.2.3")))
+		})
 	})
 
 	Describe("errors", func() {
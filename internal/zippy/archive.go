@@ -3,6 +3,7 @@ package zippy
 import (
 	"archive/zip"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -20,11 +21,21 @@ func Archive(sourceDirectory, destinationZip string, compress bool) error {
 		_ = f.Close()
 	}(fd)
 
-	w := zip.NewWriter(fd)
-	defer func(zw *zip.Writer) {
-		_ = zw.Close()
-	}(w)
+	return WriteArchive(sourceDirectory, fd, compress)
+}
+
+// WriteArchive writes a zip archive of the source directory to out, so that the caller can choose
+// how the destination is created, for example with restricted permissions
+func WriteArchive(sourceDirectory string, out io.Writer, compress bool) error {
+	w := zip.NewWriter(out)
+	if err := addDirectory(w, sourceDirectory, compress); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
 
+func addDirectory(w *zip.Writer, sourceDirectory string, compress bool) error {
 	sourceDirectory = path.Clean(sourceDirectory)
 	return filepath.Walk(sourceDirectory, func(path string, info os.FileInfo, walkErr error) error {
 		if walkErr != nil {
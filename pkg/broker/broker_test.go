@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
@@ -163,7 +164,7 @@ func ExampleServiceDefinition_CatalogEntry() {
 
 	srvc = service.CatalogEntry()
 
-	eq := reflect.DeepEqual(srvc.ToPlain().Plans[0].Schemas, service.createSchemas())
+	eq := reflect.DeepEqual(srvc.ToPlain().Plans[0].Schemas, service.createSchemas(service.Plans[0]))
 
 	fmt.Println("schema was generated?", eq)
 
@@ -952,6 +953,120 @@ func TestServiceDefinition_BindVariables(t *testing.T) {
 	}
 }
 
+func TestServiceDefinition_ResourceTags(t *testing.T) {
+	plan := ServicePlan{ServicePlan: domain.ServicePlan{ID: "plan-id", Name: "small"}}
+	service := ServiceDefinition{
+		ID:   "service-id",
+		Name: "tagged-service",
+		ProvisionComputedVariables: []varcontext.DefaultVariable{
+			{Name: "labels", Default: "${json.marshal(request.default_labels)}", Overwrite: true},
+		},
+		GlobalLabels: map[string]string{"key1": "value1"},
+		ResourceTags: map[string]string{
+			"cost-center": "cc-${organization_guid}",
+			"plan":        "${plan_name}",
+			"binding":     "${binding_id}",
+		},
+	}
+
+	t.Run("provision merges tags into the labels variable", func(t *testing.T) {
+		details := paramparser.ProvisionDetails{OrganizationGUID: "org-guid", SpaceGUID: "space-guid"}
+		vars, err := service.ProvisionVariables("instance-id", details, plan, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var labels map[string]string
+		if err := json.Unmarshal([]byte(vars.GetString("labels")), &labels); err != nil {
+			t.Fatalf("labels are not a JSON object: %s", err)
+		}
+		expected := map[string]string{
+			"key1":                  "value1",
+			"pcf-organization-guid": "org-guid",
+			"pcf-space-guid":        "space-guid",
+			"pcf-instance-id":       "instance-id",
+			"cost-center":           "cc-org-guid",
+			"plan":                  "small",
+			"binding":               "",
+		}
+		if !reflect.DeepEqual(labels, expected) {
+			t.Errorf("Expected labels: %v got %v", expected, labels)
+		}
+	})
+
+	t.Run("bind adds the labels variable", func(t *testing.T) {
+		instance := storage.ServiceInstanceDetails{GUID: "instance-id", OrganizationGUID: "org-guid", SpaceGUID: "space-guid"}
+		vars, err := service.BindVariables(instance, "binding-id", paramparser.BindDetails{}, &plan, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := map[string]any{
+			"key1":                  "value1",
+			"pcf-organization-guid": "org-guid",
+			"pcf-space-guid":        "space-guid",
+			"pcf-instance-id":       "instance-id",
+			"cost-center":           "cc-org-guid",
+			"plan":                  "small",
+			"binding":               "binding-id",
+		}
+		if actual := vars.ToMap()["labels"]; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected labels: %v got %v", expected, actual)
+		}
+	})
+
+	t.Run("invalid tag template", func(t *testing.T) {
+		broken := service
+		broken.ResourceTags = map[string]string{"broken": "${unknown_var}"}
+
+		_, err := broken.ProvisionVariables("instance-id", paramparser.ProvisionDetails{}, plan, nil)
+		if err == nil || !strings.Contains(err.Error(), `error evaluating resource tag "broken"`) {
+			t.Errorf("expected resource tag error, got %v", err)
+		}
+	})
+}
+
+func TestServiceDefinition_VariablesUseBoundConfig(t *testing.T) {
+	viper.Set("test.region", "global-region")
+	defer viper.Reset()
+
+	settings := viper.New()
+	settings.Set("test.region", "bound-region")
+
+	plan := ServicePlan{ServicePlan: domain.ServicePlan{ID: "plan-id", Name: "small"}}
+	service := ServiceDefinition{
+		ID:   "service-id",
+		Name: "configured-service",
+		ProvisionComputedVariables: []varcontext.DefaultVariable{
+			{Name: "region", Default: `${config("test.region")}`, Overwrite: true},
+		},
+		BindComputedVariables: []varcontext.DefaultVariable{
+			{Name: "region", Default: `${config("test.region")}`, Overwrite: true},
+		},
+		ResourceTags: map[string]string{"region": `${config("test.region")}`},
+	}
+	service.UseConfig(settings)
+
+	vars, err := service.ProvisionVariables("instance-id", paramparser.ProvisionDetails{}, plan, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual := vars.GetString("region"); actual != "bound-region" {
+		t.Errorf("Expected provision region: %q got %q", "bound-region", actual)
+	}
+	if actual := vars.ToMap()["labels"].(map[string]any)["region"]; actual != "bound-region" {
+		t.Errorf("Expected region label: %q got %q", "bound-region", actual)
+	}
+
+	vars, err = service.BindVariables(storage.ServiceInstanceDetails{GUID: "instance-id"}, "binding-id", paramparser.BindDetails{}, &plan, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual := vars.GetString("region"); actual != "bound-region" {
+		t.Errorf("Expected bind region: %q got %q", "bound-region", actual)
+	}
+}
+
 func TestServiceDefinition_createSchemas(t *testing.T) {
 	service := ServiceDefinition{
 		ID:   "00000000-0000-0000-0000-000000000000",
@@ -961,13 +1076,15 @@ func TestServiceDefinition_createSchemas(t *testing.T) {
 		},
 		ProvisionInputVariables: []BrokerVariable{
 			{FieldName: "location", Type: JSONTypeString, Default: "us"},
+			{FieldName: "name", Type: JSONTypeString, Required: true},
+			{FieldName: "region", Type: JSONTypeString, ProhibitUpdate: true},
 		},
 		BindInputVariables: []BrokerVariable{
 			{FieldName: "name", Type: JSONTypeString, Default: "name"},
 		},
 	}
 
-	schemas := service.createSchemas()
+	schemas := service.createSchemas(service.Plans[0])
 	if schemas == nil {
 		t.Fatal("Schemas was nil, expected non-nil value")
 	}
@@ -983,10 +1100,14 @@ func TestServiceDefinition_createSchemas(t *testing.T) {
 		t.Errorf("expected create params to be: %v got %v", expectedCreateParams, instanceCreate.Parameters)
 	}
 
-	// It leaves the instance update schema blank.
+	// it populates the instance update schema with the updatable fields, none of which are required
 	instanceUpdate := schemas.Instance.Update
-	if instanceUpdate.Parameters != nil {
-		t.Error("instance update params were not nil, expected nil")
+	expectedUpdateParams := CreateJSONSchema([]BrokerVariable{
+		{FieldName: "location", Type: JSONTypeString, Default: "us"},
+		{FieldName: "name", Type: JSONTypeString},
+	})
+	if !reflect.DeepEqual(instanceUpdate.Parameters, expectedUpdateParams) {
+		t.Errorf("expected update params to be: %v got %v", expectedUpdateParams, instanceUpdate.Parameters)
 	}
 
 	// it populates the binding create schema with the fields in BindInputVariables.
@@ -1001,6 +1122,26 @@ func TestServiceDefinition_createSchemas(t *testing.T) {
 	}
 }
 
+func TestServiceDefinition_UpdateJSONSchema(t *testing.T) {
+	service := ServiceDefinition{
+		ProvisionInputVariables: []BrokerVariable{
+			{FieldName: "location", Type: JSONTypeString},
+			{FieldName: "tier", Type: JSONTypeString},
+			{FieldName: "storage_gb", Type: JSONTypeInteger},
+		},
+	}
+	plan := ServicePlan{
+		ServiceProperties:  map[string]any{"tier": "gold"},
+		ProvisionOverrides: map[string]any{"location": "eu"},
+	}
+
+	schema := service.UpdateJSONSchema(plan)
+	expected := CreateJSONSchema([]BrokerVariable{{FieldName: "storage_gb", Type: JSONTypeInteger}})
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("expected update schema to be: %v got %v", expected, schema)
+	}
+}
+
 func expectError(t *testing.T, expected, actual error) {
 	t.Helper()
 	expectedErr := expected != nil
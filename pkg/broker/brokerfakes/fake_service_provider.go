@@ -11,6 +11,19 @@ import (
 )
 
 type FakeServiceProvider struct {
+	AdoptStub        func(context.Context, *varcontext.VarContext, map[string]string) error
+	adoptMutex       sync.RWMutex
+	adoptArgsForCall []struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+		arg3 map[string]string
+	}
+	adoptReturns struct {
+		result1 error
+	}
+	adoptReturnsOnCall map[int]struct {
+		result1 error
+	}
 	BindStub        func(context.Context, *varcontext.VarContext) (map[string]any, error)
 	bindMutex       sync.RWMutex
 	bindArgsForCall []struct {
@@ -25,6 +38,18 @@ type FakeServiceProvider struct {
 		result1 map[string]any
 		result2 error
 	}
+	BindAsyncStub        func(context.Context, *varcontext.VarContext) error
+	bindAsyncMutex       sync.RWMutex
+	bindAsyncArgsForCall []struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}
+	bindAsyncReturns struct {
+		result1 error
+	}
+	bindAsyncReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CheckOperationConstraintsStub        func(string, string) error
 	checkOperationConstraintsMutex       sync.RWMutex
 	checkOperationConstraintsArgsForCall []struct {
@@ -100,6 +125,21 @@ type FakeServiceProvider struct {
 		result1 *string
 		result2 error
 	}
+	GetBindingOutputsStub        func(context.Context, string, string) (storage.JSONObject, error)
+	getBindingOutputsMutex       sync.RWMutex
+	getBindingOutputsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getBindingOutputsReturns struct {
+		result1 storage.JSONObject
+		result2 error
+	}
+	getBindingOutputsReturnsOnCall map[int]struct {
+		result1 storage.JSONObject
+		result2 error
+	}
 	GetImportedPropertiesStub        func(context.Context, string, []broker.BrokerVariable, map[string]any) (map[string]any, error)
 	getImportedPropertiesMutex       sync.RWMutex
 	getImportedPropertiesArgsForCall []struct {
@@ -130,6 +170,25 @@ type FakeServiceProvider struct {
 		result1 storage.JSONObject
 		result2 error
 	}
+	PollBindingStub        func(context.Context, string, string) (bool, string, string, error)
+	pollBindingMutex       sync.RWMutex
+	pollBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	pollBindingReturns struct {
+		result1 bool
+		result2 string
+		result3 string
+		result4 error
+	}
+	pollBindingReturnsOnCall map[int]struct {
+		result1 bool
+		result2 string
+		result3 string
+		result4 error
+	}
 	PollInstanceStub        func(context.Context, string) (bool, string, string, error)
 	pollInstanceMutex       sync.RWMutex
 	pollInstanceArgsForCall []struct {
@@ -174,6 +233,20 @@ type FakeServiceProvider struct {
 	unbindReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UnbindAsyncStub        func(context.Context, string, string, *varcontext.VarContext) error
+	unbindAsyncMutex       sync.RWMutex
+	unbindAsyncArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *varcontext.VarContext
+	}
+	unbindAsyncReturns struct {
+		result1 error
+	}
+	unbindAsyncReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateStub        func(context.Context, *varcontext.VarContext) error
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
@@ -186,6 +259,20 @@ type FakeServiceProvider struct {
 	updateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateBindingStub        func(context.Context, *varcontext.VarContext) (map[string]any, error)
+	updateBindingMutex       sync.RWMutex
+	updateBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}
+	updateBindingReturns struct {
+		result1 map[string]any
+		result2 error
+	}
+	updateBindingReturnsOnCall map[int]struct {
+		result1 map[string]any
+		result2 error
+	}
 	UpgradeBindingsStub        func(context.Context, *varcontext.VarContext, []*varcontext.VarContext) error
 	upgradeBindingsMutex       sync.RWMutex
 	upgradeBindingsArgsForCall []struct {
@@ -217,6 +304,69 @@ type FakeServiceProvider struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeServiceProvider) Adopt(arg1 context.Context, arg2 *varcontext.VarContext, arg3 map[string]string) error {
+	fake.adoptMutex.Lock()
+	ret, specificReturn := fake.adoptReturnsOnCall[len(fake.adoptArgsForCall)]
+	fake.adoptArgsForCall = append(fake.adoptArgsForCall, struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+		arg3 map[string]string
+	}{arg1, arg2, arg3})
+	stub := fake.AdoptStub
+	fakeReturns := fake.adoptReturns
+	fake.recordInvocation("Adopt", []interface{}{arg1, arg2, arg3})
+	fake.adoptMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeServiceProvider) AdoptCallCount() int {
+	fake.adoptMutex.RLock()
+	defer fake.adoptMutex.RUnlock()
+	return len(fake.adoptArgsForCall)
+}
+
+func (fake *FakeServiceProvider) AdoptCalls(stub func(context.Context, *varcontext.VarContext, map[string]string) error) {
+	fake.adoptMutex.Lock()
+	defer fake.adoptMutex.Unlock()
+	fake.AdoptStub = stub
+}
+
+func (fake *FakeServiceProvider) AdoptArgsForCall(i int) (context.Context, *varcontext.VarContext, map[string]string) {
+	fake.adoptMutex.RLock()
+	defer fake.adoptMutex.RUnlock()
+	argsForCall := fake.adoptArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeServiceProvider) AdoptReturns(result1 error) {
+	fake.adoptMutex.Lock()
+	defer fake.adoptMutex.Unlock()
+	fake.AdoptStub = nil
+	fake.adoptReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceProvider) AdoptReturnsOnCall(i int, result1 error) {
+	fake.adoptMutex.Lock()
+	defer fake.adoptMutex.Unlock()
+	fake.AdoptStub = nil
+	if fake.adoptReturnsOnCall == nil {
+		fake.adoptReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.adoptReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeServiceProvider) Bind(arg1 context.Context, arg2 *varcontext.VarContext) (map[string]any, error) {
 	fake.bindMutex.Lock()
 	ret, specificReturn := fake.bindReturnsOnCall[len(fake.bindArgsForCall)]
@@ -282,6 +432,68 @@ func (fake *FakeServiceProvider) BindReturnsOnCall(i int, result1 map[string]any
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) BindAsync(arg1 context.Context, arg2 *varcontext.VarContext) error {
+	fake.bindAsyncMutex.Lock()
+	ret, specificReturn := fake.bindAsyncReturnsOnCall[len(fake.bindAsyncArgsForCall)]
+	fake.bindAsyncArgsForCall = append(fake.bindAsyncArgsForCall, struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}{arg1, arg2})
+	stub := fake.BindAsyncStub
+	fakeReturns := fake.bindAsyncReturns
+	fake.recordInvocation("BindAsync", []interface{}{arg1, arg2})
+	fake.bindAsyncMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeServiceProvider) BindAsyncCallCount() int {
+	fake.bindAsyncMutex.RLock()
+	defer fake.bindAsyncMutex.RUnlock()
+	return len(fake.bindAsyncArgsForCall)
+}
+
+func (fake *FakeServiceProvider) BindAsyncCalls(stub func(context.Context, *varcontext.VarContext) error) {
+	fake.bindAsyncMutex.Lock()
+	defer fake.bindAsyncMutex.Unlock()
+	fake.BindAsyncStub = stub
+}
+
+func (fake *FakeServiceProvider) BindAsyncArgsForCall(i int) (context.Context, *varcontext.VarContext) {
+	fake.bindAsyncMutex.RLock()
+	defer fake.bindAsyncMutex.RUnlock()
+	argsForCall := fake.bindAsyncArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) BindAsyncReturns(result1 error) {
+	fake.bindAsyncMutex.Lock()
+	defer fake.bindAsyncMutex.Unlock()
+	fake.BindAsyncStub = nil
+	fake.bindAsyncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceProvider) BindAsyncReturnsOnCall(i int, result1 error) {
+	fake.bindAsyncMutex.Lock()
+	defer fake.bindAsyncMutex.Unlock()
+	fake.BindAsyncStub = nil
+	if fake.bindAsyncReturnsOnCall == nil {
+		fake.bindAsyncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.bindAsyncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeServiceProvider) CheckOperationConstraints(arg1 string, arg2 string) error {
 	fake.checkOperationConstraintsMutex.Lock()
 	ret, specificReturn := fake.checkOperationConstraintsReturnsOnCall[len(fake.checkOperationConstraintsArgsForCall)]
@@ -658,6 +870,72 @@ func (fake *FakeServiceProvider) DeprovisionReturnsOnCall(i int, result1 *string
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) GetBindingOutputs(arg1 context.Context, arg2 string, arg3 string) (storage.JSONObject, error) {
+	fake.getBindingOutputsMutex.Lock()
+	ret, specificReturn := fake.getBindingOutputsReturnsOnCall[len(fake.getBindingOutputsArgsForCall)]
+	fake.getBindingOutputsArgsForCall = append(fake.getBindingOutputsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetBindingOutputsStub
+	fakeReturns := fake.getBindingOutputsReturns
+	fake.recordInvocation("GetBindingOutputs", []interface{}{arg1, arg2, arg3})
+	fake.getBindingOutputsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsCallCount() int {
+	fake.getBindingOutputsMutex.RLock()
+	defer fake.getBindingOutputsMutex.RUnlock()
+	return len(fake.getBindingOutputsArgsForCall)
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsCalls(stub func(context.Context, string, string) (storage.JSONObject, error)) {
+	fake.getBindingOutputsMutex.Lock()
+	defer fake.getBindingOutputsMutex.Unlock()
+	fake.GetBindingOutputsStub = stub
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsArgsForCall(i int) (context.Context, string, string) {
+	fake.getBindingOutputsMutex.RLock()
+	defer fake.getBindingOutputsMutex.RUnlock()
+	argsForCall := fake.getBindingOutputsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsReturns(result1 storage.JSONObject, result2 error) {
+	fake.getBindingOutputsMutex.Lock()
+	defer fake.getBindingOutputsMutex.Unlock()
+	fake.GetBindingOutputsStub = nil
+	fake.getBindingOutputsReturns = struct {
+		result1 storage.JSONObject
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) GetBindingOutputsReturnsOnCall(i int, result1 storage.JSONObject, result2 error) {
+	fake.getBindingOutputsMutex.Lock()
+	defer fake.getBindingOutputsMutex.Unlock()
+	fake.GetBindingOutputsStub = nil
+	if fake.getBindingOutputsReturnsOnCall == nil {
+		fake.getBindingOutputsReturnsOnCall = make(map[int]struct {
+			result1 storage.JSONObject
+			result2 error
+		})
+	}
+	fake.getBindingOutputsReturnsOnCall[i] = struct {
+		result1 storage.JSONObject
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceProvider) GetImportedProperties(arg1 context.Context, arg2 string, arg3 []broker.BrokerVariable, arg4 map[string]any) (map[string]any, error) {
 	var arg3Copy []broker.BrokerVariable
 	if arg3 != nil {
@@ -795,6 +1073,78 @@ func (fake *FakeServiceProvider) GetTerraformOutputsReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) PollBinding(arg1 context.Context, arg2 string, arg3 string) (bool, string, string, error) {
+	fake.pollBindingMutex.Lock()
+	ret, specificReturn := fake.pollBindingReturnsOnCall[len(fake.pollBindingArgsForCall)]
+	fake.pollBindingArgsForCall = append(fake.pollBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.PollBindingStub
+	fakeReturns := fake.pollBindingReturns
+	fake.recordInvocation("PollBinding", []interface{}{arg1, arg2, arg3})
+	fake.pollBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3, fakeReturns.result4
+}
+
+func (fake *FakeServiceProvider) PollBindingCallCount() int {
+	fake.pollBindingMutex.RLock()
+	defer fake.pollBindingMutex.RUnlock()
+	return len(fake.pollBindingArgsForCall)
+}
+
+func (fake *FakeServiceProvider) PollBindingCalls(stub func(context.Context, string, string) (bool, string, string, error)) {
+	fake.pollBindingMutex.Lock()
+	defer fake.pollBindingMutex.Unlock()
+	fake.PollBindingStub = stub
+}
+
+func (fake *FakeServiceProvider) PollBindingArgsForCall(i int) (context.Context, string, string) {
+	fake.pollBindingMutex.RLock()
+	defer fake.pollBindingMutex.RUnlock()
+	argsForCall := fake.pollBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeServiceProvider) PollBindingReturns(result1 bool, result2 string, result3 string, result4 error) {
+	fake.pollBindingMutex.Lock()
+	defer fake.pollBindingMutex.Unlock()
+	fake.PollBindingStub = nil
+	fake.pollBindingReturns = struct {
+		result1 bool
+		result2 string
+		result3 string
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeServiceProvider) PollBindingReturnsOnCall(i int, result1 bool, result2 string, result3 string, result4 error) {
+	fake.pollBindingMutex.Lock()
+	defer fake.pollBindingMutex.Unlock()
+	fake.PollBindingStub = nil
+	if fake.pollBindingReturnsOnCall == nil {
+		fake.pollBindingReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 string
+			result3 string
+			result4 error
+		})
+	}
+	fake.pollBindingReturnsOnCall[i] = struct {
+		result1 bool
+		result2 string
+		result3 string
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
 func (fake *FakeServiceProvider) PollInstance(arg1 context.Context, arg2 string) (bool, string, string, error) {
 	fake.pollInstanceMutex.Lock()
 	ret, specificReturn := fake.pollInstanceReturnsOnCall[len(fake.pollInstanceArgsForCall)]
@@ -992,6 +1342,70 @@ func (fake *FakeServiceProvider) UnbindReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeServiceProvider) UnbindAsync(arg1 context.Context, arg2 string, arg3 string, arg4 *varcontext.VarContext) error {
+	fake.unbindAsyncMutex.Lock()
+	ret, specificReturn := fake.unbindAsyncReturnsOnCall[len(fake.unbindAsyncArgsForCall)]
+	fake.unbindAsyncArgsForCall = append(fake.unbindAsyncArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *varcontext.VarContext
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.UnbindAsyncStub
+	fakeReturns := fake.unbindAsyncReturns
+	fake.recordInvocation("UnbindAsync", []interface{}{arg1, arg2, arg3, arg4})
+	fake.unbindAsyncMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncCallCount() int {
+	fake.unbindAsyncMutex.RLock()
+	defer fake.unbindAsyncMutex.RUnlock()
+	return len(fake.unbindAsyncArgsForCall)
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncCalls(stub func(context.Context, string, string, *varcontext.VarContext) error) {
+	fake.unbindAsyncMutex.Lock()
+	defer fake.unbindAsyncMutex.Unlock()
+	fake.UnbindAsyncStub = stub
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncArgsForCall(i int) (context.Context, string, string, *varcontext.VarContext) {
+	fake.unbindAsyncMutex.RLock()
+	defer fake.unbindAsyncMutex.RUnlock()
+	argsForCall := fake.unbindAsyncArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncReturns(result1 error) {
+	fake.unbindAsyncMutex.Lock()
+	defer fake.unbindAsyncMutex.Unlock()
+	fake.UnbindAsyncStub = nil
+	fake.unbindAsyncReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceProvider) UnbindAsyncReturnsOnCall(i int, result1 error) {
+	fake.unbindAsyncMutex.Lock()
+	defer fake.unbindAsyncMutex.Unlock()
+	fake.UnbindAsyncStub = nil
+	if fake.unbindAsyncReturnsOnCall == nil {
+		fake.unbindAsyncReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unbindAsyncReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeServiceProvider) Update(arg1 context.Context, arg2 *varcontext.VarContext) error {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
@@ -1054,6 +1468,71 @@ func (fake *FakeServiceProvider) UpdateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeServiceProvider) UpdateBinding(arg1 context.Context, arg2 *varcontext.VarContext) (map[string]any, error) {
+	fake.updateBindingMutex.Lock()
+	ret, specificReturn := fake.updateBindingReturnsOnCall[len(fake.updateBindingArgsForCall)]
+	fake.updateBindingArgsForCall = append(fake.updateBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 *varcontext.VarContext
+	}{arg1, arg2})
+	stub := fake.UpdateBindingStub
+	fakeReturns := fake.updateBindingReturns
+	fake.recordInvocation("UpdateBinding", []interface{}{arg1, arg2})
+	fake.updateBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) UpdateBindingCallCount() int {
+	fake.updateBindingMutex.RLock()
+	defer fake.updateBindingMutex.RUnlock()
+	return len(fake.updateBindingArgsForCall)
+}
+
+func (fake *FakeServiceProvider) UpdateBindingCalls(stub func(context.Context, *varcontext.VarContext) (map[string]any, error)) {
+	fake.updateBindingMutex.Lock()
+	defer fake.updateBindingMutex.Unlock()
+	fake.UpdateBindingStub = stub
+}
+
+func (fake *FakeServiceProvider) UpdateBindingArgsForCall(i int) (context.Context, *varcontext.VarContext) {
+	fake.updateBindingMutex.RLock()
+	defer fake.updateBindingMutex.RUnlock()
+	argsForCall := fake.updateBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) UpdateBindingReturns(result1 map[string]any, result2 error) {
+	fake.updateBindingMutex.Lock()
+	defer fake.updateBindingMutex.Unlock()
+	fake.UpdateBindingStub = nil
+	fake.updateBindingReturns = struct {
+		result1 map[string]any
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) UpdateBindingReturnsOnCall(i int, result1 map[string]any, result2 error) {
+	fake.updateBindingMutex.Lock()
+	defer fake.updateBindingMutex.Unlock()
+	fake.UpdateBindingStub = nil
+	if fake.updateBindingReturnsOnCall == nil {
+		fake.updateBindingReturnsOnCall = make(map[int]struct {
+			result1 map[string]any
+			result2 error
+		})
+	}
+	fake.updateBindingReturnsOnCall[i] = struct {
+		result1 map[string]any
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceProvider) UpgradeBindings(arg1 context.Context, arg2 *varcontext.VarContext, arg3 []*varcontext.VarContext) error {
 	var arg3Copy []*varcontext.VarContext
 	if arg3 != nil {
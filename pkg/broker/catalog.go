@@ -62,6 +62,10 @@ type ServicePlan struct {
 	ServiceProperties  map[string]any `json:"service_properties"`
 	ProvisionOverrides map[string]any `json:"provision_overrides,omitempty"`
 	BindOverrides      map[string]any `json:"bind_overrides,omitempty"`
+
+	// RetainOnDelete is set by operators on plans whose resources must not be destroyed when an
+	// instance or binding is deleted. The Terraform workspace is retained in the database instead.
+	RetainOnDelete bool `json:"retain_on_delete"`
 }
 
 // Validate implements validation.Validatable.
@@ -14,7 +14,11 @@
 
 package broker
 
-import "github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
+import (
+	"regexp"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
+)
 
 // ServiceExample holds example configurations for a service that _should_
 // work.
@@ -33,15 +37,79 @@ type ServiceExample struct {
 	// this example DOES NOT include a bind portion.
 	BindParams  map[string]any `json:"bind_params" yaml:"bind_params"`
 	BindCanFail bool           `json:"bind_can_fail,omitempty" yaml:"bind_can_fail,omitempty"`
+
+	// Update is an optional step that updates the service instance after it has been provisioned.
+	Update *ExampleUpdate `json:"update,omitempty" yaml:"update,omitempty"`
+
+	// ExpectedCredentials are assertions on the credentials returned by bind.
+	ExpectedCredentials []CredentialAssertion `json:"expected_credentials,omitempty" yaml:"expected_credentials,omitempty"`
+
+	// ExpectedProvisionError is a regular expression. When set, the example passes only if
+	// provision fails with a matching message, and there is no bind.
+	ExpectedProvisionError string `json:"expected_provision_error,omitempty" yaml:"expected_provision_error,omitempty"`
+
+	// ExpectedBindError is a regular expression. When set, the example passes only if
+	// bind fails with a matching message.
+	ExpectedBindError string `json:"expected_bind_error,omitempty" yaml:"expected_bind_error,omitempty"`
+
+	// Sequence is an optional list of steps that replaces provision, update and bind, for
+	// example to provision with one plan, update to another, and upgrade from the previous
+	// version of the brokerpak before binding. Unbind and deprovision always run at the end.
+	Sequence []ExampleStep `json:"sequence,omitempty" yaml:"sequence,omitempty"`
+}
+
+// ExampleUpdate is an update of the service instance in an example.
+type ExampleUpdate struct {
+	// PlanID is the plan to update to. If blank, the plan is not changed.
+	PlanID string `json:"plan_id,omitempty" yaml:"plan_id,omitempty"`
+	// Params is the JSON object that will be passed to update.
+	Params map[string]any `json:"params" yaml:"params"`
+	// ExpectedError is a regular expression. When set, the update must fail with a matching
+	// message, and the example carries on with the instance as it was.
+	ExpectedError string `json:"expected_error,omitempty" yaml:"expected_error,omitempty"`
 }
 
 var _ validation.Validatable = (*ServiceExample)(nil)
 
 // Validate implements validation.Validatable.
 func (action *ServiceExample) Validate() (errs *validation.FieldError) {
-	return errs.Also(
+	errs = errs.Also(
 		validation.ErrIfBlank(action.Name, "name"),
 		validation.ErrIfBlank(action.Description, "description"),
 		validation.ErrIfBlank(action.PlanID, "plan_id"),
+		errIfNotRegexp(action.ExpectedProvisionError, "expected_provision_error"),
+		errIfNotRegexp(action.ExpectedBindError, "expected_bind_error"),
 	)
+
+	if action.Update != nil {
+		errs = errs.Also(errIfNotRegexp(action.Update.ExpectedError, "expected_error").ViaField("update"))
+	}
+
+	for i, a := range action.ExpectedCredentials {
+		errs = errs.Also(a.Validate().ViaFieldIndex("expected_credentials", i))
+	}
+
+	if len(action.Sequence) > 0 {
+		errs = errs.Also(action.validateSequence())
+	}
+
+	return errs
+}
+
+// AssertionCount is the number of assertions that the example makes beyond a successful round trip.
+func (action *ServiceExample) AssertionCount() int {
+	count := len(action.ExpectedCredentials)
+	for _, s := range action.Steps() {
+		if s.ExpectedError != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func errIfNotRegexp(value, field string) *validation.FieldError {
+	if _, err := regexp.Compile(value); err != nil {
+		return validation.ErrInvalidValue(value, field)
+	}
+	return nil
 }
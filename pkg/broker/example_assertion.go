@@ -0,0 +1,162 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
+)
+
+// CredentialAssertion is an assertion on a value in the credentials returned by bind. The value
+// must exist, and must also equal Equals and match Matches when they are set.
+type CredentialAssertion struct {
+	// Path is a JSONPath expression selecting the value, made of child and index segments,
+	// for example `$.uri`, `$.hosts[0]` or `$['key with spaces']`.
+	Path string `json:"path" yaml:"path"`
+	// Equals is the value expected at the path.
+	Equals any `json:"equals,omitempty" yaml:"equals,omitempty"`
+	// Matches is a regular expression that the value, formatted as a string, must match.
+	Matches string `json:"matches,omitempty" yaml:"matches,omitempty"`
+}
+
+var _ validation.Validatable = (*CredentialAssertion)(nil)
+
+// Validate implements validation.Validatable.
+func (a *CredentialAssertion) Validate() (errs *validation.FieldError) {
+	errs = errs.Also(validation.ErrIfBlank(a.Path, "path"))
+	if a.Path != "" {
+		if _, err := parseJSONPath(a.Path); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(a.Path, "path"))
+		}
+	}
+	return errs.Also(errIfNotRegexp(a.Matches, "matches"))
+}
+
+// Check returns an error describing how the credentials fail the assertion, or nil if they pass
+func (a *CredentialAssertion) Check(credentials map[string]any) error {
+	segments, err := parseJSONPath(a.Path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.Path, err)
+	}
+
+	value, err := lookup(segments, credentials)
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.Path, err)
+	}
+
+	if a.Equals != nil && !jsonEqual(a.Equals, value) {
+		return fmt.Errorf("%s: expected %v but got %v", a.Path, a.Equals, value)
+	}
+
+	if a.Matches != "" {
+		re, err := regexp.Compile(a.Matches)
+		if err != nil {
+			return fmt.Errorf("%s: invalid regular expression %q: %w", a.Path, a.Matches, err)
+		}
+		if !re.MatchString(fmt.Sprint(value)) {
+			return fmt.Errorf("%s: expected %v to match %q", a.Path, value, a.Matches)
+		}
+	}
+
+	return nil
+}
+
+// jsonEqual compares values as they would be decoded from JSON, so that the integer 5432 in a YAML
+// service definition equals the float64 5432 in the credentials.
+func jsonEqual(expected, actual any) bool {
+	normalise := func(v any) any {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var result any
+		if err := json.Unmarshal(data, &result); err != nil {
+			return v
+		}
+		return result
+	}
+	return reflect.DeepEqual(normalise(expected), normalise(actual))
+}
+
+// jsonPathSegment is either a key of an object or an index of an array
+type jsonPathSegment struct {
+	key   string
+	index int
+	isKey bool
+}
+
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	var segments []jsonPathSegment
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("empty key in path")
+			}
+			segments = append(segments, jsonPathSegment{key: key, isKey: true})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated key in path")
+			}
+			segments = append(segments, jsonPathSegment{key: rest[2 : end+2], isKey: true})
+			rest = rest[end+4:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated index in path")
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q in path", rest[1:end])
+			}
+			segments = append(segments, jsonPathSegment{index: index})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in path", rest)
+		}
+	}
+
+	return segments, nil
+}
+
+func lookup(segments []jsonPathSegment, value any) (any, error) {
+	for _, s := range segments {
+		if s.isKey {
+			object, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("cannot read key %q of %T", s.key, value)
+			}
+			if value, ok = object[s.key]; !ok {
+				return nil, fmt.Errorf("key %q not found", s.key)
+			}
+			continue
+		}
+
+		array, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot read index %d of %T", s.index, value)
+		}
+		if s.index >= len(array) {
+			return nil, fmt.Errorf("index %d out of range", s.index)
+		}
+		value = array[s.index]
+	}
+	return value, nil
+}
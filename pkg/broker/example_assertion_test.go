@@ -0,0 +1,163 @@
+package broker
+
+import (
+	"testing"
+)
+
+func TestCredentialAssertion_Check(t *testing.T) {
+	credentials := map[string]any{
+		"uri":   "postgres://db.example.com:5432/db",
+		"port":  float64(5432),
+		"hosts": []any{"db.example.com", "replica.example.com"},
+		"tls":   map[string]any{"enabled": true, "ca cert": "-----BEGIN CERTIFICATE-----"},
+	}
+
+	cases := map[string]struct {
+		Assertion CredentialAssertion
+		Expected  string
+	}{
+		"key exists":              {Assertion: CredentialAssertion{Path: "$.uri"}},
+		"number equals":           {Assertion: CredentialAssertion{Path: "$.port", Equals: 5432}},
+		"nested key":              {Assertion: CredentialAssertion{Path: "$.tls.enabled", Equals: true}},
+		"quoted key":              {Assertion: CredentialAssertion{Path: "$.tls['ca cert']", Matches: "^-----BEGIN"}},
+		"index":                   {Assertion: CredentialAssertion{Path: "$.hosts[1]", Equals: "replica.example.com"}},
+		"whole array":             {Assertion: CredentialAssertion{Path: "$.hosts", Equals: []string{"db.example.com", "replica.example.com"}}},
+		"matches a number":        {Assertion: CredentialAssertion{Path: "$.port", Matches: `^\d+$`}},
+		"missing key":             {Assertion: CredentialAssertion{Path: "$.password"}, Expected: `$.password: key "password" not found`},
+		"not equal":               {Assertion: CredentialAssertion{Path: "$.port", Equals: 3306}, Expected: "$.port: expected 3306 but got 5432"},
+		"does not match":          {Assertion: CredentialAssertion{Path: "$.uri", Matches: "^mysql://"}, Expected: `$.uri: expected postgres://db.example.com:5432/db to match "^mysql://"`},
+		"index out of range":      {Assertion: CredentialAssertion{Path: "$.hosts[2]"}, Expected: "$.hosts[2]: index 2 out of range"},
+		"index of a non-array":    {Assertion: CredentialAssertion{Path: "$.uri[0]"}, Expected: "$.uri[0]: cannot read index 0 of string"},
+		"key of a non-object":     {Assertion: CredentialAssertion{Path: "$.port.value"}, Expected: `$.port.value: cannot read key "value" of float64`},
+		"path without root":       {Assertion: CredentialAssertion{Path: "uri"}, Expected: "uri: path must start with $"},
+		"path with an empty key":  {Assertion: CredentialAssertion{Path: "$..uri"}, Expected: "$..uri: empty key in path"},
+		"path with a wildcard":    {Assertion: CredentialAssertion{Path: "$.hosts[*]"}, Expected: `$.hosts[*]: invalid index "*" in path`},
+		"path with unclosed key":  {Assertion: CredentialAssertion{Path: "$['uri"}, Expected: "$['uri: unterminated key in path"},
+		"path with a bare letter": {Assertion: CredentialAssertion{Path: "$uri"}, Expected: `$uri: unexpected "uri" in path`},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			err := tc.Assertion.Check(credentials)
+			switch {
+			case tc.Expected == "" && err != nil:
+				t.Fatalf("Expected no error but got: %v", err)
+			case tc.Expected != "" && err == nil:
+				t.Fatalf("Expected error %q but got none", tc.Expected)
+			case tc.Expected != "" && err.Error() != tc.Expected:
+				t.Fatalf("Expected error %q but got %q", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func TestServiceExample_Validate(t *testing.T) {
+	valid := func() ServiceExample {
+		return ServiceExample{Name: "example", Description: "an example", PlanID: "plan"}
+	}
+
+	cases := map[string]struct {
+		Modify   func(e *ServiceExample)
+		Expected string
+	}{
+		"valid": {
+			Modify: func(e *ServiceExample) {},
+		},
+		"valid with assertions": {
+			Modify: func(e *ServiceExample) {
+				e.Update = &ExampleUpdate{PlanID: "other-plan", ExpectedError: "cannot .* plan"}
+				e.ExpectedBindError = "^bind failed$"
+				e.ExpectedCredentials = []CredentialAssertion{{Path: "$.uri", Matches: "^https://"}}
+			},
+		},
+		"bad expected provision error": {
+			Modify:   func(e *ServiceExample) { e.ExpectedProvisionError = "(" },
+			Expected: "invalid value: (: expected_provision_error",
+		},
+		"bad update expected error": {
+			Modify:   func(e *ServiceExample) { e.Update = &ExampleUpdate{ExpectedError: "["} },
+			Expected: "invalid value: [: update.expected_error",
+		},
+		"bad credential assertion": {
+			Modify: func(e *ServiceExample) {
+				e.ExpectedCredentials = []CredentialAssertion{{Path: "$.uri"}, {Path: "uri", Matches: "*"}}
+			},
+			Expected: "invalid value: *: expected_credentials[1].matches\ninvalid value: uri: expected_credentials[1].path",
+		},
+		"blank credential assertion path": {
+			Modify:   func(e *ServiceExample) { e.ExpectedCredentials = []CredentialAssertion{{}} },
+			Expected: "missing field(s): expected_credentials[0].path",
+		},
+		"valid sequence": {
+			Modify: func(e *ServiceExample) {
+				e.Sequence = []ExampleStep{
+					{Action: "provision", PlanID: "small-plan"},
+					{Action: "update", PlanID: "large-plan"},
+					{Action: "update", Params: map[string]any{"size": 20}},
+					{Action: "upgrade"},
+					{Action: "bind", Params: map[string]any{"role": "reader"}, CanFail: true},
+				}
+			},
+		},
+		"sequence combined with other steps": {
+			Modify: func(e *ServiceExample) {
+				e.Sequence = []ExampleStep{{Action: "provision"}}
+				e.Update = &ExampleUpdate{}
+				e.BindCanFail = true
+			},
+			Expected: "cannot be combined with a sequence, set them on the steps instead: bind_can_fail, update",
+		},
+		"sequence not starting with provision": {
+			Modify:   func(e *ServiceExample) { e.Sequence = []ExampleStep{{Action: "bind"}, {Action: "provision"}} },
+			Expected: "the first step must provision: sequence[0].action",
+		},
+		"sequence with repeated steps": {
+			Modify: func(e *ServiceExample) {
+				e.Sequence = []ExampleStep{{Action: "provision"}, {Action: "upgrade"}, {Action: "upgrade"}}
+			},
+			Expected: "only one upgrade step is allowed: sequence[2].action",
+		},
+		"sequence with an unknown action": {
+			Modify:   func(e *ServiceExample) { e.Sequence = []ExampleStep{{Action: "provision"}, {Action: "restart"}} },
+			Expected: "invalid value: restart: sequence[1].action",
+		},
+		"sequence with invalid step fields": {
+			Modify: func(e *ServiceExample) {
+				e.Sequence = []ExampleStep{
+					{Action: "provision", CanFail: true},
+					{Action: "upgrade", PlanID: "plan", Params: map[string]any{}, ExpectedError: "("},
+				}
+			},
+			Expected: "invalid value: (: sequence[1].expected_error\nmust not set the field(s): sequence[0].can_fail, sequence[1].params, sequence[1].plan_id",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			example := valid()
+			tc.Modify(&example)
+
+			err := example.Validate()
+			switch {
+			case tc.Expected == "" && err != nil:
+				t.Fatalf("Expected no error but got: %v", err)
+			case tc.Expected != "" && err == nil:
+				t.Fatalf("Expected error %q but got none", tc.Expected)
+			case tc.Expected != "" && err.Error() != tc.Expected:
+				t.Fatalf("Expected error %q but got %q", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func TestServiceExample_AssertionCount(t *testing.T) {
+	example := ServiceExample{
+		Update:              &ExampleUpdate{ExpectedError: "no"},
+		ExpectedBindError:   "no",
+		ExpectedCredentials: []CredentialAssertion{{Path: "$.a"}, {Path: "$.b"}},
+	}
+
+	if actual := example.AssertionCount(); actual != 4 {
+		t.Fatalf("Expected 4 assertions but got %d", actual)
+	}
+}
@@ -0,0 +1,125 @@
+package broker
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
+)
+
+// The actions of the steps in the sequence of an example
+const (
+	ExampleActionProvision = "provision"
+	ExampleActionUpdate    = "update"
+	ExampleActionUpgrade   = "upgrade"
+	ExampleActionBind      = "bind"
+)
+
+// ExampleStep is a step in the sequence of an example. Steps before an upgrade run against
+// the previous version of the brokerpak, and the upgrade and later steps run against the
+// current version.
+type ExampleStep struct {
+	// Action is one of provision, update, upgrade or bind.
+	Action string `json:"action" yaml:"action"`
+	// PlanID is the plan to provision or to update to. If blank, provision uses the plan
+	// of the example, and update does not change the plan.
+	PlanID string `json:"plan_id,omitempty" yaml:"plan_id,omitempty"`
+	// Params is the JSON object passed to provision, update or bind. If nil, provision and
+	// bind use the parameters of the example.
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+	// ExpectedError is a regular expression. When set, the step must fail with a matching
+	// message. The example ends when provision fails as expected, and carries on after other steps.
+	ExpectedError string `json:"expected_error,omitempty" yaml:"expected_error,omitempty"`
+	// CanFail treats a failed bind as a warning.
+	CanFail bool `json:"can_fail,omitempty" yaml:"can_fail,omitempty"`
+}
+
+var _ validation.Validatable = (*ExampleStep)(nil)
+
+// Validate implements validation.Validatable.
+func (s *ExampleStep) Validate() (errs *validation.FieldError) {
+	switch s.Action {
+	case ExampleActionProvision, ExampleActionUpdate:
+	case ExampleActionBind:
+		if s.PlanID != "" {
+			errs = errs.Also(validation.ErrDisallowedFields("plan_id"))
+		}
+	case ExampleActionUpgrade:
+		if s.PlanID != "" {
+			errs = errs.Also(validation.ErrDisallowedFields("plan_id"))
+		}
+		if s.Params != nil {
+			errs = errs.Also(validation.ErrDisallowedFields("params"))
+		}
+	default:
+		return validation.ErrInvalidValue(s.Action, "action")
+	}
+
+	if s.CanFail && s.Action != ExampleActionBind {
+		errs = errs.Also(validation.ErrDisallowedFields("can_fail"))
+	}
+
+	return errs.Also(errIfNotRegexp(s.ExpectedError, "expected_error"))
+}
+
+// Steps returns the sequence of the example. An example without a sequence provisions,
+// updates if it has an update, and then binds.
+func (action *ServiceExample) Steps() []ExampleStep {
+	if len(action.Sequence) > 0 {
+		return action.Sequence
+	}
+
+	steps := []ExampleStep{{Action: ExampleActionProvision, ExpectedError: action.ExpectedProvisionError}}
+	if u := action.Update; u != nil {
+		steps = append(steps, ExampleStep{Action: ExampleActionUpdate, PlanID: u.PlanID, Params: u.Params, ExpectedError: u.ExpectedError})
+	}
+	return append(steps, ExampleStep{Action: ExampleActionBind, ExpectedError: action.ExpectedBindError, CanFail: action.BindCanFail})
+}
+
+// UpgradesBrokerpak is true when the sequence of the example needs the previous version of the brokerpak
+func (action *ServiceExample) UpgradesBrokerpak() bool {
+	return slices.ContainsFunc(action.Sequence, func(s ExampleStep) bool { return s.Action == ExampleActionUpgrade })
+}
+
+func (action *ServiceExample) validateSequence() (errs *validation.FieldError) {
+	var combined []string
+	for field, set := range map[string]bool{
+		"update":                   action.Update != nil,
+		"expected_provision_error": action.ExpectedProvisionError != "",
+		"expected_bind_error":      action.ExpectedBindError != "",
+		"bind_can_fail":            action.BindCanFail,
+	} {
+		if set {
+			combined = append(combined, field)
+		}
+	}
+	if len(combined) > 0 {
+		slices.Sort(combined)
+		errs = errs.Also(&validation.FieldError{
+			Message: "cannot be combined with a sequence, set them on the steps instead",
+			Paths:   combined,
+		})
+	}
+
+	if action.Sequence[0].Action != ExampleActionProvision {
+		errs = errs.Also((&validation.FieldError{
+			Message: "the first step must provision",
+			Paths:   []string{"action"},
+		}).ViaFieldIndex("sequence", 0))
+	}
+
+	counts := make(map[string]int)
+	for i, s := range action.Sequence {
+		errs = errs.Also(s.Validate().ViaFieldIndex("sequence", i))
+
+		counts[s.Action]++
+		if s.Action != ExampleActionUpdate && counts[s.Action] > 1 {
+			errs = errs.Also((&validation.FieldError{
+				Message: fmt.Sprintf("only one %s step is allowed", s.Action),
+				Paths:   []string{"action"},
+			}).ViaFieldIndex("sequence", i))
+		}
+	}
+
+	return errs
+}
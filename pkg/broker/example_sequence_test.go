@@ -0,0 +1,66 @@
+package broker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceExample_Steps(t *testing.T) {
+	cases := map[string]struct {
+		Example  ServiceExample
+		Expected []ExampleStep
+	}{
+		"default": {
+			Example: ServiceExample{},
+			Expected: []ExampleStep{
+				{Action: ExampleActionProvision},
+				{Action: ExampleActionBind},
+			},
+		},
+		"with expected errors and an update": {
+			Example: ServiceExample{
+				ExpectedProvisionError: "provision",
+				Update:                 &ExampleUpdate{PlanID: "plan", Params: map[string]any{"size": 20}, ExpectedError: "update"},
+				ExpectedBindError:      "bind",
+				BindCanFail:            true,
+			},
+			Expected: []ExampleStep{
+				{Action: ExampleActionProvision, ExpectedError: "provision"},
+				{Action: ExampleActionUpdate, PlanID: "plan", Params: map[string]any{"size": 20}, ExpectedError: "update"},
+				{Action: ExampleActionBind, ExpectedError: "bind", CanFail: true},
+			},
+		},
+		"sequence": {
+			Example: ServiceExample{
+				Sequence: []ExampleStep{
+					{Action: ExampleActionProvision},
+					{Action: ExampleActionUpgrade},
+				},
+			},
+			Expected: []ExampleStep{
+				{Action: ExampleActionProvision},
+				{Action: ExampleActionUpgrade},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if actual := tc.Example.Steps(); !reflect.DeepEqual(actual, tc.Expected) {
+				t.Errorf("Expected: %v got: %v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestServiceExample_UpgradesBrokerpak(t *testing.T) {
+	upgrade := ServiceExample{Sequence: []ExampleStep{{Action: ExampleActionProvision}, {Action: ExampleActionUpgrade}}}
+	if !upgrade.UpgradesBrokerpak() {
+		t.Error("Expected an example with an upgrade step to upgrade the brokerpak")
+	}
+
+	update := ServiceExample{Update: &ExampleUpdate{}}
+	if update.UpgradesBrokerpak() {
+		t.Error("Expected an example without an upgrade step not to upgrade the brokerpak")
+	}
+}
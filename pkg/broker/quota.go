@@ -0,0 +1,89 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Quota limits the number of instances of a service that may be provisioned.
+// Empty fields match any value, so a quota with only a plan limits that plan across all
+// organizations and spaces, and a quota with only an organization limits all plans in it.
+type Quota struct {
+	PlanID           string `json:"plan_id,omitempty"`
+	PlanName         string `json:"plan_name,omitempty"`
+	OrganizationGUID string `json:"organization_guid,omitempty"`
+	SpaceGUID        string `json:"space_guid,omitempty"`
+	Limit            int    `json:"limit"`
+}
+
+// Matches returns true if an instance of the plan in the organization and space counts towards the quota
+func (q Quota) Matches(plan ServicePlan, organizationGUID, spaceGUID string) bool {
+	return (q.PlanID == "" || q.PlanID == plan.ID) &&
+		(q.PlanName == "" || q.PlanName == plan.Name) &&
+		(q.OrganizationGUID == "" || q.OrganizationGUID == organizationGUID) &&
+		(q.SpaceGUID == "" || q.SpaceGUID == spaceGUID)
+}
+
+// String describes the scope of the quota for use in error messages
+func (q Quota) String() string {
+	var scope []string
+	if q.PlanName != "" {
+		scope = append(scope, fmt.Sprintf("plan %q", q.PlanName))
+	}
+	if q.PlanID != "" {
+		scope = append(scope, fmt.Sprintf("plan ID %q", q.PlanID))
+	}
+	if q.OrganizationGUID != "" {
+		scope = append(scope, fmt.Sprintf("organization %q", q.OrganizationGUID))
+	}
+	if q.SpaceGUID != "" {
+		scope = append(scope, fmt.Sprintf("space %q", q.SpaceGUID))
+	}
+	if len(scope) == 0 {
+		scope = append(scope, "all plans")
+	}
+
+	return fmt.Sprintf("%s: limit %d", strings.Join(scope, ", "), q.Limit)
+}
+
+// QuotasProperty returns the Viper property name for the JSON list of
+// operator-provided quotas.
+func (svc *ServiceDefinition) QuotasProperty() string {
+	return fmt.Sprintf("service.%s.quotas", svc.Name)
+}
+
+// Quotas returns the operator-provided quotas, failing if they are not valid.
+func (svc *ServiceDefinition) Quotas() ([]Quota, error) {
+	key := svc.QuotasProperty()
+	settings := svc.settings()
+	if !settings.IsSet(key) {
+		return nil, nil
+	}
+
+	// Like other service config values, quotas are a JSON string when sourced from an
+	// environment variable, but may be a list when sourced from a config file
+	var data []byte
+	switch v := settings.Get(key).(type) {
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("failed marshaling config value %s: %w", key, err)
+		}
+	}
+
+	var quotas []Quota
+	if err := json.Unmarshal(data, &quotas); err != nil {
+		return nil, fmt.Errorf("failed unmarshaling config value %s: %w", key, err)
+	}
+
+	for i, q := range quotas {
+		if q.Limit < 0 {
+			return nil, fmt.Errorf("invalid config value %s: quota %d has a negative limit", key, i)
+		}
+	}
+
+	return quotas, nil
+}
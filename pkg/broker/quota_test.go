@@ -0,0 +1,86 @@
+package broker_test
+
+import (
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+var _ = Describe("Quota", func() {
+	plan := broker.ServicePlan{ServicePlan: domain.ServicePlan{ID: "plan-id", Name: "plan-name"}}
+
+	DescribeTable(
+		"Matches",
+		func(quota broker.Quota, expected bool) {
+			Expect(quota.Matches(plan, "org-guid", "space-guid")).To(Equal(expected))
+		},
+		Entry("empty quota matches everything", broker.Quota{}, true),
+		Entry("matching plan ID", broker.Quota{PlanID: "plan-id"}, true),
+		Entry("matching plan name", broker.Quota{PlanName: "plan-name"}, true),
+		Entry("different plan", broker.Quota{PlanName: "other-plan"}, false),
+		Entry("matching organization", broker.Quota{OrganizationGUID: "org-guid"}, true),
+		Entry("different organization", broker.Quota{OrganizationGUID: "other-org"}, false),
+		Entry("matching plan and space", broker.Quota{PlanID: "plan-id", SpaceGUID: "space-guid"}, true),
+		Entry("matching plan in different space", broker.Quota{PlanID: "plan-id", SpaceGUID: "other-space"}, false),
+	)
+
+	It("describes its scope", func() {
+		Expect(broker.Quota{PlanName: "small", OrganizationGUID: "org-guid", Limit: 2}.String()).To(Equal(`plan "small", organization "org-guid": limit 2`))
+		Expect(broker.Quota{Limit: 5}.String()).To(Equal("all plans: limit 5"))
+	})
+
+	Describe("ServiceDefinition.Quotas", func() {
+		service := broker.ServiceDefinition{Name: "fake-service"}
+
+		AfterEach(func() {
+			viper.Reset()
+		})
+
+		It("returns no quotas when not configured", func() {
+			Expect(service.Quotas()).To(BeEmpty())
+		})
+
+		It("reads quotas from a JSON string", func() {
+			viper.Set("service.fake-service.quotas", `[{"plan_name":"small","limit":3},{"space_guid":"space-guid","limit":1}]`)
+
+			Expect(service.Quotas()).To(Equal([]broker.Quota{
+				{PlanName: "small", Limit: 3},
+				{SpaceGUID: "space-guid", Limit: 1},
+			}))
+		})
+
+		It("reads quotas from a config file list", func() {
+			viper.Set("service.fake-service.quotas", []any{map[string]any{"organization_guid": "org-guid", "limit": 2}})
+
+			Expect(service.Quotas()).To(Equal([]broker.Quota{{OrganizationGUID: "org-guid", Limit: 2}}))
+		})
+
+		It("fails when the quotas are not valid JSON", func() {
+			viper.Set("service.fake-service.quotas", `not-json`)
+
+			_, err := service.Quotas()
+			Expect(err).To(MatchError(ContainSubstring("failed unmarshaling config value service.fake-service.quotas")))
+		})
+
+		It("fails when a limit is negative", func() {
+			viper.Set("service.fake-service.quotas", `[{"limit":-1}]`)
+
+			_, err := service.Quotas()
+			Expect(err).To(MatchError("invalid config value service.fake-service.quotas: quota 0 has a negative limit"))
+		})
+
+		It("reads quotas from the configuration that the service is bound to", func() {
+			viper.Set("service.fake-service.quotas", `[{"limit":1}]`)
+			settings := viper.New()
+			settings.Set("service.fake-service.quotas", `[{"limit":2}]`)
+			bound := broker.ServiceDefinition{Name: "fake-service"}
+			bound.UseConfig(settings)
+
+			Expect(bound.Quotas()).To(Equal([]broker.Quota{{Limit: 2}}))
+			Expect(service.Quotas()).To(Equal([]broker.Quota{{Limit: 1}}))
+		})
+	})
+})
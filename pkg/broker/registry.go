@@ -19,6 +19,7 @@ import (
 	"sort"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"github.com/hashicorp/go-version"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
 
@@ -52,23 +53,87 @@ func (brokerRegistry BrokerRegistry) Register(service *ServiceDefinition, mainte
 		return fmt.Errorf("tried to register multiple instances of: %q", name)
 	}
 
-	userPlans, err := service.UserDefinedPlans(maintenanceInfo)
+	if err := service.prepare(maintenanceInfo); err != nil {
+		return err
+	}
+
+	brokerRegistry[name] = service
+	return nil
+}
+
+// RegisterVersion registers a ServiceDefinition like Register, but a service with the same name and ID
+// may be registered from several versions of a brokerpak. The newest version is the one in the catalog,
+// and the others are kept in PreviousVersions for the service instances that are pinned to them.
+func (brokerRegistry BrokerRegistry) RegisterVersion(service *ServiceDefinition, maintenanceInfo *domain.MaintenanceInfo) error {
+	name := service.Name
+
+	current, ok := brokerRegistry[name]
+	if !ok {
+		return brokerRegistry.Register(service, maintenanceInfo)
+	}
+
+	if current.ID != service.ID {
+		return fmt.Errorf("tried to register multiple instances of: %q", name)
+	}
+	if _, ok := current.PreviousVersions[service.Version]; ok || current.Version == service.Version {
+		return fmt.Errorf("version %q of service %q is already registered", service.Version, name)
+	}
+
+	newer, err := isNewerVersion(service.Version, current.Version)
 	if err != nil {
-		return fmt.Errorf("error getting user defined plans: %q, %s", name, err)
+		return fmt.Errorf("error comparing versions of service %q: %w", name, err)
 	}
-	service.Plans = append(service.Plans, userPlans...)
-	if len(service.Plans) == 0 {
-		return fmt.Errorf("service %q has no plans defined; at least one plan must be specified in the service definition or via the environment variable %q or %q", service.Name, service.UserDefinedPlansVariable(), service.TileUserDefinedPlansVariable())
+
+	if err := service.prepare(maintenanceInfo); err != nil {
+		return err
 	}
 
-	if err := service.Validate(); err != nil {
-		return fmt.Errorf("error validating service %q, %s", name, err)
+	if !newer {
+		current.addPreviousVersion(service)
+		return nil
 	}
 
+	service.PreviousVersions = current.PreviousVersions
+	current.PreviousVersions = nil
+	service.addPreviousVersion(current)
 	brokerRegistry[name] = service
 	return nil
 }
 
+func (svc *ServiceDefinition) prepare(maintenanceInfo *domain.MaintenanceInfo) error {
+	userPlans, err := svc.UserDefinedPlans(maintenanceInfo)
+	if err != nil {
+		return fmt.Errorf("error getting user defined plans: %q, %s", svc.Name, err)
+	}
+	svc.Plans = append(svc.Plans, userPlans...)
+	if len(svc.Plans) == 0 {
+		return fmt.Errorf("service %q has no plans defined; at least one plan must be specified in the service definition or via the environment variable %q or %q", svc.Name, svc.UserDefinedPlansVariable(), svc.TileUserDefinedPlansVariable())
+	}
+
+	if err := svc.Validate(); err != nil {
+		return fmt.Errorf("error validating service %q, %s", svc.Name, err)
+	}
+
+	// Quotas are read on each provision, so an invalid configuration would otherwise only fail provisions
+	if _, err := svc.Quotas(); err != nil {
+		return fmt.Errorf("error getting quotas: %q, %s", svc.Name, err)
+	}
+
+	return nil
+}
+
+func isNewerVersion(candidate, current string) (bool, error) {
+	c, err := version.NewVersion(candidate)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %w", candidate, err)
+	}
+	v, err := version.NewVersion(current)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %w", current, err)
+	}
+	return c.GreaterThan(v), nil
+}
+
 func (brokerRegistry BrokerRegistry) Validate() (errs *validation.FieldError) {
 	services := brokerRegistry.GetAllServices()
 	serviceIDs := make(map[string]struct{})
@@ -131,6 +196,43 @@ func (brokerRegistry BrokerRegistry) GetAllServices() []*ServiceDefinition {
 	return out
 }
 
+// Brokerpaks returns the brokerpaks that the services were loaded from, including older versions
+// of brokerpaks, sorted by name and version.
+func (brokerRegistry BrokerRegistry) Brokerpaks() []Brokerpak {
+	seen := make(map[*Brokerpak]struct{})
+	var out []Brokerpak
+	add := func(svc *ServiceDefinition) {
+		if svc.Brokerpak == nil {
+			return
+		}
+		if _, ok := seen[svc.Brokerpak]; ok {
+			return
+		}
+		seen[svc.Brokerpak] = struct{}{}
+		out = append(out, *svc.Brokerpak)
+	}
+
+	for _, svc := range brokerRegistry {
+		add(svc)
+		for _, previous := range svc.PreviousVersions {
+			add(previous)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		newer, err := isNewerVersion(out[j].Version, out[i].Version)
+		if err != nil {
+			return out[i].Version < out[j].Version
+		}
+		return newer
+	})
+
+	return out
+}
+
 // GetServiceByID returns the service with the given ID, if it does not exist
 // or one of the services has a parse error then an error is returned.
 func (brokerRegistry BrokerRegistry) GetServiceByID(id string) (*ServiceDefinition, error) {
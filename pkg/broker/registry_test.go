@@ -79,6 +79,18 @@ var _ = Describe("Registry", func() {
 			})
 		})
 
+		Context("quotas", func() {
+			It("fails when the quotas are not valid", func() {
+				viper.Set("service.test-service.quotas", `[{"limit":-1}]`)
+
+				registry := BrokerRegistry{}
+
+				err := registry.Register(&serviceDef, nil)
+				Expect(err).To(MatchError(`error getting quotas: "test-service", invalid config value service.test-service.quotas: quota 0 has a negative limit`))
+				Expect(registry).To(BeEmpty())
+			})
+		})
+
 		Context("no plans defined", func() {
 			It("defines a default plan", func() {
 				registry := make(BrokerRegistry)
@@ -93,6 +105,112 @@ var _ = Describe("Registry", func() {
 		})
 	})
 
+	Describe("RegisterVersion", func() {
+		const serviceID = "b9e4332e-b42b-4680-bda5-ea1506797474"
+
+		serviceVersion := func(version string) *ServiceDefinition {
+			return &ServiceDefinition{
+				ID:      serviceID,
+				Name:    "test-service",
+				Version: version,
+				Plans: []ServicePlan{
+					{
+						ServicePlan: domain.ServicePlan{
+							ID:   "e1d11f65-da66-46ad-977c-6d56513baf43",
+							Name: "default",
+						},
+					},
+				},
+			}
+		}
+
+		It("keeps the newest version in the catalog and the others as previous versions", func() {
+			registry := BrokerRegistry{}
+
+			Expect(registry.RegisterVersion(serviceVersion("1.1.0"), nil)).To(Succeed())
+			Expect(registry.RegisterVersion(serviceVersion("1.10.0"), nil)).To(Succeed())
+			Expect(registry.RegisterVersion(serviceVersion("1.2.0"), nil)).To(Succeed())
+
+			current := registry["test-service"]
+			Expect(current.Version).To(Equal("1.10.0"))
+			Expect(current.PreviousVersions).To(SatisfyAll(HaveLen(2), HaveKey("1.1.0"), HaveKey("1.2.0")))
+			Expect(current.ForVersion("1.1.0").Version).To(Equal("1.1.0"))
+			Expect(current.ForVersion("1.10.0")).To(BeIdenticalTo(current))
+			Expect(registry.Validate()).To(BeNil())
+		})
+
+		It("uses the current definition for versions that are not loaded", func() {
+			registry := BrokerRegistry{}
+			Expect(registry.RegisterVersion(serviceVersion("1.0.0"), nil)).To(Succeed())
+
+			current := registry["test-service"]
+			Expect(current.ForVersion("")).To(BeIdenticalTo(current))
+			Expect(current.ForVersion("0.9.0")).To(BeIdenticalTo(current))
+		})
+
+		It("fails when the same version is registered twice", func() {
+			registry := BrokerRegistry{}
+			Expect(registry.RegisterVersion(serviceVersion("2.0.0"), nil)).To(Succeed())
+			Expect(registry.RegisterVersion(serviceVersion("1.0.0"), nil)).To(Succeed())
+
+			Expect(registry.RegisterVersion(serviceVersion("1.0.0"), nil)).To(MatchError(`version "1.0.0" of service "test-service" is already registered`))
+			Expect(registry.RegisterVersion(serviceVersion("2.0.0"), nil)).To(MatchError(`version "2.0.0" of service "test-service" is already registered`))
+		})
+
+		It("fails when a service with the same name has a different ID", func() {
+			registry := BrokerRegistry{}
+			Expect(registry.RegisterVersion(serviceVersion("1.0.0"), nil)).To(Succeed())
+
+			other := serviceVersion("2.0.0")
+			other.ID = "a3d7e3a0-3a54-4e0f-8b45-6a2b1c7fd0a2"
+			Expect(registry.RegisterVersion(other, nil)).To(MatchError(`tried to register multiple instances of: "test-service"`))
+		})
+
+		It("fails when a version cannot be compared", func() {
+			registry := BrokerRegistry{}
+			Expect(registry.RegisterVersion(serviceVersion("1.0.0"), nil)).To(Succeed())
+
+			Expect(registry.RegisterVersion(serviceVersion("latest"), nil)).To(MatchError(ContainSubstring(`error comparing versions of service "test-service": invalid version "latest"`)))
+		})
+	})
+
+	Describe("Brokerpaks", func() {
+		It("lists each brokerpak once, including older versions", func() {
+			pakV1 := &Brokerpak{Name: "fake-pak", Version: "1.2.0"}
+			pakV2 := &Brokerpak{Name: "fake-pak", Version: "1.10.0"}
+			other := &Brokerpak{Name: "another-pak", Version: "0.1.0"}
+
+			serviceFrom := func(name, id, planID string, pak *Brokerpak) *ServiceDefinition {
+				return &ServiceDefinition{
+					ID:        id,
+					Name:      name,
+					Version:   pak.Version,
+					Brokerpak: pak,
+					Plans: []ServicePlan{
+						{
+							ServicePlan: domain.ServicePlan{
+								ID:   planID,
+								Name: "default",
+							},
+						},
+					},
+				}
+			}
+
+			registry := BrokerRegistry{}
+			Expect(registry.RegisterVersion(serviceFrom("first", "2a3d3ab0-5e7c-4f5c-9b5e-0b7b8d0a1a01", "7f1c1e4a-3b0e-4c64-a1b8-6d0f5e2b9c01", pakV1), nil)).To(Succeed())
+			Expect(registry.RegisterVersion(serviceFrom("second", "2a3d3ab0-5e7c-4f5c-9b5e-0b7b8d0a1a02", "7f1c1e4a-3b0e-4c64-a1b8-6d0f5e2b9c02", pakV1), nil)).To(Succeed())
+			Expect(registry.RegisterVersion(serviceFrom("first", "2a3d3ab0-5e7c-4f5c-9b5e-0b7b8d0a1a01", "7f1c1e4a-3b0e-4c64-a1b8-6d0f5e2b9c01", pakV2), nil)).To(Succeed())
+			Expect(registry.RegisterVersion(serviceFrom("third", "2a3d3ab0-5e7c-4f5c-9b5e-0b7b8d0a1a03", "7f1c1e4a-3b0e-4c64-a1b8-6d0f5e2b9c03", other), nil)).To(Succeed())
+
+			unpacked := serviceFrom("unpacked", "2a3d3ab0-5e7c-4f5c-9b5e-0b7b8d0a1a04", "7f1c1e4a-3b0e-4c64-a1b8-6d0f5e2b9c04", other)
+			unpacked.Brokerpak = nil
+			Expect(registry.Register(unpacked, nil)).To(Succeed())
+
+			Expect(registry.Brokerpaks()).To(Equal([]Brokerpak{*other, *pakV1, *pakV2}))
+		})
+	})
+
 	Describe("Validate", func() {
 		It("should fail when same service ID is used in two different services", func() {
 			const duplicateID = "b9e4332e-b42b-4680-bda5-ea1506797474"
@@ -30,6 +30,7 @@ import (
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/toggles"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext/interpolation"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
 )
 
@@ -38,6 +39,9 @@ var enableCatalogSchemas = toggles.Features.Toggle("enable-catalog-schemas", fal
 // GlobalProvisionDefaults viper key for global provision defaults
 const GlobalProvisionDefaults = "provision.defaults"
 
+// LabelsVariable is the well-known variable that resource tags are merged into
+const LabelsVariable = "labels"
+
 // ServiceDefinition holds the necessary details to describe an OSB service and
 // provision it.
 type ServiceDefinition struct {
@@ -68,10 +72,49 @@ type ServiceDefinition struct {
 	ProviderBuilder func(plogger lager.Logger, store ServiceProviderStorage) ServiceProvider
 
 	GlobalLabels map[string]string
+
+	// ResourceTags are operator-configured tags whose values are templates evaluated
+	// for each provision and bind. See resourceTagVariables for the available variables.
+	ResourceTags map[string]string
+
+	// SBOM is the software bill of materials of the brokerpak the service was loaded from
+	SBOM json.RawMessage
+
+	// Version is the version of the brokerpak the service was loaded from
+	Version string
+
+	// Brokerpak describes the brokerpak the service was loaded from. It is shared by all the services
+	// loaded from the same brokerpak.
+	Brokerpak *Brokerpak
+
+	// config is where the operator-provided plans, defaults and quotas are read from.
+	// The global Viper is used when nil.
+	config *viper.Viper
+
+	// PreviousVersions holds the definitions of this service from older versions of the brokerpak, keyed by
+	// version. Service instances that were provisioned with an older version keep using its definition
+	// until they are upgraded.
+	PreviousVersions map[string]*ServiceDefinition
 }
 
 var _ validation.Validatable = (*ServiceDefinition)(nil)
 
+// Brokerpak describes a loaded brokerpak for troubleshooting
+type Brokerpak struct {
+	Name    string
+	Version string
+	// URI is where the brokerpak was loaded from, with any credentials redacted
+	URI          string
+	TofuVersions []string
+	// Providers are the Terraform providers in the brokerpak, as source address and version
+	Providers []string
+
+	// PluginDir is where the binaries of the brokerpak were extracted to, and TofuBinaries are
+	// the OpenTofu binaries in it
+	PluginDir    string
+	TofuBinaries []string
+}
+
 // Validate implements validation.Validatable.
 func (svc *ServiceDefinition) Validate() (errs *validation.FieldError) {
 	errs = errs.Also(
@@ -128,6 +171,22 @@ func (svc *ServiceDefinition) Validate() (errs *validation.FieldError) {
 	return errs
 }
 
+// ForVersion returns the definition of the service from the given brokerpak version.
+// When that version is not loaded, the current definition is returned.
+func (svc *ServiceDefinition) ForVersion(version string) *ServiceDefinition {
+	if previous, ok := svc.PreviousVersions[version]; ok {
+		return previous
+	}
+	return svc
+}
+
+func (svc *ServiceDefinition) addPreviousVersion(previous *ServiceDefinition) {
+	if svc.PreviousVersions == nil {
+		svc.PreviousVersions = make(map[string]*ServiceDefinition)
+	}
+	svc.PreviousVersions[previous.Version] = previous
+}
+
 // UserDefinedPlansProperty computes the Viper property name for the JSON list
 // of user-defined service plans.
 func (svc *ServiceDefinition) UserDefinedPlansProperty() string {
@@ -153,11 +212,24 @@ func (svc *ServiceDefinition) ProvisionDefaultOverrideProperty() string {
 // ProvisionDefaultOverrides returns the deserialized JSON object for the
 // operator-provided property overrides.
 func (svc *ServiceDefinition) ProvisionDefaultOverrides() (map[string]any, error) {
-	return unmarshalViperMap(svc.ProvisionDefaultOverrideProperty())
+	return unmarshalViperMap(svc.settings(), svc.ProvisionDefaultOverrideProperty())
 }
 
 func ProvisionGlobalDefaults() (map[string]any, error) {
-	return unmarshalViperMap(GlobalProvisionDefaults)
+	return unmarshalViperMap(viper.GetViper(), GlobalProvisionDefaults)
+}
+
+// UseConfig sets where the operator-provided plans, defaults and quotas are read from, so that
+// a reloaded configuration only takes effect along with the services loaded from it
+func (svc *ServiceDefinition) UseConfig(v *viper.Viper) {
+	svc.config = v
+}
+
+func (svc *ServiceDefinition) settings() *viper.Viper {
+	if svc.config == nil {
+		return viper.GetViper()
+	}
+	return svc.config
 }
 
 // Some config values were historically expected to be provided as a string.
@@ -165,10 +237,10 @@ func ProvisionGlobalDefaults() (map[string]any, error) {
 // be provided via a config file.
 // E.g. consider the value .service.x.plans which would need to be a string in the config,
 // file even though it would be more readable as a list of objects.
-func unmarshalViperList(key string) ([]map[string]any, error) {
+func unmarshalViperList(v *viper.Viper, key string) ([]map[string]any, error) {
 	vals := []map[string]any{}
-	if viper.IsSet(key) {
-		val := viper.Get(key)
+	if v.IsSet(key) {
+		val := v.Get(key)
 
 		switch v := val.(type) {
 		case string:
@@ -187,11 +259,11 @@ func unmarshalViperList(key string) ([]map[string]any, error) {
 // be provided via a config file.
 // E.g. consider the value .service.x.provision.defaults which would need to be a
 // string in the config file even though it would be more readable as a map object.
-func unmarshalViperMap(key string) (map[string]any, error) {
+func unmarshalViperMap(v *viper.Viper, key string) (map[string]any, error) {
 	vals := make(map[string]any)
-	if viper.IsSet(key) {
+	if v.IsSet(key) {
 
-		val := viper.Get(key)
+		val := v.Get(key)
 		switch v := val.(type) {
 		case string:
 			if err := json.Unmarshal([]byte(v), &vals); err != nil {
@@ -219,7 +291,7 @@ func (svc *ServiceDefinition) BindDefaultOverrideProperty() string {
 // BindDefaultOverrides returns the deserialized JSON object for the
 // operator-provided property overrides.
 func (svc *ServiceDefinition) BindDefaultOverrides() map[string]any {
-	return viper.GetStringMap(svc.BindDefaultOverrideProperty())
+	return svc.settings().GetStringMap(svc.BindDefaultOverrideProperty())
 }
 
 // TileUserDefinedPlansVariable returns the name of the user defined plans
@@ -259,21 +331,23 @@ func (svc *ServiceDefinition) CatalogEntry() *Service {
 
 	if enableCatalogSchemas.IsActive() {
 		for i := range sd.Plans {
-			sd.Plans[i].Schemas = svc.createSchemas()
+			sd.Plans[i].Schemas = svc.createSchemas(sd.Plans[i])
 		}
 	}
 
 	return sd
 }
 
-// createSchemas creates JSONSchemas compatible with the OSB spec for provision and bind.
-// It leaves the instance update schema empty to indicate updates are not supported.
-func (svc *ServiceDefinition) createSchemas() *domain.ServiceSchemas {
+// createSchemas creates JSONSchemas compatible with the OSB spec for provision, update and bind.
+func (svc *ServiceDefinition) createSchemas(plan ServicePlan) *domain.ServiceSchemas {
 	return &domain.ServiceSchemas{
 		Instance: domain.ServiceInstanceSchema{
 			Create: domain.Schema{
 				Parameters: CreateJSONSchema(svc.ProvisionInputVariables),
 			},
+			Update: domain.Schema{
+				Parameters: svc.UpdateJSONSchema(plan),
+			},
 		},
 		Binding: domain.ServiceBindingSchema{
 			Create: domain.Schema{
@@ -283,6 +357,25 @@ func (svc *ServiceDefinition) createSchemas() *domain.ServiceSchemas {
 	}
 }
 
+// UpdateJSONSchema creates a JSONSchema for the parameters of an update request on the plan.
+// Fields that prohibit update, or that are fixed by the plan's properties or provision
+// overrides, are excluded. No fields are required as updates only contain changed values.
+func (svc *ServiceDefinition) UpdateJSONSchema(plan ServicePlan) map[string]any {
+	var updatable []BrokerVariable
+	for _, v := range svc.ProvisionInputVariables {
+		_, isPlanProperty := plan.ServiceProperties[v.FieldName]
+		_, isPlanOverride := plan.ProvisionOverrides[v.FieldName]
+		if v.ProhibitUpdate || isPlanProperty || isPlanOverride {
+			continue
+		}
+
+		v.Required = false
+		updatable = append(updatable, v)
+	}
+
+	return CreateJSONSchema(updatable)
+}
+
 // GetPlanByID finds a plan in this service by its UUID.
 func (svc *ServiceDefinition) GetPlanByID(planID string) (*ServicePlan, error) {
 	catalogEntry := svc.CatalogEntry()
@@ -309,7 +402,7 @@ func (svc *ServiceDefinition) UserDefinedPlans(maintenanceInfo *domain.Maintenan
 	// Unmarshal the plans from the viper configuration which is just a JSON list
 	// of plans
 
-	userPlan, err := unmarshalViperList(svc.UserDefinedPlansProperty())
+	userPlan, err := unmarshalViperList(svc.settings(), svc.UserDefinedPlansProperty())
 	if err != nil {
 		return []ServicePlan{}, err
 	}
@@ -427,9 +520,9 @@ func (svc *ServiceDefinition) bindDefaults() []varcontext.DefaultVariable {
 // For example, to create a default database name based on a user-provided instance name.
 // Therefore, they get executed conditionally if a user-provided variable does not exist.
 // Computed variables get executed either unconditionally or conditionally for greater flexibility.
-func (svc *ServiceDefinition) variables(constants map[string]any, userProvidedParameters map[string]any, plan ServicePlan) (*varcontext.VarContext, error) {
+func (svc *ServiceDefinition) variables(constants map[string]any, userProvidedParameters map[string]any, plan ServicePlan, labels map[string]string) (*varcontext.VarContext, error) {
 
-	globalDefaults, err := ProvisionGlobalDefaults()
+	globalDefaults, err := unmarshalViperMap(svc.settings(), GlobalProvisionDefaults)
 	if err != nil {
 		return nil, err
 	}
@@ -437,7 +530,7 @@ func (svc *ServiceDefinition) variables(constants map[string]any, userProvidedPa
 	if err != nil {
 		return nil, err
 	}
-	builder := varcontext.Builder().SetEvalConstants(constants).
+	builder := varcontext.Builder().SetEvalConstants(constants).SetConfig(svc.settings()).
 		MergeMap(globalDefaults).                            // Viper: provision.defaults
 		MergeMap(provisionDefaultOverrides).                 // Viper: service.<service>.provision.defaults
 		MergeMap(userProvidedParameters).                    // OSBAPI request parameters
@@ -446,41 +539,60 @@ func (svc *ServiceDefinition) variables(constants map[string]any, userProvidedPa
 		MergeMap(plan.GetServiceProperties()).               // "properties" from the Plan
 		MergeDefaultWithEval(svc.ProvisionComputedVariables) // "computed_variables" from the Service definition
 
+	if labels != nil {
+		builder.MergeLabels(LabelsVariable, labels) // resource tags configured by the operator
+	}
+
 	return buildAndValidate(builder, svc.ProvisionInputVariables)
 }
 
 func (svc *ServiceDefinition) ProvisionVariables(instanceID string, details paramparser.ProvisionDetails, plan ServicePlan, originatingIdentity map[string]any) (*varcontext.VarContext, error) {
+	defaultLabels := svc.combineLabels(map[string]string{
+		"pcf-organization-guid": utils.InvalidLabelChars.ReplaceAllString(details.OrganizationGUID, "_"),
+		"pcf-space-guid":        utils.InvalidLabelChars.ReplaceAllString(details.SpaceGUID, "_"),
+		"pcf-instance-id":       utils.InvalidLabelChars.ReplaceAllString(instanceID, "_"),
+	})
+
 	// The namespaces of these values roughly align with the OSB spec.
 	constants := map[string]any{
-		"request.plan_id":     details.PlanID,
-		"request.service_id":  details.ServiceID,
-		"request.instance_id": instanceID,
-		"request.default_labels": svc.combineLabels(map[string]string{
-			"pcf-organization-guid": utils.InvalidLabelChars.ReplaceAllString(details.OrganizationGUID, "_"),
-			"pcf-space-guid":        utils.InvalidLabelChars.ReplaceAllString(details.SpaceGUID, "_"),
-			"pcf-instance-id":       utils.InvalidLabelChars.ReplaceAllString(instanceID, "_"),
-		}),
-		"request.context": details.RequestContext,
+		"request.plan_id":                           details.PlanID,
+		"request.service_id":                        details.ServiceID,
+		"request.instance_id":                       instanceID,
+		"request.default_labels":                    defaultLabels,
+		"request.context":                           details.RequestContext,
 		"request.x_broker_api_originating_identity": originatingIdentity,
 	}
 
-	return svc.variables(constants, details.RequestParams, plan)
+	labels, err := svc.resourceTagLabels(defaultLabels, svc.resourceTagVariables(details.OrganizationGUID, details.SpaceGUID, instanceID, "", plan))
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.variables(constants, details.RequestParams, plan, labels)
 }
 
 func (svc *ServiceDefinition) UpdateVariables(instanceID string, details paramparser.UpdateDetails, mergedUserProvidedParameters map[string]any, plan ServicePlan, originatingIdentity map[string]any) (*varcontext.VarContext, error) {
+	defaultLabels := svc.combineLabels(map[string]string{
+		"pcf-organization-guid": utils.InvalidLabelChars.ReplaceAllString(details.PreviousOrgID, "_"),
+		"pcf-space-guid":        utils.InvalidLabelChars.ReplaceAllString(details.PreviousSpaceID, "_"),
+		"pcf-instance-id":       utils.InvalidLabelChars.ReplaceAllString(instanceID, "_"),
+	})
+
 	constants := map[string]any{
-		"request.plan_id":     details.PlanID,
-		"request.service_id":  details.ServiceID,
-		"request.instance_id": instanceID,
-		"request.default_labels": svc.combineLabels(map[string]string{
-			"pcf-organization-guid": utils.InvalidLabelChars.ReplaceAllString(details.PreviousOrgID, "_"),
-			"pcf-space-guid":        utils.InvalidLabelChars.ReplaceAllString(details.PreviousSpaceID, "_"),
-			"pcf-instance-id":       utils.InvalidLabelChars.ReplaceAllString(instanceID, "_"),
-		}),
-		"request.context": details.RequestContext,
+		"request.plan_id":                           details.PlanID,
+		"request.service_id":                        details.ServiceID,
+		"request.instance_id":                       instanceID,
+		"request.default_labels":                    defaultLabels,
+		"request.context":                           details.RequestContext,
 		"request.x_broker_api_originating_identity": originatingIdentity,
 	}
-	return svc.variables(constants, mergedUserProvidedParameters, plan)
+
+	labels, err := svc.resourceTagLabels(defaultLabels, svc.resourceTagVariables(details.PreviousOrgID, details.PreviousSpaceID, instanceID, "", plan))
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.variables(constants, mergedUserProvidedParameters, plan, labels)
 }
 
 // BindVariables gets the variable resolution context for a bind request.
@@ -518,12 +630,26 @@ func (svc *ServiceDefinition) BindVariables(instance storage.ServiceInstanceDeta
 
 	builder := varcontext.Builder().
 		SetEvalConstants(constants).
+		SetConfig(svc.settings()).
 		MergeMap(svc.BindDefaultOverrides()).
 		MergeMap(details.RequestParams).
 		MergeMap(plan.BindOverrides).
 		MergeDefaultWithEval(svc.bindDefaults()).
 		MergeDefaultWithEval(svc.BindComputedVariables)
 
+	defaultLabels := svc.combineLabels(map[string]string{
+		"pcf-organization-guid": utils.InvalidLabelChars.ReplaceAllString(instance.OrganizationGUID, "_"),
+		"pcf-space-guid":        utils.InvalidLabelChars.ReplaceAllString(instance.SpaceGUID, "_"),
+		"pcf-instance-id":       utils.InvalidLabelChars.ReplaceAllString(instance.GUID, "_"),
+	})
+	labels, err := svc.resourceTagLabels(defaultLabels, svc.resourceTagVariables(instance.OrganizationGUID, instance.SpaceGUID, instance.GUID, bindingID, *plan))
+	if err != nil {
+		return nil, err
+	}
+	if labels != nil {
+		builder.MergeLabels(LabelsVariable, labels)
+	}
+
 	return buildAndValidate(builder, svc.BindInputVariables)
 }
 
@@ -540,6 +666,39 @@ func (svc *ServiceDefinition) combineLabels(defaultLabels map[string]string) map
 	return ll
 }
 
+// resourceTagVariables returns the variables that may be used in resource tag templates
+func (svc *ServiceDefinition) resourceTagVariables(organizationGUID, spaceGUID, instanceID, bindingID string, plan ServicePlan) map[string]any {
+	return map[string]any{
+		"organization_guid": organizationGUID,
+		"space_guid":        spaceGUID,
+		"instance_id":       instanceID,
+		"binding_id":        bindingID,
+		"plan_id":           plan.ID,
+		"plan_name":         plan.Name,
+		"service_id":        svc.ID,
+		"service_name":      svc.Name,
+	}
+}
+
+// resourceTagLabels evaluates the resource tag templates and combines them with the default labels.
+// It returns nil when no resource tags are configured, so that the "labels" variable is left alone.
+func (svc *ServiceDefinition) resourceTagLabels(defaultLabels map[string]string, vars map[string]any) (map[string]string, error) {
+	if len(svc.ResourceTags) == 0 {
+		return nil, nil
+	}
+
+	labels := maps.Clone(defaultLabels)
+	for key, tmpl := range svc.ResourceTags {
+		value, err := interpolation.EvalWithConfig(tmpl, vars, svc.settings())
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating resource tag %q: %w", key, err)
+		}
+		labels[key] = fmt.Sprint(value)
+	}
+
+	return labels, nil
+}
+
 // buildAndValidate builds the varcontext and if it's valid validates the
 // resulting context against the JSONSchema defined by the BrokerVariables
 // exactly one of VarContext and error will be nil upon return.
@@ -556,6 +715,15 @@ func buildAndValidate(builder *varcontext.ContextBuilder, vars []BrokerVariable)
 	return vc, nil
 }
 
+// ValidateUpdateParameters validates the parameters of an update request against the update JSONSchema for the plan
+func (svc *ServiceDefinition) ValidateUpdateParameters(params map[string]any, plan ServicePlan) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	return ValidateVariablesAgainstSchema(params, svc.UpdateJSONSchema(plan))
+}
+
 func (svc *ServiceDefinition) AllowedUpdate(params map[string]any) bool {
 	for _, param := range svc.ProvisionInputVariables {
 		if param.ProhibitUpdate {
@@ -566,3 +734,14 @@ func (svc *ServiceDefinition) AllowedUpdate(params map[string]any) bool {
 	}
 	return true
 }
+
+func (svc *ServiceDefinition) AllowedBindingUpdate(params map[string]any) bool {
+	for _, param := range svc.BindInputVariables {
+		if param.ProhibitUpdate {
+			if _, ok := params[param.FieldName]; ok {
+				return false
+			}
+		}
+	}
+	return true
+}
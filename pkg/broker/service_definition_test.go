@@ -325,6 +325,30 @@ var _ = Describe("ServiceDefinition", func() {
 		)
 	})
 
+	Describe("AllowedBindingUpdate", func() {
+		serviceDefinition := broker.ServiceDefinition{
+			BindInputVariables: []broker.BrokerVariable{
+				{
+					FieldName:      "prohibited",
+					ProhibitUpdate: true,
+				},
+				{
+					FieldName: "allowed",
+				},
+			},
+		}
+
+		DescribeTable("returns the correct result",
+			func(params map[string]any, expected bool) {
+				actual := serviceDefinition.AllowedBindingUpdate(params)
+				Expect(actual).To(Equal(expected))
+			},
+			Entry("allowed", map[string]any{"allowed": "some_val"}, true),
+			Entry("prohibited", map[string]any{"prohibited": "some_val"}, false),
+			Entry("empty", nil, true),
+		)
+	})
+
 	Describe("UserDefinedPlans", func() {
 
 		const (
@@ -525,6 +549,21 @@ var _ = Describe("ServiceDefinition", func() {
 
 		})
 
+		When("a plan retains its resources on delete", func() {
+			BeforeEach(func() {
+				viper.Set("service.fake-service.plans", fmt.Sprintf(`[{"name":"%s","id":"%s","retain_on_delete":true,"additional_property":"%s"}]`,
+					fakePlanName, fakePlanID, fakePlanProperty))
+			})
+
+			It("sets RetainOnDelete and does not pass it as a plan property", func() {
+				plans, err := service.UserDefinedPlans(maintenanceInfo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plans).To(HaveLen(1))
+				Expect(plans[0].RetainOnDelete).To(BeTrue())
+				Expect(plans[0].ServiceProperties).To(Equal(map[string]any{"additional_property": fakePlanProperty}))
+			})
+		})
+
 		When("plan validation fails", func() {
 			BeforeEach(func() {
 				fakeServicePlanMissingID = fmt.Sprintf(`[{"name":"%s","description":"%s", "additional_property":"%s"}]`,
@@ -534,7 +573,7 @@ var _ = Describe("ServiceDefinition", func() {
 
 			It("returns an error", func() {
 				_, err := service.UserDefinedPlans(maintenanceInfo)
-				Expect(err).To(MatchError("fake-service custom plan {ServicePlan:{ID: Name:fakePlanName Description:fakePlanDescription Free:<nil> Bindable:<nil> Metadata:<nil> Schemas:<nil> PlanUpdatable:<nil> MaximumPollingDuration:<nil> MaintenanceInfo:<nil>} ServiceProperties:map[additional_property:fakePlanProperty] ProvisionOverrides:map[] BindOverrides:map[]} is missing an id"))
+				Expect(err).To(MatchError("fake-service custom plan {ServicePlan:{ID: Name:fakePlanName Description:fakePlanDescription Free:<nil> Bindable:<nil> Metadata:<nil> Schemas:<nil> PlanUpdatable:<nil> MaximumPollingDuration:<nil> MaintenanceInfo:<nil>} ServiceProperties:map[additional_property:fakePlanProperty] ProvisionOverrides:map[] BindOverrides:map[] RetainOnDelete:false} is missing an id"))
 			})
 		})
 
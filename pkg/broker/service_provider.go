@@ -34,6 +34,10 @@ type ServiceProvider interface {
 	// needs to operate.
 	Provision(ctx context.Context, provisionContext *varcontext.VarContext) error
 
+	// Adopt brings existing resources, given as resource addresses mapped to IaaS resource IDs, under the
+	// management of a new instance, as if the instance had provisioned them.
+	Adopt(ctx context.Context, provisionContext *varcontext.VarContext, resources map[string]string) error
+
 	// Update makes necessary updates to resources so they match new desired configuration
 	Update(ctx context.Context, updateContext *varcontext.VarContext) error
 
@@ -48,9 +52,20 @@ type ServiceProvider interface {
 	// It stores information necessary to access the service _and_ delete the binding in the returned map.
 	Bind(ctx context.Context, vc *varcontext.VarContext) (map[string]any, error)
 
+	// BindAsync starts provisioning the resources for a binding without waiting for completion.
+	// Progress is reported by PollBinding, and the result is available from GetBindingOutputs.
+	BindAsync(ctx context.Context, vc *varcontext.VarContext) error
+
+	// UpdateBinding re-applies the resources created with Bind using new configuration.
+	// It returns the updated information necessary to access the service.
+	UpdateBinding(ctx context.Context, vc *varcontext.VarContext) (map[string]any, error)
+
 	// Unbind deprovisions the resources created with Bind.
 	Unbind(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) error
 
+	// UnbindAsync starts deprovisioning the resources created with Bind without waiting for completion.
+	UnbindAsync(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) error
+
 	// Deprovision deprovisions the service.
 	// If the deprovision is asynchronous (results in a long-running job), then operationId is returned.
 	// If no error and no operationId are returned, then the deprovision is expected to have been completed successfully.
@@ -58,8 +73,12 @@ type ServiceProvider interface {
 
 	PollInstance(ctx context.Context, instanceGUID string) (bool, string, string, error)
 
+	PollBinding(ctx context.Context, instanceGUID, bindingID string) (bool, string, string, error)
+
 	GetTerraformOutputs(ctx context.Context, instanceGUID string) (storage.JSONObject, error)
 
+	GetBindingOutputs(ctx context.Context, instanceGUID, bindingID string) (storage.JSONObject, error)
+
 	DeleteInstanceData(ctx context.Context, instanceGUID string) error
 
 	DeleteBindingData(ctx context.Context, instanceGUID, bindingID string) error
@@ -15,20 +15,29 @@
 package brokerpak
 
 import (
+	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/google/uuid"
+	"github.com/spf13/viper"
 
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/compat"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/diff"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/manifest"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/mirror"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/packer"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/platform"
 	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/reader"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/signature"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/client"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/generator"
@@ -176,6 +185,95 @@ func finfo(pack string, out io.Writer) error {
 	return nil
 }
 
+// SBOM writes out the software bill of materials of the brokerpak.
+func SBOM(pack string) error {
+	brokerPak, err := reader.OpenBrokerPak(pack)
+	if err != nil {
+		return err
+	}
+	defer brokerPak.Close()
+
+	data, err := brokerPak.SBOM()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+// Diff writes out the differences between two brokerpaks, either human-readable or as JSON.
+func Diff(oldPack, newPack string, jsonOutput bool) error {
+	return fdiff(oldPack, newPack, jsonOutput, os.Stdout)
+}
+
+func fdiff(oldPack, newPack string, jsonOutput bool, out io.Writer) error {
+	oldPak, err := readForComparison(oldPack)
+	if err != nil {
+		return err
+	}
+	newPak, err := readForComparison(newPack)
+	if err != nil {
+		return err
+	}
+
+	report := diff.Compare(oldPak, newPak)
+	if jsonOutput {
+		return json.NewEncoder(out).Encode(report)
+	}
+	return report.WriteText(out)
+}
+
+// CheckCompat checks that the brokerpak can replace the previous brokerpak without breaking existing service
+// instances. It writes out the findings and returns an error if there are breaking changes.
+func CheckCompat(pack, previousPack string) error {
+	return fcheckCompat(pack, previousPack, os.Stdout)
+}
+
+func fcheckCompat(pack, previousPack string, out io.Writer) error {
+	previous, err := readForComparison(previousPack)
+	if err != nil {
+		return err
+	}
+	current, err := readForComparison(pack)
+	if err != nil {
+		return err
+	}
+
+	result := compat.Check(previous, current)
+	for _, e := range result.Errors {
+		fmt.Fprintf(out, "ERROR: %s\n", e)
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(out, "WARNING: %s\n", w)
+	}
+
+	if !result.Compatible() {
+		return fmt.Errorf("%q is not compatible with %q: %d breaking change(s)", pack, previousPack, len(result.Errors))
+	}
+	return nil
+}
+
+func readForComparison(pack string) (diff.Brokerpak, error) {
+	brokerPak, err := reader.OpenBrokerPak(pack)
+	if err != nil {
+		return diff.Brokerpak{}, err
+	}
+	defer brokerPak.Close()
+
+	mf, err := brokerPak.Manifest()
+	if err != nil {
+		return diff.Brokerpak{}, fmt.Errorf("error reading manifest of %q: %w", pack, err)
+	}
+
+	services, err := brokerPak.Services()
+	if err != nil {
+		return diff.Brokerpak{}, fmt.Errorf("error reading services of %q: %w", pack, err)
+	}
+
+	return diff.Brokerpak{Manifest: mf, Services: services}, nil
+}
+
 func cmdTabWriter(out io.Writer) *tabwriter.Writer {
 	// args: output, minwidth, tabwidth, padding, padchar, flags
 	return tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.StripEscape)
@@ -192,10 +290,121 @@ func Validate(pack string) error {
 	return brokerPak.Validate()
 }
 
+// Sign signs the brokerpak with the PEM encoded ed25519 private key in the given file.
+func Sign(pack, keyPath string) error {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+
+	key, err := signature.ParsePrivateKey(data)
+	if err != nil {
+		return err
+	}
+
+	return signature.Sign(pack, key)
+}
+
+// Verify checks the signature of the brokerpak against the PEM encoded ed25519 public keys in the given file.
+func Verify(pack, keysPath string) error {
+	data, err := os.ReadFile(keysPath)
+	if err != nil {
+		return err
+	}
+
+	keys, err := signature.ParsePublicKeys(data)
+	if err != nil {
+		return err
+	}
+
+	brokerPak, err := reader.OpenBrokerPak(pack)
+	if err != nil {
+		return err
+	}
+	defer brokerPak.Close()
+
+	return brokerPak.VerifySignature(keys)
+}
+
+// Mirror writes the providers in the brokerpak to the directory as an OpenTofu provider mirror,
+// along with checksums and an OpenTofu CLI configuration that uses the mirror.
+func Mirror(pack, directory string) error {
+	return fmirror(pack, directory, os.Stdout)
+}
+
+func fmirror(pack, directory string, out io.Writer) error {
+	brokerPak, err := reader.OpenBrokerPak(pack)
+	if err != nil {
+		return err
+	}
+	defer brokerPak.Close()
+
+	result, err := mirror.Write(brokerPak, directory)
+	if err != nil {
+		return fmt.Errorf("error writing provider mirror: %w", err)
+	}
+
+	fmt.Fprintln(out, "Providers:")
+	w := cmdTabWriter(out)
+	fmt.Fprintln(w, "ADDRESS\tVERSION\tPLATFORMS")
+	for _, p := range result.Providers {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Address, p.Version, strings.Join(p.Platforms, ", "))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nChecksums written to %s\n", filepath.Join(result.Directory, mirror.ChecksumsFileName))
+	fmt.Fprintf(out, "OpenTofu CLI configuration written to %s:\n\n", filepath.Join(result.Directory, mirror.CLIConfigFileName))
+	fmt.Fprint(out, mirror.CLIConfig(result))
+	return nil
+}
+
+// Push publishes the brokerpak to an OCI registry, logging in with the configured registry credentials,
+// and prints the reference with the digest that pins the published brokerpak.
+func Push(pack, reference string) error {
+	return fpush(pack, reference, os.Stdout)
+}
+
+func fpush(pack, reference string, out io.Writer) error {
+	ref, err := oci.ParseReference(reference)
+	if err != nil {
+		return err
+	}
+
+	brokerPak, err := reader.OpenBrokerPak(pack)
+	if err != nil {
+		return err
+	}
+	defer brokerPak.Close()
+
+	if _, err := brokerPak.Manifest(); err != nil {
+		return fmt.Errorf("%q is not a valid brokerpak: %w", pack, err)
+	}
+
+	credentials, err := RegistryCredentialsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	digest, err := oci.NewClient(credentials).Push(context.Background(), ref, pack)
+	if err != nil {
+		return err
+	}
+
+	ref.Digest = digest
+	fmt.Fprintf(out, "Pushed %s\n", ref)
+	return nil
+}
+
 // RegisterAll fetches all brokerpaks from the settings file and registers them
 // with the given registry.
 func RegisterAll(registry broker.BrokerRegistry) error {
-	pakConfig, err := NewServerConfigFromEnv()
+	return RegisterAllFromConfig(viper.GetViper(), registry)
+}
+
+// RegisterAllFromConfig fetches all brokerpaks in the configuration of a Viper instance
+// and registers them with the given registry
+func RegisterAllFromConfig(v *viper.Viper, registry broker.BrokerRegistry) error {
+	pakConfig, err := NewServerConfig(v)
 	if err != nil {
 		return err
 	}
@@ -220,7 +429,7 @@ func RunExamples(pack string) {
 		log.Fatalf("Error executing examples (getting): %v", err)
 	}
 
-	client.RunExamplesForService(allExamples, apiClient, "", "", 1)
+	client.RunExamplesForService(allExamples, apiClient, nil, "", "", 1, client.ReportPaths{})
 }
 
 // Docs generates the markdown usage docs for the given pack and writes them to stdout.
@@ -16,6 +16,10 @@ package brokerpak
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"os"
 	"path/filepath"
 	"strings"
@@ -111,6 +115,67 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestSignAndVerify(t *testing.T) {
+	pk, err := fakeBrokerpak()
+	defer os.Remove(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	trustedPrivate, trustedPublic := writeKeyPair(t, dir, "trusted")
+	_, untrustedPublic := writeKeyPair(t, dir, "untrusted")
+
+	if err := Verify(pk, trustedPublic); err == nil || err.Error() != "brokerpak is not signed" {
+		t.Fatalf("expected unsigned brokerpak to fail verification, got %v", err)
+	}
+
+	if err := Sign(pk, trustedPrivate); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Verify(pk, trustedPublic); err != nil {
+		t.Fatalf("expected signed brokerpak to verify, got %v", err)
+	}
+
+	if err := Verify(pk, untrustedPublic); err == nil || err.Error() != "signature does not match any trusted public key" {
+		t.Fatalf("expected verification with untrusted key to fail, got %v", err)
+	}
+
+	if err := Validate(pk); err != nil {
+		t.Fatalf("expected signed brokerpak to be valid, got %v", err)
+	}
+}
+
+// writeKeyPair writes PEM encoded ed25519 keys in the same format as openssl
+func writeKeyPair(t *testing.T, dir, name string) (privatePath, publicPath string) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePath = filepath.Join(dir, name+".pem")
+	publicPath = filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return privatePath, publicPath
+}
+
 func TestFinfo(t *testing.T) {
 	pk, err := fakeBrokerpak()
 	defer os.Remove(pk)
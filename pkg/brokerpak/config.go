@@ -15,6 +15,7 @@
 package brokerpak
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -24,6 +25,8 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/signature"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/toggles"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
 	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
@@ -32,18 +35,25 @@ import (
 const (
 	// BuiltinPakLocation is the file-system location to load brokerpaks from to
 	// make them look builtin.
-	BuiltinPakLocation      = "./"
-	brokerpakSourcesKey     = "brokerpak.sources"
-	brokerpakConfigKey      = "brokerpak.config"
-	brokerpakBuiltinPathKey = "brokerpak.builtin.path"
+	BuiltinPakLocation              = "./"
+	brokerpakSourcesKey             = "brokerpak.sources"
+	brokerpakConfigKey              = "brokerpak.config"
+	brokerpakBuiltinPathKey         = "brokerpak.builtin.path"
+	brokerpakTrustedKeysKey         = "brokerpak.trusted_public_keys"
+	brokerpakRegistryCredentialsKey = "brokerpak.registry_credentials"
 )
 
 var loadBuiltinToggle = toggles.Features.Toggle("enable-builtin-brokerpaks", true, `Load brokerpaks that are built-in to the software.`)
 
 func init() {
-	viper.SetDefault(brokerpakSourcesKey, "{}")
-	viper.SetDefault(brokerpakConfigKey, "{}")
-	viper.SetDefault(brokerpakBuiltinPathKey, BuiltinPakLocation)
+	SetConfigDefaults(viper.GetViper())
+}
+
+// SetConfigDefaults sets the defaults of the brokerpak configuration on a Viper instance
+func SetConfigDefaults(v *viper.Viper) {
+	v.SetDefault(brokerpakSourcesKey, "{}")
+	v.SetDefault(brokerpakConfigKey, "{}")
+	v.SetDefault(brokerpakBuiltinPathKey, BuiltinPakLocation)
 }
 
 // BrokerpakSourceConfig represents a single configuration of a brokerpak.
@@ -101,6 +111,16 @@ type ServerConfig struct {
 
 	// Brokerpaks holds list of brokerpaks to load.
 	Brokerpaks map[string]BrokerpakSourceConfig
+
+	// TrustedPublicKeys holds the keys that brokerpaks must be signed with.
+	// When empty, brokerpak signatures are not checked.
+	TrustedPublicKeys []ed25519.PublicKey
+
+	// RegistryCredentials holds the credentials for OCI registries that brokerpaks are pulled from.
+	RegistryCredentials oci.Credentials
+
+	// settings is the configuration that the services are bound to. The global Viper is used when nil.
+	settings *viper.Viper
 }
 
 var _ validation.Validatable = (*ServerConfig)(nil)
@@ -142,19 +162,71 @@ func (cfg *ServerConfig) GetGlobalLabels() (map[string]string, error) {
 	return labels, nil
 }
 
+// GetResourceTags returns the operator-configured resource tags. Tag values are
+// templates which are evaluated for each provision and bind, and the results
+// are merged into the "labels" variable of every Terraform deployment.
+func (cfg *ServerConfig) GetResourceTags() (map[string]string, error) {
+	if cfg.Config == "" {
+		return map[string]string{}, nil
+	}
+
+	type tag struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+
+	var globalConfiguration struct {
+		ResourceTags []tag `json:"resource_tags"`
+	}
+	if err := json.Unmarshal([]byte(cfg.Config), &globalConfiguration); err != nil {
+		return nil, fmt.Errorf("invalid global configuration for brokerpak %w", err)
+	}
+
+	tags := map[string]string{}
+	for _, value := range globalConfiguration.ResourceTags {
+		if value.Key == "" {
+			return nil, fmt.Errorf("invalid global configuration for brokerpak: resource tags must have a key")
+		}
+		tags[value.Key] = value.Value
+	}
+
+	return tags, nil
+}
+
 // NewServerConfigFromEnv loads the global Brokerpak config from Viper.
 func NewServerConfigFromEnv() (*ServerConfig, error) {
+	return NewServerConfig(viper.GetViper())
+}
+
+// NewServerConfig loads the Brokerpak config from a Viper instance. The services registered
+// with the config read their plans, defaults and quotas from the same instance.
+func NewServerConfig(v *viper.Viper) (*ServerConfig, error) {
 	paks := map[string]BrokerpakSourceConfig{}
-	sources := viper.GetString(brokerpakSourcesKey)
+	sources := v.GetString(brokerpakSourcesKey)
 	if err := json.Unmarshal([]byte(sources), &paks); err != nil {
 		return nil, fmt.Errorf("couldn't deserialize brokerpak source config: %v", err)
 	}
 
 	cfg := ServerConfig{
-		Config:     viper.GetString(brokerpakConfigKey),
+		Config:     v.GetString(brokerpakConfigKey),
 		Brokerpaks: paks,
+		settings:   v,
+	}
+
+	if trustedKeys := v.GetString(brokerpakTrustedKeysKey); trustedKeys != "" {
+		keys, err := signature.ParsePublicKeys([]byte(trustedKeys))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse brokerpak trusted public keys: %v", err)
+		}
+		cfg.TrustedPublicKeys = keys
 	}
 
+	credentials, err := registryCredentials(v)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RegistryCredentials = credentials
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("brokerpak config was invalid: %v", err)
 	}
@@ -162,7 +234,7 @@ func NewServerConfigFromEnv() (*ServerConfig, error) {
 	// Builtin paks fail validation because they reference the local filesystem
 	// but do work.
 	if loadBuiltinToggle.IsActive() {
-		paks, err := ListBrokerpaks(viper.GetString(brokerpakBuiltinPathKey))
+		paks, err := ListBrokerpaks(v.GetString(brokerpakBuiltinPathKey))
 		if err != nil {
 			return nil, fmt.Errorf("couldn't load builtin brokerpaks: %v", err)
 		}
@@ -178,6 +250,19 @@ func NewServerConfigFromEnv() (*ServerConfig, error) {
 	return &cfg, nil
 }
 
+// RegistryCredentialsFromEnv loads the credentials for OCI registries from Viper.
+func RegistryCredentialsFromEnv() (oci.Credentials, error) {
+	return registryCredentials(viper.GetViper())
+}
+
+func registryCredentials(v *viper.Viper) (oci.Credentials, error) {
+	credentials, err := oci.ParseCredentials(v.GetString(brokerpakRegistryCredentialsKey))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse brokerpak registry credentials: %v", err)
+	}
+	return credentials, nil
+}
+
 // ListBrokerpaks gets all brokerpaks in a given directory.
 func ListBrokerpaks(directory string) ([]string, error) {
 	var paks []string
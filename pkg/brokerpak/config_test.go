@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/brokerpak/oci"
 )
 
 func TestNewBrokerpakSourceConfigFromPath(t *testing.T) {
@@ -234,6 +236,70 @@ func TestNewServerConfigFromEnv_Valid_Configuration(t *testing.T) {
 	}
 }
 
+func TestNewServerConfigFromEnv_RegistryCredentials(t *testing.T) {
+	viper.Set("brokerpak.sources", `{}`)
+	viper.Set("brokerpak.config", `{}`)
+	viper.Set("brokerpak.registry_credentials", `{"registry.example.com":{"username":"user","password":"secret"}}`)
+	defer viper.Reset()
+
+	cfg, err := NewServerConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error, got %s", err)
+	}
+
+	expected := oci.Credentials{"registry.example.com": {Username: "user", Password: "secret"}}
+	if !reflect.DeepEqual(cfg.RegistryCredentials, expected) {
+		t.Errorf("unexpected registry credentials, got %v, want %v", cfg.RegistryCredentials, expected)
+	}
+
+	viper.Set("brokerpak.registry_credentials", `{"registry.example.com":"secret"}`)
+	if _, err := NewServerConfigFromEnv(); err == nil {
+		t.Error("expected error for invalid registry credentials, got nil")
+	}
+}
+
+func TestServerConfig_GetResourceTags(t *testing.T) {
+	cases := map[string]struct {
+		Config   string
+		Expected map[string]string
+		Err      string
+	}{
+		"empty configuration": {
+			Config:   ``,
+			Expected: map[string]string{},
+		},
+		"no resource tags": {
+			Config:   `{"global_labels": [{"key": "key1", "value": "value1"}]}`,
+			Expected: map[string]string{},
+		},
+		"resource tags": {
+			Config:   `{"resource_tags": [{"key": "cost-center", "value": "cc-${organization_guid}"}, {"key": "plan", "value": "${plan_name}"}]}`,
+			Expected: map[string]string{"cost-center": "cc-${organization_guid}", "plan": "${plan_name}"},
+		},
+		"missing key": {
+			Config: `{"resource_tags": [{"value": "value"}]}`,
+			Err:    "invalid global configuration for brokerpak: resource tags must have a key",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			cfg := ServerConfig{Config: tc.Config}
+			tags, err := cfg.GetResourceTags()
+			switch {
+			case tc.Err != "" && (err == nil || err.Error() != tc.Err):
+				t.Fatalf("expected error %q, got %v", tc.Err, err)
+			case tc.Err == "" && err != nil:
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tc.Err == "" && !reflect.DeepEqual(tags, tc.Expected) {
+				t.Errorf("expected tags %v, got %v", tc.Expected, tags)
+			}
+		})
+	}
+}
+
 func TestListBrokerpaks(t *testing.T) {
 	t.Parallel()
 
@@ -17,7 +17,9 @@ package brokerpak
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
 	"code.cloudfoundry.org/lager/v3"
@@ -56,12 +58,18 @@ func (r *Registrar) Register(registry broker.BrokerRegistry) error {
 			"prefix":            pak.ServicePrefix,
 		})
 
-		brokerPak, err := reader.DownloadAndOpenBrokerpak(pak.BrokerpakURI)
+		brokerPak, err := reader.DownloadAndOpenBrokerpak(pak.BrokerpakURI, r.config.RegistryCredentials)
 		if err != nil {
 			return fmt.Errorf("couldn't open brokerpak: %q: %v", pak.BrokerpakURI, err)
 		}
 		defer brokerPak.Close()
 
+		if len(r.config.TrustedPublicKeys) > 0 {
+			if err := brokerPak.VerifySignature(r.config.TrustedPublicKeys); err != nil {
+				return fmt.Errorf("refusing to register brokerpak %q: %v", pak.BrokerpakURI, err)
+			}
+		}
+
 		tfBinariesContext, err := r.extractTfBinaries(brokerPak, vc)
 		if err != nil {
 			return err
@@ -86,8 +94,27 @@ func (r *Registrar) Register(registry broker.BrokerRegistry) error {
 			return err
 		}
 
+		sbom, err := brokerPak.SBOM()
+		if err != nil {
+			return fmt.Errorf("error reading brokerpak SBOM: %w", err)
+		}
+
+		mf, err := brokerPak.Manifest()
+		if err != nil {
+			return fmt.Errorf("error reading brokerpak manifest: %w", err)
+		}
+
+		register := registry.Register
+		if featureflags.Enabled(featureflags.MultiVersionBrokerpaksEnabled) {
+			register = registry.RegisterVersion
+		}
+
+		pakInfo := describeBrokerpak(mf, pak, tfBinariesContext)
 		for _, defn := range defns {
-			err := registry.Register(defn, maintenanceInfo)
+			defn.SBOM = sbom
+			defn.Version = mf.Version
+			defn.Brokerpak = pakInfo
+			err := register(defn, maintenanceInfo)
 			if err != nil {
 				return err
 			}
@@ -98,13 +125,8 @@ func (r *Registrar) Register(registry broker.BrokerRegistry) error {
 			return errs
 		}
 
-		mf, err := brokerPak.Manifest()
-		if err != nil {
-			return fmt.Errorf("error reading brokerpak manifest: %w", err)
-		}
-
 		for env, config := range mf.EnvConfigMapping {
-			viper.BindEnv(config, env)
+			r.settings().BindEnv(config, env)
 		}
 
 		registerLogger.Info("registration-successful", lager.Data{"version": mf.Version})
@@ -118,6 +140,10 @@ func (r *Registrar) toDefinitions(services []tf.TfServiceDefinitionV1, config Br
 	if err != nil {
 		return nil, err
 	}
+	resourceTags, err := r.config.GetResourceTags()
+	if err != nil {
+		return nil, err
+	}
 
 	toIgnore := utils.NewStringSet(config.ExcludedServicesSlice()...)
 	for _, svc := range services {
@@ -126,6 +152,7 @@ func (r *Registrar) toDefinitions(services []tf.TfServiceDefinitionV1, config Br
 		}
 
 		svc.Name = config.ServicePrefix + svc.Name
+		svc.UseConfig(r.settings())
 
 		bs, err := svc.ToService(tfBinariesContext, maintenanceInfo)
 		if err != nil {
@@ -133,6 +160,7 @@ func (r *Registrar) toDefinitions(services []tf.TfServiceDefinitionV1, config Br
 		}
 
 		bs.GlobalLabels = globalLabels
+		bs.ResourceTags = resourceTags
 
 		out = append(out, bs)
 	}
@@ -170,6 +198,14 @@ func (r *Registrar) extractTfBinaries(brokerPak *reader.BrokerPakReader, vc *var
 	}, nil
 }
 
+// settings is the configuration that the registered services are bound to
+func (r *Registrar) settings() *viper.Viper {
+	if r.config.settings == nil {
+		return viper.GetViper()
+	}
+	return r.config.settings
+}
+
 func (r *Registrar) walk(callback registrarWalkFunc) error {
 	for name, pak := range r.config.Brokerpaks {
 		vc, err := varcontext.Builder().
@@ -195,6 +231,67 @@ func NewRegistrar(sc *ServerConfig) *Registrar {
 	return &Registrar{config: sc}
 }
 
+// describeBrokerpak summarises the brokerpak for troubleshooting. The URI comes from configuration
+// and may contain credentials, so they are redacted.
+func describeBrokerpak(mf *manifest.Manifest, pak BrokerpakSourceConfig, tfBinariesContext executor.TFBinariesContext) *broker.Brokerpak {
+	info := broker.Brokerpak{
+		Name:      mf.Name,
+		Version:   mf.Version,
+		URI:       redactURI(pak.BrokerpakURI),
+		PluginDir: tfBinariesContext.Dir,
+	}
+
+	for _, v := range mf.TerraformVersions {
+		info.TofuVersions = append(info.TofuVersions, v.Version.String())
+		info.TofuBinaries = append(info.TofuBinaries, executor.BinaryPath(tfBinariesContext.Dir, v.Version))
+	}
+
+	for _, p := range mf.TerraformProviders {
+		source := p.Provider.String()
+		if source == "" {
+			source = p.Name
+		}
+		if p.Version != nil {
+			source = fmt.Sprintf("%s@%s", source, p.Version)
+		}
+		info.Providers = append(info.Providers, source)
+	}
+
+	return &info
+}
+
+// redactURI replaces any user info and query parameter values in a brokerpak URI, as they
+// may hold credentials for the location the brokerpak is fetched from
+func redactURI(uri string) string {
+	const redacted = "REDACTED"
+
+	getter, location, forced := strings.Cut(uri, "::")
+	if !forced {
+		location = uri
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return redacted
+	}
+
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, redacted)
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	if forced {
+		return getter + "::" + u.String()
+	}
+	return u.String()
+}
+
 // resolveParameters resolves environment variables from the given global and
 // brokerpak specific.
 func resolveParameters(params []manifest.Parameter, vc *varcontext.VarContext) map[string]string {
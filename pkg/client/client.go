@@ -39,9 +39,14 @@ const (
 
 // NewClientFromEnv creates a new client from the client configuration properties.
 func NewClientFromEnv() (*Client, error) {
+	return NewClientFromEnvWithPort(viper.GetInt("api.port"))
+}
+
+// NewClientFromEnvWithPort creates a client like NewClientFromEnv, connected to another port
+// on the same host, such as a broker serving the previous version of a brokerpak.
+func NewClientFromEnvWithPort(port int) (*Client, error) {
 	user := viper.GetString("api.user")
 	pass := viper.GetString("api.password")
-	port := viper.GetInt("api.port")
 
 	viper.SetDefault("api.hostname", "localhost")
 	host := viper.GetString("api.hostname")
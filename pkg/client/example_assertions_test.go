@@ -0,0 +1,249 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+// fakeBroker responds to each OSB operation with a fixed status code and body
+type fakeBroker struct {
+	responses map[string]fakeResponse
+	requests  []string
+	// version is the maintenance info version of the plans in the catalog
+	version string
+}
+
+type fakeResponse struct {
+	status int
+	body   string
+}
+
+func (f *fakeBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v2/catalog" {
+		_, _ = fmt.Fprintf(w, `{"services":[{"id":"fake-service-id","plans":[{"id":"fake-plan-id","maintenance_info":{"version":%q}}]}]}`, f.version)
+		return
+	}
+
+	operation := map[string]string{
+		http.MethodPut + "instance":    "provision",
+		http.MethodPatch + "instance":  "update",
+		http.MethodDelete + "instance": "deprovision",
+		http.MethodPut + "binding":     "bind",
+		http.MethodDelete + "binding":  "unbind",
+	}[r.Method+resourceOf(r.URL.Path)]
+
+	var body struct {
+		MaintenanceInfo *struct {
+			Version string `json:"version"`
+		} `json:"maintenance_info"`
+		PreviousValues struct {
+			MaintenanceInfo *struct {
+				Version string `json:"version"`
+			} `json:"maintenance_info"`
+		} `json:"previous_values"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	switch {
+	case body.MaintenanceInfo != nil && body.PreviousValues.MaintenanceInfo != nil:
+		f.requests = append(f.requests, fmt.Sprintf("upgrade(%s->%s)", body.PreviousValues.MaintenanceInfo.Version, body.MaintenanceInfo.Version))
+	default:
+		f.requests = append(f.requests, operation)
+	}
+
+	// a response for the nth request of an operation, such as "update#2", takes precedence
+	resp, ok := f.responses[fmt.Sprintf("%s#%d", operation, f.count(operation))]
+	if !ok {
+		resp, ok = f.responses[operation]
+	}
+	if !ok {
+		resp = map[string]fakeResponse{
+			"provision":   {status: http.StatusCreated, body: `{}`},
+			"update":      {status: http.StatusOK, body: `{}`},
+			"deprovision": {status: http.StatusOK, body: `{}`},
+			"bind":        {status: http.StatusCreated, body: `{"credentials":{"hosts":["db.example.com"],"port":5432,"uri":"postgres://db.example.com:5432/db"}}`},
+			"unbind":      {status: http.StatusOK, body: `{}`},
+		}[operation]
+	}
+
+	w.WriteHeader(resp.status)
+	_, _ = w.Write([]byte(resp.body))
+}
+
+func (f *fakeBroker) count(operation string) (n int) {
+	for _, r := range f.requests {
+		if r == operation {
+			n++
+		}
+	}
+	return n
+}
+
+func resourceOf(path string) string {
+	if strings.Contains(path, "/service_bindings/") {
+		return "binding"
+	}
+	return "instance"
+}
+
+func newFakeBrokerClient(t *testing.T, responses map[string]fakeResponse) (*Client, *fakeBroker) {
+	t.Helper()
+
+	fake := &fakeBroker{responses: responses}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New("user", "pass", u.Hostname(), port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, fake
+}
+
+func TestRunExampleAssertions(t *testing.T) {
+	cases := map[string]struct {
+		Example          broker.ServiceExample
+		Responses        map[string]fakeResponse
+		ExpectedFailures []string
+		ExpectedRequests string
+		ExpectedPhases   string
+	}{
+		"passing credential assertions": {
+			Example: broker.ServiceExample{
+				ExpectedCredentials: []broker.CredentialAssertion{
+					{Path: "$.port", Equals: 5432},
+					{Path: "$.hosts[0]", Matches: `\.example\.com$`},
+					{Path: "$.uri"},
+				},
+			},
+			ExpectedRequests: "provision bind unbind deprovision unbind deprovision",
+			ExpectedPhases:   "provision bind unbind deprovision",
+		},
+		"failing credential assertions": {
+			Example: broker.ServiceExample{
+				ExpectedCredentials: []broker.CredentialAssertion{
+					{Path: "$.port", Equals: 3306},
+					{Path: "$.username"},
+				},
+			},
+			ExpectedFailures: []string{
+				"$.port: expected 3306 but got 5432",
+				`$.username: key "username" not found`,
+			},
+			ExpectedRequests: "provision bind unbind deprovision unbind deprovision",
+			ExpectedPhases:   "provision bind unbind deprovision",
+		},
+		"expected provision error": {
+			Example: broker.ServiceExample{
+				ExpectedProvisionError: "invalid size",
+			},
+			Responses: map[string]fakeResponse{
+				"provision": {status: http.StatusBadRequest, body: `{"description":"invalid size: must be at least 10"}`},
+			},
+			ExpectedRequests: "provision unbind deprovision",
+			ExpectedPhases:   "provision(failed) bind(skipped: provision failed) unbind(skipped: provision failed) deprovision(skipped: provision failed)",
+		},
+		"provision succeeds unexpectedly": {
+			Example: broker.ServiceExample{
+				ExpectedProvisionError: "invalid size",
+			},
+			ExpectedFailures: []string{`expected provision to fail with an error matching "invalid size", but it succeeded`},
+			ExpectedRequests: "provision unbind deprovision",
+			ExpectedPhases:   "provision bind(skipped: example stopped) unbind(skipped: example stopped) deprovision(skipped: example stopped)",
+		},
+		"update": {
+			Example: broker.ServiceExample{
+				Update: &broker.ExampleUpdate{Params: map[string]any{"size": 20}},
+			},
+			ExpectedRequests: "provision update bind unbind deprovision unbind deprovision",
+			ExpectedPhases:   "provision update bind unbind deprovision",
+		},
+		"expected update error with a different message": {
+			Example: broker.ServiceExample{
+				Update: &broker.ExampleUpdate{Params: map[string]any{"size": 5}, ExpectedError: "cannot shrink"},
+			},
+			Responses: map[string]fakeResponse{
+				"update": {status: http.StatusUnprocessableEntity, body: `{"description":"plan not updatable"}`},
+			},
+			ExpectedFailures: []string{`expected update to fail with an error matching "cannot shrink", but got: unexpected response code 422: plan not updatable`},
+			ExpectedRequests: "provision update unbind deprovision",
+			ExpectedPhases:   "provision update(failed) bind(skipped: update failed) unbind(skipped: update failed) deprovision(skipped: update failed)",
+		},
+		"expected bind error": {
+			Example: broker.ServiceExample{
+				ExpectedBindError: "read-only",
+				ExpectedCredentials: []broker.CredentialAssertion{
+					{Path: "$.port", Equals: 3306},
+				},
+			},
+			Responses: map[string]fakeResponse{
+				"bind": {status: http.StatusBadRequest, body: `{"description":"instance is read-only"}`},
+			},
+			ExpectedRequests: "provision bind deprovision unbind deprovision",
+			ExpectedPhases:   "provision bind(failed) unbind(skipped: bind failed as expected) deprovision",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			apiClient, fake := newFakeBrokerClient(t, tc.Responses)
+
+			example := CompleteServiceExample{ServiceExample: tc.Example, ServiceName: "fake-service"}
+			example.Name = tn
+			example.BindParams = map[string]any{}
+			example.ExpectedOutput = map[string]any{"type": "object"}
+
+			phases, err := runExample(apiClient, nil, "000", example)
+
+			var assertionErr *AssertionError
+			switch {
+			case len(tc.ExpectedFailures) == 0 && err != nil:
+				t.Fatalf("Expected no error but got: %v", err)
+			case len(tc.ExpectedFailures) > 0 && !errors.As(err, &assertionErr):
+				t.Fatalf("Expected assertion failures but got: %v", err)
+			case len(tc.ExpectedFailures) > 0 && fmt.Sprint(assertionErr.Failures) != fmt.Sprint(tc.ExpectedFailures):
+				t.Fatalf("Expected failures: %q got: %q", tc.ExpectedFailures, assertionErr.Failures)
+			}
+
+			if actual := strings.Join(fake.requests, " "); actual != tc.ExpectedRequests {
+				t.Errorf("Expected requests: %q got: %q", tc.ExpectedRequests, actual)
+			}
+
+			if actual := summarisePhases(phases); actual != tc.ExpectedPhases {
+				t.Errorf("Expected phases: %q got: %q", tc.ExpectedPhases, actual)
+			}
+		})
+	}
+}
+
+func summarisePhases(phases []PhaseResult) string {
+	var summary []string
+	for _, p := range phases {
+		switch {
+		case p.SkipReason != "":
+			summary = append(summary, fmt.Sprintf("%s(skipped: %s)", p.Name, p.SkipReason))
+		case p.Error != "":
+			summary = append(summary, fmt.Sprintf("%s(failed)", p.Name))
+		default:
+			summary = append(summary, p.Name)
+		}
+	}
+	return strings.Join(summary, " ")
+}
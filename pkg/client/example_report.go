@@ -0,0 +1,220 @@
+package client
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReportPaths are the files that reports of an example run are written to. Blank paths are not written.
+type ReportPaths struct {
+	JUnit string
+	JSON  string
+}
+
+// ExampleResult is the outcome of running a single example
+type ExampleResult struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	ServiceName string        `json:"service_name"`
+	PlanID      string        `json:"plan_id"`
+	Passed      bool          `json:"passed"`
+	Duration    time.Duration `json:"duration_ns"`
+	Error       string        `json:"error,omitempty"`
+	SkipReason  string        `json:"skip_reason,omitempty"`
+	Phases      []PhaseResult `json:"phases"`
+}
+
+// PhaseResult is the outcome of a phase of an example, such as provision or bind. The error
+// is the error returned by the broker, which is not a failure when the example expects it.
+type PhaseResult struct {
+	Name       string        `json:"name"`
+	Duration   time.Duration `json:"duration_ns"`
+	Error      string        `json:"error,omitempty"`
+	SkipReason string        `json:"skip_reason,omitempty"`
+}
+
+// phaseRecorder times the phases of an example, and records the phases that did not run
+type phaseRecorder struct {
+	planned []string
+	phases  []PhaseResult
+}
+
+func newPhaseRecorder(planned ...string) *phaseRecorder {
+	return &phaseRecorder{planned: planned}
+}
+
+func (r *phaseRecorder) run(name string, phase func() error) error {
+	start := time.Now()
+	err := phase()
+
+	result := PhaseResult{Name: name, Duration: time.Since(start)}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.phases = append(r.phases, result)
+	return err
+}
+
+func (r *phaseRecorder) skip(name, reason string) {
+	r.phases = append(r.phases, PhaseResult{Name: name, SkipReason: reason})
+}
+
+// results returns a result for each planned phase. The phases run in the planned order, so the
+// recorded phases are matched to the planned ones by position. Phases that did not run are skipped
+// because of the last phase that failed.
+func (r *phaseRecorder) results() []PhaseResult {
+	reason := "example stopped"
+	for _, p := range r.phases {
+		if p.Error != "" {
+			reason = fmt.Sprintf("%s failed", p.Name)
+		}
+	}
+
+	results := make([]PhaseResult, 0, len(r.planned))
+	for i, name := range r.planned {
+		if i < len(r.phases) {
+			results = append(results, r.phases[i])
+			continue
+		}
+		results = append(results, PhaseResult{Name: name, SkipReason: reason})
+	}
+	return results
+}
+
+func writeReports(paths ReportPaths, results []ExampleResult) error {
+	if paths.JSON != "" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(paths.JSON, data, 0o644); err != nil {
+			return fmt.Errorf("error writing JSON report: %w", err)
+		}
+	}
+
+	if paths.JUnit != "" {
+		data, err := xml.MarshalIndent(newJUnitReport(results), "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(paths.JUnit, append([]byte(xml.Header), data...), 0o644); err != nil {
+			return fmt.Errorf("error writing JUnit report: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	Skipped    *junitSkipped   `xml:"skipped,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// newJUnitReport has a test suite for each service, and a test case for each example
+// with the plan and phase durations as properties
+func newJUnitReport(results []ExampleResult) junitTestSuites {
+	report := junitTestSuites{}
+	suites := make(map[string]int)
+	var total time.Duration
+	var suiteTotals []time.Duration
+
+	for _, r := range results {
+		i, ok := suites[r.ServiceName]
+		if !ok {
+			i = len(report.Suites)
+			suites[r.ServiceName] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: r.ServiceName})
+			suiteTotals = append(suiteTotals, 0)
+		}
+
+		testCase := junitTestCase{
+			Name:       r.Name,
+			ClassName:  r.ServiceName,
+			Time:       seconds(r.Duration),
+			Properties: []junitProperty{{Name: "plan_id", Value: r.PlanID}},
+		}
+
+		var out strings.Builder
+		for _, p := range r.Phases {
+			switch {
+			case p.SkipReason != "":
+				fmt.Fprintf(&out, "%s: skipped: %s\n", p.Name, p.SkipReason)
+			case p.Error != "":
+				testCase.Properties = append(testCase.Properties, junitProperty{Name: p.Name + "_duration", Value: seconds(p.Duration)})
+				fmt.Fprintf(&out, "%s: %s: %s\n", p.Name, p.Duration, p.Error)
+			default:
+				testCase.Properties = append(testCase.Properties, junitProperty{Name: p.Name + "_duration", Value: seconds(p.Duration)})
+				fmt.Fprintf(&out, "%s: %s\n", p.Name, p.Duration)
+			}
+		}
+		testCase.SystemOut = out.String()
+
+		report.Suites[i].Tests++
+		report.Tests++
+		switch {
+		case r.SkipReason != "":
+			testCase.Skipped = &junitSkipped{Message: r.SkipReason}
+			report.Suites[i].Skipped++
+			report.Skipped++
+		case !r.Passed:
+			testCase.Failure = &junitFailure{Message: r.Error}
+			report.Suites[i].Failures++
+			report.Failures++
+		}
+
+		report.Suites[i].TestCases = append(report.Suites[i].TestCases, testCase)
+		suiteTotals[i] += r.Duration
+		total += r.Duration
+	}
+
+	for i, t := range suiteTotals {
+		report.Suites[i].Time = seconds(t)
+	}
+	report.Time = seconds(total)
+
+	return report
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
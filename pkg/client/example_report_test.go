@@ -0,0 +1,132 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var reportResults = []ExampleResult{
+	{
+		ID:          "000",
+		Name:        "basic",
+		ServiceName: "fake-service",
+		PlanID:      "plan-1",
+		Passed:      true,
+		Duration:    3 * time.Second,
+		Phases: []PhaseResult{
+			{Name: "provision", Duration: 2 * time.Second},
+			{Name: "bind", Duration: 500 * time.Millisecond, Error: "unexpected response code 400: read-only"},
+			{Name: "unbind", SkipReason: "bind failed as expected"},
+			{Name: "deprovision", Duration: 500 * time.Millisecond},
+		},
+	},
+	{
+		ID:          "001",
+		Name:        "broken",
+		ServiceName: "fake-service",
+		PlanID:      "plan-2",
+		Duration:    time.Second,
+		Error:       "unexpected response code 500",
+		Phases: []PhaseResult{
+			{Name: "provision", Duration: time.Second, Error: "unexpected response code 500"},
+			{Name: "bind", SkipReason: "provision failed"},
+		},
+	},
+	{
+		ID:          "002",
+		Name:        "other",
+		ServiceName: "other-service",
+		PlanID:      "plan-3",
+		Passed:      true,
+		Duration:    1500 * time.Millisecond,
+	},
+	{
+		ID:          "003",
+		Name:        "upgrade",
+		ServiceName: "other-service",
+		PlanID:      "plan-4",
+		SkipReason:  "no previous version of the brokerpak to upgrade from",
+	},
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+
+	if err := writeReports(ReportPaths{JSON: path}, reportResults); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []ExampleResult
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, reportResults) {
+		t.Errorf("Expected: %v got: %v", reportResults, actual)
+	}
+
+	if !strings.Contains(string(data), `"skip_reason": "bind failed as expected"`) {
+		t.Errorf("Expected skip reason in report: %s", data)
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.xml")
+
+	if err := writeReports(ReportPaths{JUnit: path}, reportResults); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" failures="1" skipped="1" time="5.500">
+  <testsuite name="fake-service" tests="2" failures="1" skipped="0" time="4.000">
+    <testcase name="basic" classname="fake-service" time="3.000">
+      <properties>
+        <property name="plan_id" value="plan-1"></property>
+        <property name="provision_duration" value="2.000"></property>
+        <property name="bind_duration" value="0.500"></property>
+        <property name="deprovision_duration" value="0.500"></property>
+      </properties>
+      <system-out>provision: 2s&#xA;bind: 500ms: unexpected response code 400: read-only&#xA;unbind: skipped: bind failed as expected&#xA;deprovision: 500ms&#xA;</system-out>
+    </testcase>
+    <testcase name="broken" classname="fake-service" time="1.000">
+      <properties>
+        <property name="plan_id" value="plan-2"></property>
+        <property name="provision_duration" value="1.000"></property>
+      </properties>
+      <failure message="unexpected response code 500"></failure>
+      <system-out>provision: 1s: unexpected response code 500&#xA;bind: skipped: provision failed&#xA;</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="other-service" tests="2" failures="0" skipped="1" time="1.500">
+    <testcase name="other" classname="other-service" time="1.500">
+      <properties>
+        <property name="plan_id" value="plan-3"></property>
+      </properties>
+    </testcase>
+    <testcase name="upgrade" classname="other-service" time="0.000">
+      <properties>
+        <property name="plan_id" value="plan-4"></property>
+      </properties>
+      <skipped message="no previous version of the brokerpak to upgrade from"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, data)
+	}
+}
@@ -15,12 +15,16 @@
 package client
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,15 +36,17 @@ import (
 // RunExamplesForService runs all the examples for a given service name against
 // the service broker pointed to by client. All examples in the registry get run
 // if serviceName is blank. If exampleName is non-blank then only the example
-// with the given name is run.
-func RunExamplesForService(allExamples []CompleteServiceExample, client *Client, serviceName, exampleName string, jobCount int) {
-	runExamples(jobCount, client, FilterMatchingServiceExamples(allExamples, serviceName, exampleName))
+// with the given name is run. Examples that upgrade run against previous until
+// they upgrade, and are skipped if previous is nil.
+func RunExamplesForService(allExamples []CompleteServiceExample, client, previous *Client, serviceName, exampleName string, jobCount int, reports ReportPaths) {
+	runExamples(jobCount, client, previous, FilterMatchingServiceExamples(allExamples, serviceName, exampleName), reports)
 }
 
 // RunExamplesFromFile reads a json-encoded list of CompleteServiceExamples.
 // All examples in the list get run if serviceName is blank. If exampleName
-// is non-blank then only the example with the given name is run.
-func RunExamplesFromFile(client *Client, fileName, serviceName, exampleName string) {
+// is non-blank then only the example with the given name is run. Examples that
+// upgrade run against previous until they upgrade, and are skipped if previous is nil.
+func RunExamplesFromFile(client, previous *Client, fileName, serviceName, exampleName string, reports ReportPaths) {
 	data, err := os.ReadFile(fileName)
 	if err != nil {
 		log.Fatalf("Error opening file: %v", err)
@@ -49,18 +55,13 @@ func RunExamplesFromFile(client *Client, fileName, serviceName, exampleName stri
 	var allExamples []CompleteServiceExample
 	json.Unmarshal(data, &allExamples)
 
-	runExamples(1, client, FilterMatchingServiceExamples(allExamples, serviceName, exampleName))
+	runExamples(1, client, previous, FilterMatchingServiceExamples(allExamples, serviceName, exampleName), reports)
 }
 
-func runExamples(workers int, client *Client, examples []CompleteServiceExample) {
-	type result struct {
-		id, name, service string
-		duration          time.Duration
-		err               error
-	}
-	var results []result
+func runExamples(workers int, client, previous *Client, examples []CompleteServiceExample, reports ReportPaths) {
+	var results []ExampleResult
 	var resultsLock sync.Mutex
-	addResult := func(r result) {
+	addResult := func(r ExampleResult) {
 		resultsLock.Lock()
 		defer resultsLock.Unlock()
 		results = append(results, r)
@@ -76,15 +77,32 @@ func runExamples(workers int, client *Client, examples []CompleteServiceExample)
 	for range workers {
 		go func() {
 			for w := range queue {
+				if w.example.UpgradesBrokerpak() && previous == nil {
+					addResult(ExampleResult{
+						ID:          w.id,
+						Name:        w.example.Name,
+						ServiceName: w.example.ServiceName,
+						PlanID:      w.example.PlanID,
+						SkipReason:  "no previous version of the brokerpak to upgrade from",
+					})
+					continue
+				}
+
 				start := time.Now()
-				err := runExample(client, w.id, w.example)
-				addResult(result{
-					id:       w.id,
-					name:     w.example.Name,
-					service:  w.example.ServiceName,
-					duration: time.Since(start),
-					err:      err,
-				})
+				phases, err := runExample(client, previous, w.id, w.example)
+				result := ExampleResult{
+					ID:          w.id,
+					Name:        w.example.Name,
+					ServiceName: w.example.ServiceName,
+					PlanID:      w.example.PlanID,
+					Passed:      err == nil,
+					Duration:    time.Since(start),
+					Phases:      phases,
+				}
+				if err != nil {
+					result.Error = err.Error()
+				}
+				addResult(result)
 			}
 			wg.Done()
 		}()
@@ -99,28 +117,38 @@ func runExamples(workers int, client *Client, examples []CompleteServiceExample)
 	close(queue)
 	wg.Wait()
 
-	failed := 0
+	slices.SortFunc(results, func(a, b ExampleResult) int { return strings.Compare(a.ID, b.ID) })
+	reportErr := writeReports(reports, results)
+
+	failed, skipped := 0, 0
 	log.Println()
 	log.Println("RESULTS:")
 	log.Println()
 	log.Println("id | name | service | duration | result")
 	log.Println("-- | ---- | ------- | -------- | ------")
 	for _, r := range results {
-		switch r.err {
-		case nil:
-			log.Printf("%s | %s | %s | %s | PASS\n", r.id, r.name, r.service, r.duration)
+		switch {
+		case r.SkipReason != "":
+			skipped++
+			log.Printf("%s | %s | %s | %s | SKIPPED %s\n", r.ID, r.Name, r.ServiceName, r.Duration, r.SkipReason)
+		case r.Passed:
+			log.Printf("%s | %s | %s | %s | PASS\n", r.ID, r.Name, r.ServiceName, r.Duration)
 		default:
 			failed++
-			log.Printf("%s | %s | %s | %s | FAILED %s\n", r.id, r.name, r.service, r.duration, r.err)
+			log.Printf("%s | %s | %s | %s | FAILED %s\n", r.ID, r.Name, r.ServiceName, r.Duration, r.Error)
 		}
 	}
 	log.Println()
 
-	switch failed {
-	case 0:
-		log.Println("Success")
-	default:
+	switch {
+	case failed > 0:
 		log.Fatalf("FAILED %d examples", failed)
+	case reportErr != nil:
+		log.Fatalf("Error writing reports: %v", reportErr)
+	case skipped > 0:
+		log.Printf("Success, skipped %d examples", skipped)
+	default:
+		log.Println("Success")
 	}
 }
 
@@ -170,16 +198,25 @@ func FilterMatchingServiceExamples(allExamples []CompleteServiceExample, service
 }
 
 // RunExample runs a single example against the given service on the broker
-// pointed to by client.
-func runExample(client *Client, id string, serviceExample CompleteServiceExample) error {
+// pointed to by client. Steps before an upgrade run against previous, which
+// serves the previous version of the brokerpak. It returns the timings of each
+// phase of the example.
+func runExample(client, previous *Client, id string, serviceExample CompleteServiceExample) ([]PhaseResult, error) {
 	logger := newExampleLogger(id)
-	executor, err := newExampleExecutor(logger, id, client, serviceExample)
+	executor, err := newExampleExecutor(logger, id, client, previous, serviceExample)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	executor.LogTestInfo(logger)
 
+	phases := stepPhaseNames(executor.Steps)
+	binds := slices.ContainsFunc(executor.Steps, func(s broker.ExampleStep) bool { return s.Action == broker.ExampleActionBind })
+	if binds {
+		phases = append(phases, "unbind")
+	}
+	recorder := newPhaseRecorder(append(slices.Clone(phases), "deprovision")...)
+
 	// Cleanup the test if it fails partway through
 	defer func() {
 		logger.Println("Cleaning up the environment")
@@ -187,35 +224,67 @@ func runExample(client *Client, id string, serviceExample CompleteServiceExample
 		executor.Deprovision()
 	}()
 
-	if err := executor.Provision(); err != nil {
-		logger.Printf("Failed to provision %v: %v", serviceExample.ServiceName, err)
-		return err
+	var bindResponse json.RawMessage
+	bound := false
+	unbindSkipReason := ""
+	for i, step := range executor.Steps {
+		stepErr := recorder.run(phases[i], func() (err error) {
+			switch step.Action {
+			case broker.ExampleActionProvision:
+				return executor.Provision()
+			case broker.ExampleActionUpdate:
+				return executor.Update(step)
+			case broker.ExampleActionUpgrade:
+				return executor.Upgrade()
+			case broker.ExampleActionBind:
+				bindResponse, err = executor.Bind()
+				return err
+			default:
+				return fmt.Errorf("unknown action %q", step.Action)
+			}
+		})
+
+		switch {
+		case step.ExpectedError != "":
+			if err := matchExpectedError(step.Action, stepErr, step.ExpectedError); err != nil {
+				return recorder.results(), err
+			}
+			if step.Action == broker.ExampleActionProvision {
+				return recorder.results(), nil
+			}
+			unbindSkipReason = fmt.Sprintf("%s failed as expected", phases[i])
+		case stepErr != nil && step.CanFail:
+			log.Printf("WARNING: %s failed: %v, but marked 'can fail' so treated as warning.", step.Action, stepErr)
+			unbindSkipReason = fmt.Sprintf("%s failed, but marked 'can fail'", phases[i])
+		case stepErr != nil:
+			log.Printf("Failed to %s %v: %v", step.Action, serviceExample.ServiceName, stepErr)
+			return recorder.results(), stepErr
+		case step.Action == broker.ExampleActionBind:
+			bound = true
+		}
 	}
 
-	bindResponse, bindErr := executor.Bind()
-	if bindErr != nil {
-		if serviceExample.BindCanFail {
-			log.Printf("WARNING: bind failed: %v, but marked 'can fail' so treated as warning.", bindErr)
-		} else {
-			log.Printf("Failed to bind %v: %v", serviceExample.ServiceName, bindErr)
-			return bindErr
+	switch {
+	case bound:
+		if err := recorder.run("unbind", executor.Unbind); err != nil {
+			log.Printf("Failed to unbind %v: %v", serviceExample.ServiceName, err)
+			return recorder.results(), err
 		}
-	} else if err := executor.Unbind(); err != nil {
-		log.Printf("Failed to unbind %v: %v", serviceExample.ServiceName, err)
-		return err
+	case binds:
+		recorder.skip("unbind", unbindSkipReason)
 	}
 
-	if err := executor.Deprovision(); err != nil {
+	if err := recorder.run("deprovision", executor.Deprovision); err != nil {
 		log.Printf("Failed to deprovision %v: %v", serviceExample.ServiceName, err)
-		return err
+		return recorder.results(), err
 	}
 
-	if bindErr == nil {
+	if bound {
 		// Check that the binding response has the same fields as expected
 		var binding domain.Binding
 		err = json.Unmarshal(bindResponse, &binding)
 		if err != nil {
-			return err
+			return recorder.results(), err
 		}
 
 		credentialsEntry := binding.Credentials.(map[string]any)
@@ -223,13 +292,78 @@ func runExample(client *Client, id string, serviceExample CompleteServiceExample
 		if err := broker.ValidateVariablesAgainstSchema(credentialsEntry, serviceExample.ExpectedOutput); err != nil {
 			log.Printf("Error: results don't match JSON Schema: %v", err)
 			log.Printf("Schema: %v\n, Actual: %v", serviceExample.ExpectedOutput, credentialsEntry)
-			return err
+			return recorder.results(), err
 		}
+
+		if err := checkCredentials(credentialsEntry, serviceExample.ExpectedCredentials); err != nil {
+			log.Printf("Error: credentials don't match the expected credentials: %v", err)
+			return recorder.results(), err
+		}
+	}
+
+	return recorder.results(), nil
+}
+
+// stepPhaseNames names the phase of each step after its action. Actions that are repeated, such as update,
+// are numbered so that each phase is reported separately, for example update#1 and update#2.
+func stepPhaseNames(steps []broker.ExampleStep) []string {
+	counts := make(map[string]int)
+	for _, s := range steps {
+		counts[s.Action]++
 	}
 
+	seen := make(map[string]int)
+	names := make([]string, 0, len(steps))
+	for _, s := range steps {
+		seen[s.Action]++
+		if counts[s.Action] > 1 {
+			names = append(names, fmt.Sprintf("%s#%d", s.Action, seen[s.Action]))
+		} else {
+			names = append(names, s.Action)
+		}
+	}
+	return names
+}
+
+// AssertionError is returned when an example runs, but the results are not as it expects
+type AssertionError struct {
+	Failures []string
+}
+
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("assertions failed: %s", strings.Join(e.Failures, "; "))
+}
+
+func checkCredentials(credentials map[string]any, assertions []broker.CredentialAssertion) error {
+	var failures []string
+	for _, a := range assertions {
+		if err := a.Check(credentials); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return &AssertionError{Failures: failures}
+	}
 	return nil
 }
 
+// matchExpectedError checks that an operation failed with a message matching the regular expression
+func matchExpectedError(operation string, err error, expected string) error {
+	re, reErr := regexp.Compile(expected)
+	switch {
+	case reErr != nil:
+		return fmt.Errorf("invalid regular expression %q for expected %s error: %w", expected, operation, reErr)
+	case err == nil:
+		return &AssertionError{Failures: []string{fmt.Sprintf("expected %s to fail with an error matching %q, but it succeeded", operation, expected)}}
+	case !re.MatchString(err.Error()):
+		return &AssertionError{Failures: []string{fmt.Sprintf("expected %s to fail with an error matching %q, but got: %s", operation, expected, err)}}
+	default:
+		log.Printf("%s failed as expected: %v", operation, err)
+		return nil
+	}
+}
+
 func retry(timeout, period time.Duration, function func() (tryAgain bool, err error)) error {
 	to := time.After(timeout)
 	tick := time.NewTicker(period).C
@@ -253,29 +387,54 @@ func retry(timeout, period time.Duration, function func() (tryAgain bool, err er
 	}
 }
 
-func newExampleExecutor(logger *exampleLogger, id string, client *Client, serviceExample CompleteServiceExample) (*exampleExecutor, error) {
-	provisionParams, err := json.Marshal(serviceExample.ServiceExample.ProvisionParams)
+func newExampleExecutor(logger *exampleLogger, id string, client, previous *Client, serviceExample CompleteServiceExample) (*exampleExecutor, error) {
+	steps := serviceExample.ServiceExample.Steps()
+
+	planID := serviceExample.ServiceExample.PlanID
+	provisionParams := serviceExample.ServiceExample.ProvisionParams
+	bindParams := serviceExample.ServiceExample.BindParams
+	for _, s := range steps {
+		switch {
+		case s.Action == broker.ExampleActionProvision:
+			planID = cmp.Or(s.PlanID, planID)
+			if s.Params != nil {
+				provisionParams = s.Params
+			}
+		case s.Action == broker.ExampleActionBind && s.Params != nil:
+			bindParams = s.Params
+		}
+	}
+
+	provisionJSON, err := json.Marshal(provisionParams)
 	if err != nil {
 		return nil, err
 	}
 
-	bindParams, err := json.Marshal(serviceExample.ServiceExample.BindParams)
+	bindJSON, err := json.Marshal(bindParams)
 	if err != nil {
 		return nil, err
 	}
 
+	// Steps before an upgrade run against the previous version of the brokerpak
+	initial := client
+	if serviceExample.UpgradesBrokerpak() {
+		initial = previous
+	}
+
 	return &exampleExecutor{
 		Name:       fmt.Sprintf("%s/%s", serviceExample.ServiceName, serviceExample.ServiceExample.Name),
 		ServiceID:  serviceExample.ServiceID,
-		PlanID:     serviceExample.ServiceExample.PlanID,
+		PlanID:     planID,
 		InstanceID: uuid.NewString(),
 		BindingID:  uuid.NewString(),
 
-		ProvisionParams: provisionParams,
-		BindParams:      bindParams,
+		ProvisionParams: provisionJSON,
+		BindParams:      bindJSON,
+		Steps:           steps,
 
-		logger: logger,
-		client: client,
+		logger:  logger,
+		client:  initial,
+		current: client,
 	}, nil
 }
 
@@ -289,9 +448,13 @@ type exampleExecutor struct {
 
 	ProvisionParams json.RawMessage
 	BindParams      json.RawMessage
+	Steps           []broker.ExampleStep
 
 	logger *exampleLogger
-	client *Client
+	// client is the broker that requests are sent to, which changes from the previous
+	// version of the brokerpak to the current version on upgrade
+	client  *Client
+	current *Client
 }
 
 // Provision attempts to create a service instance from the example.
@@ -316,7 +479,7 @@ func (ee *exampleExecutor) Provision() error {
 	case http.StatusAccepted:
 		return ee.pollUntilFinished()
 	default:
-		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+		return unexpectedResponse(resp)
 	}
 }
 
@@ -354,6 +517,97 @@ func (ee *exampleExecutor) pollUntilFinished() error {
 	})
 }
 
+// Update changes the plan and parameters of the instance created by a call to Provision.
+// If the plan is changed, later calls use the new plan.
+func (ee *exampleExecutor) Update(step broker.ExampleStep) error {
+	params, err := json.Marshal(step.Params)
+	if err != nil {
+		return err
+	}
+	planID := cmp.Or(step.PlanID, ee.PlanID)
+
+	requestID := uuid.NewString()
+	ee.logger.Printf("Updating %s (id: %s)\n", ee.Name, requestID)
+	resp := ee.client.Update(ee.InstanceID, ee.ServiceID, planID, requestID, params, domain.PreviousValues{ServiceID: ee.ServiceID, PlanID: ee.PlanID}, nil)
+	if err := ee.awaitUpdate(resp); err != nil {
+		return err
+	}
+
+	ee.PlanID = planID
+	return nil
+}
+
+// Upgrade moves the instance created by a call to Provision from the previous version of
+// the brokerpak to the current version, in the same way as "csb upgrade-service". Later
+// calls use the current version.
+func (ee *exampleExecutor) Upgrade() error {
+	previousInfo, err := planMaintenanceInfo(ee.client, ee.ServiceID, ee.PlanID)
+	if err != nil {
+		return fmt.Errorf("previous version of the brokerpak: %w", err)
+	}
+
+	currentInfo, err := planMaintenanceInfo(ee.current, ee.ServiceID, ee.PlanID)
+	if err != nil {
+		return fmt.Errorf("current version of the brokerpak: %w", err)
+	}
+
+	requestID := uuid.NewString()
+	ee.logger.Printf("Upgrading %s from %q to %q (id: %s)\n", ee.Name, previousInfo.Version, currentInfo.Version, requestID)
+
+	// Cleanup goes to the current version from here, even if the upgrade fails
+	ee.client = ee.current
+	resp := ee.client.Update(ee.InstanceID, ee.ServiceID, ee.PlanID, requestID, json.RawMessage("{}"),
+		domain.PreviousValues{ServiceID: ee.ServiceID, PlanID: ee.PlanID, MaintenanceInfo: previousInfo}, currentInfo)
+	return ee.awaitUpdate(resp)
+}
+
+func (ee *exampleExecutor) awaitUpdate(resp *BrokerResponse) error {
+	ee.logger.Println(resp.String())
+	if resp.InError() {
+		return resp.Error
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusAccepted:
+		return ee.pollUntilFinished()
+	default:
+		return unexpectedResponse(resp)
+	}
+}
+
+// planMaintenanceInfo reads the maintenance info of a plan from the catalog of the broker
+func planMaintenanceInfo(client *Client, serviceID, planID string) (*domain.MaintenanceInfo, error) {
+	resp := client.Catalog(uuid.NewString())
+	switch {
+	case resp.InError():
+		return nil, resp.Error
+	case resp.StatusCode != http.StatusOK:
+		return nil, unexpectedResponse(resp)
+	}
+
+	var catalog struct {
+		Services []domain.Service `json:"services"`
+	}
+	if err := json.Unmarshal(resp.ResponseBody, &catalog); err != nil {
+		return nil, err
+	}
+
+	for _, s := range catalog.Services {
+		if s.ID != serviceID {
+			continue
+		}
+		for _, p := range s.Plans {
+			if p.ID == planID {
+				return cmp.Or(p.MaintenanceInfo, &domain.MaintenanceInfo{}), nil
+			}
+		}
+		return nil, fmt.Errorf("could not find plan %q in service %q", planID, serviceID)
+	}
+	return nil, fmt.Errorf("could not find service %q in catalog", serviceID)
+}
+
 // Deprovision destroys the instance created by a call to Provision.
 func (ee *exampleExecutor) Deprovision() error {
 	requestID := uuid.NewString()
@@ -371,7 +625,7 @@ func (ee *exampleExecutor) Deprovision() error {
 	case http.StatusAccepted:
 		return ee.pollUntilFinished()
 	default:
-		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+		return unexpectedResponse(resp)
 	}
 }
 
@@ -391,7 +645,7 @@ func (ee *exampleExecutor) Unbind() error {
 			return false, nil
 		}
 
-		return false, fmt.Errorf("unexpected response code %d", resp.StatusCode)
+		return false, unexpectedResponse(resp)
 	})
 }
 
@@ -412,7 +666,19 @@ func (ee *exampleExecutor) Bind() (json.RawMessage, error) {
 		return resp.ResponseBody, nil
 	}
 
-	return nil, fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	return nil, unexpectedResponse(resp)
+}
+
+// unexpectedResponse describes a response with an unexpected status code, including the
+// description of the error from the broker so that it can be matched by expected errors
+func unexpectedResponse(resp *BrokerResponse) error {
+	var body struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(resp.ResponseBody, &body); err == nil && body.Description != "" {
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, body.Description)
+	}
+	return fmt.Errorf("unexpected response code %d", resp.StatusCode)
 }
 
 // LogTestInfo writes information about the running example and a manual backout
@@ -421,8 +687,19 @@ func (ee *exampleExecutor) LogTestInfo(logger *exampleLogger) {
 	logger.Printf("Running Example: %s\n", ee.Name)
 
 	ips := fmt.Sprintf("--instanceid %q --planid %q --serviceid %q", ee.InstanceID, ee.PlanID, ee.ServiceID)
-	logger.Printf("cloud-service-broker client provision %s --params %q\n", ips, ee.ProvisionParams)
-	logger.Printf("cloud-service-broker client bind %s --bindingid %q --params %q\n", ips, ee.BindingID, ee.BindParams)
+	for _, s := range ee.Steps {
+		switch s.Action {
+		case broker.ExampleActionProvision:
+			logger.Printf("cloud-service-broker client provision %s --params %q\n", ips, ee.ProvisionParams)
+		case broker.ExampleActionUpdate:
+			params, _ := json.Marshal(s.Params)
+			logger.Printf("cloud-service-broker client update --instanceid %q --planid %q --serviceid %q --params %q\n", ee.InstanceID, cmp.Or(s.PlanID, ee.PlanID), ee.ServiceID, params)
+		case broker.ExampleActionUpgrade:
+			logger.Printf("cloud-service-broker client upgrade %s --oldversion PREVIOUS_VERSION --newversion CURRENT_VERSION\n", ips)
+		case broker.ExampleActionBind:
+			logger.Printf("cloud-service-broker client bind %s --bindingid %q --params %q\n", ips, ee.BindingID, ee.BindParams)
+		}
+	}
 	logger.Printf("cloud-service-broker client unbind %s --bindingid %q\n", ips, ee.BindingID)
 	logger.Printf("cloud-service-broker client deprovision %s\n", ips)
 }
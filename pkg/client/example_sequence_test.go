@@ -0,0 +1,166 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+func TestRunExampleSequence(t *testing.T) {
+	cases := map[string]struct {
+		Sequence                 []broker.ExampleStep
+		CurrentResponses         map[string]fakeResponse
+		ExpectedError            string
+		ExpectedPreviousRequests string
+		ExpectedCurrentRequests  string
+		ExpectedPhases           string
+	}{
+		"update plan and params": {
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionUpdate, PlanID: "other-plan-id", Params: map[string]any{"size": 20}},
+				{Action: broker.ExampleActionBind},
+			},
+			ExpectedCurrentRequests: "provision update bind unbind deprovision unbind deprovision",
+			ExpectedPhases:          "provision update bind unbind deprovision",
+		},
+		"update after bind": {
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionBind},
+				{Action: broker.ExampleActionUpdate, Params: map[string]any{"size": 20}},
+			},
+			ExpectedCurrentRequests: "provision bind update unbind deprovision unbind deprovision",
+			ExpectedPhases:          "provision bind update unbind deprovision",
+		},
+		"repeated updates": {
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionUpdate, Params: map[string]any{"size": 20}},
+				{Action: broker.ExampleActionUpdate, Params: map[string]any{"size": 30}, ExpectedError: "too big"},
+				{Action: broker.ExampleActionBind},
+			},
+			CurrentResponses: map[string]fakeResponse{
+				"update#2": {status: http.StatusUnprocessableEntity, body: `{"description":"size too big"}`},
+			},
+			ExpectedCurrentRequests: "provision update update bind unbind deprovision unbind deprovision",
+			ExpectedPhases:          "provision update#1 update#2(failed) bind unbind deprovision",
+		},
+		"upgrade": {
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionUpdate, Params: map[string]any{"size": 20}},
+				{Action: broker.ExampleActionUpgrade},
+				{Action: broker.ExampleActionBind},
+			},
+			ExpectedPreviousRequests: "provision update",
+			ExpectedCurrentRequests:  "upgrade(1.0.0->2.0.0) bind unbind deprovision unbind deprovision",
+			ExpectedPhases:           "provision update upgrade bind unbind deprovision",
+		},
+		"upgrade fails": {
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionUpgrade},
+				{Action: broker.ExampleActionBind},
+			},
+			CurrentResponses: map[string]fakeResponse{
+				"update": {status: http.StatusInternalServerError, body: `{"description":"tofu upgrade failed"}`},
+			},
+			ExpectedError:            "unexpected response code 500: tofu upgrade failed",
+			ExpectedPreviousRequests: "provision",
+			ExpectedCurrentRequests:  "upgrade(1.0.0->2.0.0) unbind deprovision",
+			ExpectedPhases:           "provision upgrade(failed) bind(skipped: upgrade failed) unbind(skipped: upgrade failed) deprovision(skipped: upgrade failed)",
+		},
+		"upgrade expected to fail": {
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionUpgrade, ExpectedError: "not supported"},
+			},
+			CurrentResponses: map[string]fakeResponse{
+				"update": {status: http.StatusUnprocessableEntity, body: `{"description":"upgrade not supported"}`},
+			},
+			ExpectedPreviousRequests: "provision",
+			ExpectedCurrentRequests:  "upgrade(1.0.0->2.0.0) deprovision unbind deprovision",
+			ExpectedPhases:           "provision upgrade(failed) deprovision",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			currentClient, current := newFakeBrokerClient(t, tc.CurrentResponses)
+			current.version = "2.0.0"
+			previousClient, previous := newFakeBrokerClient(t, nil)
+			previous.version = "1.0.0"
+
+			example := CompleteServiceExample{
+				ServiceExample: broker.ServiceExample{
+					Name:     tn,
+					PlanID:   "fake-plan-id",
+					Sequence: tc.Sequence,
+				},
+				ServiceName:    "fake-service",
+				ServiceID:      "fake-service-id",
+				ExpectedOutput: map[string]any{"type": "object"},
+			}
+
+			phases, err := runExample(currentClient, previousClient, "000", example)
+			switch {
+			case tc.ExpectedError == "" && err != nil:
+				t.Fatalf("Expected no error but got: %v", err)
+			case tc.ExpectedError != "" && (err == nil || err.Error() != tc.ExpectedError):
+				t.Fatalf("Expected error %q but got: %v", tc.ExpectedError, err)
+			}
+
+			if actual := strings.Join(previous.requests, " "); actual != tc.ExpectedPreviousRequests {
+				t.Errorf("Expected requests to the previous broker: %q got: %q", tc.ExpectedPreviousRequests, actual)
+			}
+			if actual := strings.Join(current.requests, " "); actual != tc.ExpectedCurrentRequests {
+				t.Errorf("Expected requests to the current broker: %q got: %q", tc.ExpectedCurrentRequests, actual)
+			}
+			if actual := summarisePhases(phases); actual != tc.ExpectedPhases {
+				t.Errorf("Expected phases: %q got: %q", tc.ExpectedPhases, actual)
+			}
+		})
+	}
+}
+
+func TestRunExamplesSkipsUpgradesWithoutPreviousBroker(t *testing.T) {
+	apiClient, fake := newFakeBrokerClient(t, nil)
+	report := filepath.Join(t.TempDir(), "report.json")
+
+	examples := []CompleteServiceExample{{
+		ServiceExample: broker.ServiceExample{
+			Name:   "upgrade",
+			PlanID: "fake-plan-id",
+			Sequence: []broker.ExampleStep{
+				{Action: broker.ExampleActionProvision},
+				{Action: broker.ExampleActionUpgrade},
+			},
+		},
+		ServiceName: "fake-service",
+		ServiceID:   "fake-service-id",
+	}}
+
+	runExamples(1, apiClient, nil, examples, ReportPaths{JSON: report})
+
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected no requests but got: %q", fake.requests)
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []ExampleResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].SkipReason != "no previous version of the brokerpak to upgrade from" {
+		t.Errorf("Expected a skipped example but got: %+v", results)
+	}
+}
@@ -9,6 +9,11 @@ const (
 	TfUpgradeEnabled                 FeatureFlagName = "brokerpak.terraform.upgrades.enabled"
 	DynamicHCLEnabled                FeatureFlagName = "brokerpak.updates.enabled"
 	DisableRequestPropertyValidation FeatureFlagName = "request.property.validation.disabled"
+	AsyncBindingsEnabled             FeatureFlagName = "bindings.async.enabled"
+
+	// MultiVersionBrokerpaksEnabled allows several versions of the same brokerpak to be loaded at once.
+	// Service instances stay on the version they were provisioned with until they are upgraded.
+	MultiVersionBrokerpaksEnabled FeatureFlagName = "brokerpak.multiversion.enabled"
 
 	// EnableLegacyExamplesCommands enabled the old way of running example tests
 	// IF YOU USE THIS, PLEASE RAISE AN ISSUE. Since the new way of running examples was added,
@@ -19,19 +24,33 @@ const (
 
 var AllFeatureFlagEnvVars []string
 
+var envVars = make(map[FeatureFlagName]string)
+
 func init() {
 	for ffName, varName := range map[FeatureFlagName]string{
 		TfUpgradeEnabled:                 "TERRAFORM_UPGRADES_ENABLED", // deprecated pattern - future variables should start CSB_
 		DynamicHCLEnabled:                "BROKERPAK_UPDATES_ENABLED",  // deprecated pattern - future variables should start CSB_
 		DisableRequestPropertyValidation: "CSB_DISABLE_REQUEST_PROPERTY_VALIDATION",
+		AsyncBindingsEnabled:             "CSB_ENABLE_ASYNC_BINDINGS",
+		MultiVersionBrokerpaksEnabled:    "CSB_ENABLE_MULTI_VERSION_BROKERPAKS",
 		EnableLegacyExamplesCommands:     "CSB_ENABLE_LEGACY_EXAMPLES_COMMANDS",
 	} {
 		viper.BindEnv(string(ffName), varName)
 		viper.SetDefault(string(ffName), false)
 		AllFeatureFlagEnvVars = append(AllFeatureFlagEnvVars, varName)
+		envVars[ffName] = varName
 	}
 }
 
 func Enabled(name FeatureFlagName) bool {
 	return viper.GetBool(string(name))
 }
+
+// All returns whether each feature flag is enabled, keyed by the environment variable that sets it
+func All() map[string]bool {
+	result := make(map[string]bool, len(envVars))
+	for name, varName := range envVars {
+		result[varName] = Enabled(name)
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package tf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/steps"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
+)
+
+// Adopt brings existing resources under the management of a new service instance. Unlike the
+// import of a legacy plan, the workspace is created from the normal provision template. The resources,
+// given as a map of resource addresses in the template to IaaS resource IDs, are imported into it, and
+// then a plan must show that the resources already match the template. Only then is the workspace applied,
+// which records the outputs without changing the resources. Unlike a provision, it waits for the result.
+func (provider *TerraformProvider) Adopt(ctx context.Context, provisionContext *varcontext.VarContext, resources map[string]string) error {
+	provider.logger.Debug("terraform-adopt", correlation.ID(ctx), lager.Data{
+		"context":   provisionContext.ToMap(),
+		"resources": resources,
+	})
+
+	if len(resources) == 0 {
+		return errors.New("at least one resource must be specified to adopt")
+	}
+
+	tfID := provisionContext.GetString("tf_id")
+	if err := provisionContext.Error(); err != nil {
+		return err
+	}
+
+	action := provider.serviceDefinition.ProvisionSettings
+	newWorkspace, err := workspace.NewWorkspace(provisionContext.ToMap(), action.Template, action.Templates, []workspace.ParameterMapping{}, []string{}, []workspace.ParameterMapping{})
+	if err != nil {
+		return fmt.Errorf("error creating workspace: %w", err)
+	}
+
+	deployment, err := provider.CreateAndSaveDeployment(tfID, newWorkspace)
+	if err != nil {
+		provider.logger.Error("deployment create failed", err)
+		return fmt.Errorf("deployment create failed: %w", err)
+	}
+
+	if err := provider.MarkOperationStarted(&deployment, models.ProvisionOperationType); err != nil {
+		return fmt.Errorf("error marking job started: %w", err)
+	}
+
+	logger := provider.logger.WithData(correlation.ID(ctx))
+	terraformInvoker := provider.DefaultInvoker()
+
+	err = steps.RunSequentially(
+		func() error {
+			return terraformInvoker.Import(ctx, newWorkspace, resources)
+		},
+		func() error {
+			plan, err := terraformInvoker.PlanJSON(ctx, newWorkspace)
+			if err != nil {
+				return err
+			}
+			return CheckTerraformPlanHasNoChanges(logger, plan)
+		},
+		func() error {
+			return terraformInvoker.Apply(ctx, newWorkspace)
+		},
+	)
+	if err != nil {
+		logger.Error("operation failed", err)
+		_ = provider.MarkOperationFinished(&deployment, err)
+		return err
+	}
+
+	if err := provider.MarkOperationFinished(&deployment, nil); err != nil {
+		return fmt.Errorf("error marking job finished: %w", err)
+	}
+	return nil
+}
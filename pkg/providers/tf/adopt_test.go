@@ -0,0 +1,118 @@
+package tf_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/tffakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+var _ = Describe("Adopt", func() {
+	const expectedTfID = "tf:567c6af0-d68a-11ec-a5b6-367dda7ea869:"
+
+	var (
+		fakeDeploymentManager *tffakes.FakeDeploymentManagerInterface
+		deployment            storage.TerraformDeployment
+		fakeInvokerBuilder    *tffakes.FakeTerraformInvokerBuilder
+		fakeDefaultInvoker    *tffakes.FakeTerraformInvoker
+		provisionContext      *varcontext.VarContext
+		provider              *tf.TerraformProvider
+		resources             = map[string]string{"random_string.username": "some-user"}
+		template              = `variable username {type = string}`
+	)
+
+	BeforeEach(func() {
+		var err error
+		provisionContext, err = varcontext.Builder().MergeMap(map[string]any{"tf_id": expectedTfID, "username": "some-user"}).Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeInvokerBuilder = &tffakes.FakeTerraformInvokerBuilder{}
+		fakeDefaultInvoker = &tffakes.FakeTerraformInvoker{}
+		fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+
+		deployment = storage.TerraformDeployment{
+			ID:        expectedTfID,
+			Workspace: &workspace.TerraformWorkspace{Modules: []workspace.ModuleDefinition{{Name: "brokertemplate"}}},
+		}
+		fakeDeploymentManager = &tffakes.FakeDeploymentManagerInterface{}
+		fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+
+		serviceDefinition := tf.TfServiceDefinitionV1{
+			ProvisionSettings: tf.TfServiceDefinitionV1Action{Templates: map[string]string{"main": template}},
+		}
+		provider = tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, utils.NewLogger("test"), serviceDefinition, fakeDeploymentManager)
+	})
+
+	It("imports the resources into the provision template, checks the plan and applies", func() {
+		fakeDefaultInvoker.PlanJSONReturns(executor.ExecutionOutput{StdOut: `{"resource_changes":[{"address":"random_string.username","change":{"actions":["no-op"]}}]}`}, nil)
+
+		Expect(provider.Adopt(context.TODO(), provisionContext, resources)).To(Succeed())
+
+		By("checking the deployment uses the provision template")
+		Expect(fakeDeploymentManager.CreateAndSaveDeploymentCallCount()).To(Equal(1))
+		actualTfID, actualWorkspace := fakeDeploymentManager.CreateAndSaveDeploymentArgsForCall(0)
+		Expect(actualTfID).To(Equal(expectedTfID))
+		Expect(actualWorkspace.Modules[0].Definitions).To(Equal(map[string]string{"main": template}))
+		Expect(actualWorkspace.Instances[0].Configuration).To(Equal(map[string]any{"username": "some-user"}))
+
+		By("checking that the operation is a provision")
+		Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
+		_, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+		Expect(actualOperationType).To(Equal("provision"))
+
+		By("checking the resources were imported, planned and applied")
+		Expect(operationWasFinishedForDeployment(fakeDeploymentManager)()).To(Equal(deployment))
+		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
+		Expect(fakeDefaultInvoker.ImportCallCount()).To(Equal(1))
+		_, importedWorkspace, importedResources := fakeDefaultInvoker.ImportArgsForCall(0)
+		Expect(importedWorkspace).To(Equal(actualWorkspace))
+		Expect(importedResources).To(Equal(resources))
+		Expect(fakeDefaultInvoker.PlanJSONCallCount()).To(Equal(1))
+		Expect(fakeDefaultInvoker.ApplyCallCount()).To(Equal(1))
+	})
+
+	It("does not apply when the plan shows changes", func() {
+		fakeDefaultInvoker.PlanJSONReturns(executor.ExecutionOutput{StdOut: `{"resource_changes":[{"address":"random_string.username","change":{"actions":["update"]}}]}`}, nil)
+
+		err := provider.Adopt(context.TODO(), provisionContext, resources)
+
+		Expect(err).To(MatchError(ContainSubstring("the imported resources do not match the provision template")))
+		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError(err))
+		Expect(fakeDefaultInvoker.ApplyCallCount()).To(BeZero())
+	})
+
+	It("does not plan when the import fails", func() {
+		fakeDefaultInvoker.ImportReturns(errors.New("import failed"))
+
+		err := provider.Adopt(context.TODO(), provisionContext, resources)
+
+		Expect(err).To(MatchError("import failed"))
+		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("import failed"))
+		Expect(fakeDefaultInvoker.PlanJSONCallCount()).To(BeZero())
+		Expect(fakeDefaultInvoker.ApplyCallCount()).To(BeZero())
+	})
+
+	It("fails when no resources are specified", func() {
+		err := provider.Adopt(context.TODO(), provisionContext, nil)
+
+		Expect(err).To(MatchError("at least one resource must be specified to adopt"))
+		Expect(fakeDeploymentManager.CreateAndSaveDeploymentCallCount()).To(BeZero())
+	})
+
+	It("fails when the deployment cannot be saved", func() {
+		fakeDeploymentManager.CreateAndSaveDeploymentReturns(storage.TerraformDeployment{}, errors.New("cant save now"))
+
+		err := provider.Adopt(context.TODO(), provisionContext, resources)
+
+		Expect(err).To(MatchError("deployment create failed: cant save now"))
+	})
+})
@@ -31,3 +31,17 @@ func (provider *TerraformProvider) Bind(ctx context.Context, bindContext *varcon
 
 	return provider.outputs(tfID, workspace.DefaultInstanceName)
 }
+
+// BindAsync creates a new backing Terraform job and executes it without waiting on the result.
+// Progress can be followed with PollBinding, and the result read with GetBindingOutputs.
+func (provider *TerraformProvider) BindAsync(ctx context.Context, bindContext *varcontext.VarContext) error {
+	provider.logger.Debug("terraform-bind-async", correlation.ID(ctx), lager.Data{
+		"context": bindContext.ToMap(),
+	})
+
+	if err := provider.create(ctx, bindContext, provider.serviceDefinition.BindSettings, models.BindOperationType); err != nil {
+		return fmt.Errorf("error from provider bind: %w", err)
+	}
+
+	return nil
+}
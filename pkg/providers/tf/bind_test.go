@@ -166,4 +166,37 @@ var _ = Describe("Bind", func() {
 		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("some TF issue happened"))
 	})
 
+	Describe("BindAsync", func() {
+		It("starts the bind without waiting for it to complete", func() {
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(deployment, nil)
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeDefaultInvoker.ApplyReturns(nil)
+
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			err := provider.BindAsync(context.TODO(), bindContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("checking that bind is marked as started")
+			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
+			_, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			Expect(actualOperationType).To(Equal("bind"))
+
+			By("checking the result is not waited on")
+			Expect(fakeDeploymentManager.OperationStatusCallCount()).To(BeZero())
+
+			By("checking TF apply has been called")
+			Eventually(applyCallCount(fakeDefaultInvoker)).Should(Equal(1))
+			Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+		})
+
+		It("fails, when it errors saving the deployment", func() {
+			fakeDeploymentManager.CreateAndSaveDeploymentReturns(storage.TerraformDeployment{}, errors.New("cant save now"))
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			err := provider.BindAsync(context.TODO(), bindContext)
+
+			Expect(err).To(MatchError("error from provider bind: deployment create failed: cant save now"))
+		})
+	})
 })
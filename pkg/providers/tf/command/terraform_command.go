@@ -77,6 +77,40 @@ func (cmd plan) Env() []string {
 	return []string{}
 }
 
+// NewPlanToFile writes the plan to a file, so that it can be read with NewShowPlanJSON
+func NewPlanToFile(path string) TerraformCommand {
+	return planToFile{path: path}
+}
+
+type planToFile struct {
+	path string
+}
+
+func (cmd planToFile) Command() []string {
+	return []string{"plan", "-no-color", "-out=" + cmd.path}
+}
+
+func (cmd planToFile) Env() []string {
+	return []string{}
+}
+
+// NewShowPlanJSON shows a plan file in the machine-readable JSON format
+func NewShowPlanJSON(path string) TerraformCommand {
+	return showPlanJSON{path: path}
+}
+
+type showPlanJSON struct {
+	path string
+}
+
+func (cmd showPlanJSON) Command() []string {
+	return []string{"show", "-json", cmd.path}
+}
+
+func (cmd showPlanJSON) Env() []string {
+	return []string{}
+}
+
 func NewImport(addr, id string) TerraformCommand {
 	return importCmd{Addr: addr, ID: id}
 }
@@ -45,5 +45,15 @@ var _ = Context("Terraform Commands", func() {
 			Expect(plan.Command()).To(Equal([]string{"plan", "-no-color"}))
 			Expect(plan.Env()).To(BeEmpty())
 		})
+
+		It("can write the plan to a file and show it as JSON", func() {
+			plan := command.NewPlanToFile("changes.tfplan")
+			Expect(plan.Command()).To(Equal([]string{"plan", "-no-color", "-out=changes.tfplan"}))
+			Expect(plan.Env()).To(BeEmpty())
+
+			show := command.NewShowPlanJSON("changes.tfplan")
+			Expect(show.Command()).To(Equal([]string{"show", "-json", "changes.tfplan"}))
+			Expect(show.Env()).To(BeEmpty())
+		})
 	})
 })
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
+	"sort"
 	"strings"
 
 	"code.cloudfoundry.org/brokerapi/v13/domain"
@@ -159,6 +161,10 @@ type TfServiceDefinitionV1 struct {
 	PlanUpdateable      bool                        `yaml:"plan_updateable"`
 
 	RequiredEnvVars []string
+
+	// config is where the required environment variables are read from, and is passed on to the
+	// service definition. The global Viper is used when nil.
+	config *viper.Viper
 }
 
 var _ validation.Validatable = (*TfServiceDefinitionV1)(nil)
@@ -200,14 +206,28 @@ func (tfb *TfServiceDefinitionV1) Validate() (errs *validation.FieldError) {
 	return errs
 }
 
+// UseConfig sets the configuration that the service is bound to, so that a reloaded
+// configuration only takes effect along with the services loaded from it
+func (tfb *TfServiceDefinitionV1) UseConfig(v *viper.Viper) {
+	tfb.config = v
+}
+
+func (tfb *TfServiceDefinitionV1) settings() *viper.Viper {
+	if tfb.config == nil {
+		return viper.GetViper()
+	}
+	return tfb.config
+}
+
 func (tfb *TfServiceDefinitionV1) resolveEnvVars() (map[string]string, error) {
+	settings := tfb.settings()
 	vars := make(map[string]string)
 	for _, v := range tfb.RequiredEnvVars {
-		_ = viper.BindEnv(v, v)
-		if !viper.IsSet(v) {
+		_ = settings.BindEnv(v, v)
+		if !settings.IsSet(v) {
 			return vars, fmt.Errorf("missing required env var %s", v)
 		}
-		vars[v] = viper.GetString(v)
+		vars[v] = settings.GetString(v)
 	}
 	return vars, nil
 }
@@ -265,7 +285,7 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 	})
 
 	constDefn := *tfb
-	return &broker.ServiceDefinition{
+	defn := &broker.ServiceDefinition{
 		ID:                  tfb.ID,
 		Name:                tfb.Name,
 		Description:         tfb.Description,
@@ -295,7 +315,9 @@ func (tfb *TfServiceDefinitionV1) ToService(tfBinContext executor.TFBinariesCont
 			executorFactory := executor.NewExecutorFactory(tfBinContext.Dir, tfBinContext.Params, envVars)
 			return NewTerraformProvider(tfBinContext, invoker.NewTerraformInvokerFactory(executorFactory, tfBinContext.Dir, tfBinContext.ProviderReplacements), logger, constDefn, NewDeploymentManager(store, logger))
 		},
-	}, nil
+	}
+	defn.UseConfig(tfb.config)
+	return defn, nil
 }
 
 // TfServiceDefinitionV1Plan represents a service plan in a human-friendly format
@@ -454,6 +476,30 @@ func (action *TfServiceDefinitionV1Action) ValidateTemplateIO() (errs *validatio
 	)
 }
 
+// ResourceTagWarnings lists the provision and bind templates that do not consume the
+// well-known labels variable, so will not apply the operator-configured resource tags.
+func (tfb *TfServiceDefinitionV1) ResourceTagWarnings() ([]string, error) {
+	var warnings []string
+	for name, action := range map[string]TfServiceDefinitionV1Action{"provision": tfb.ProvisionSettings, "bind": tfb.BindSettings} {
+		if action.Template == "" && len(action.Templates) == 0 {
+			continue
+		}
+
+		tfModule := workspace.ModuleDefinition{Definition: action.Template, Definitions: action.Templates}
+		tfIn, err := tfModule.Inputs()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s template inputs for service %q: %w", name, tfb.Name, err)
+		}
+
+		if !slices.Contains(tfIn, broker.LabelsVariable) {
+			warnings = append(warnings, fmt.Sprintf("service %q %s template does not consume the %q variable, so resource tags will not be applied", tfb.Name, name, broker.LabelsVariable))
+		}
+	}
+
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
 // validateTemplateInputs checks that all the inputs of the Terraform template
 // are defined by the service.
 func (action *TfServiceDefinitionV1Action) validateTemplateInputs() (errs *validation.FieldError) {
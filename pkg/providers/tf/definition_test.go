@@ -143,4 +143,27 @@ var _ = Describe("Definition", func() {
 			})
 		})
 	})
+	Describe("ResourceTagWarnings", func() {
+		It("warns about templates that do not consume the labels variable", func() {
+			definition := tf.TfServiceDefinitionV1{
+				Name: "test-name",
+				ProvisionSettings: tf.TfServiceDefinitionV1Action{
+					Template: `variable labels { type = map(string) }`,
+				},
+				BindSettings: tf.TfServiceDefinitionV1Action{
+					Templates: map[string]string{"main": `variable username { type = string }`},
+				},
+			}
+
+			Expect(definition.ResourceTagWarnings()).To(ConsistOf(
+				`service "test-name" bind template does not consume the "labels" variable, so resource tags will not be applied`,
+			))
+		})
+
+		It("ignores actions without a template", func() {
+			definition := tf.TfServiceDefinitionV1{Name: "test-name"}
+
+			Expect(definition.ResourceTagWarnings()).To(BeEmpty())
+		})
+	})
 })
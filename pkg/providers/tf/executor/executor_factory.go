@@ -42,15 +42,30 @@ type ExecutorBuilder interface {
 
 const binaryName = "tofu"
 
+// BinaryPath is the path of the OpenTofu binary of the given version in a directory of extracted brokerpak binaries
+func BinaryPath(dir string, tfVersion *version.Version) string {
+	return filepath.Join(dir, "versions", tfVersion.String(), binaryName)
+}
+
 func (executorFactory ExecutorFactory) VersionedExecutor(tfVersion *version.Version) TerraformExecutor {
+	return executorFactory.wrap(tfVersion, DefaultExecutor())
+}
+
+// VersionedShellExecutor is like VersionedExecutor, but runs the commands attached to the
+// terminal and opens an interactive shell once Terraform has been initialized
+func (executorFactory ExecutorFactory) VersionedShellExecutor(tfVersion *version.Version, terminal Terminal) TerraformExecutor {
+	return executorFactory.wrap(tfVersion, ShellExecutor(terminal))
+}
+
+func (executorFactory ExecutorFactory) wrap(tfVersion *version.Version, wrapped TerraformExecutor) TerraformExecutor {
 	return CustomEnvironmentExecutor(executorFactory.EnvVars,
 		CustomEnvironmentExecutor(
 			executorFactory.Params,
 			CustomTerraformExecutor(
-				filepath.Join(executorFactory.Dir, "versions", tfVersion.String(), binaryName),
+				BinaryPath(executorFactory.Dir, tfVersion),
 				executorFactory.Dir,
 				tfVersion,
-				DefaultExecutor(),
+				wrapped,
 			),
 		),
 	)
@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Terminal is where an interactive shell reads its input and writes its output
+type Terminal struct {
+	Shell  string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ShellExecutor runs the commands with their output written to the terminal. Once Terraform has been
+// initialized it opens an interactive shell in the workspace directory, with the same
+// environment, and with the Terraform binary on the PATH. The shell exiting with an
+// error is not treated as a failure, as that is usually the last command typed.
+func ShellExecutor(terminal Terminal) TerraformExecutor {
+	return shellExecutor{terminal: terminal}
+}
+
+type shellExecutor struct {
+	terminal Terminal
+}
+
+func (e shellExecutor) Execute(ctx context.Context, c *exec.Cmd) (ExecutionOutput, error) {
+	c.Stdout = e.terminal.Stdout
+	c.Stderr = e.terminal.Stderr
+	if err := c.Run(); err != nil {
+		return ExecutionOutput{}, fmt.Errorf("failed to execute tofu: %w", err)
+	}
+
+	if len(c.Args) < 2 || c.Args[1] != "init" {
+		return ExecutionOutput{}, nil
+	}
+
+	shell := exec.CommandContext(ctx, e.terminal.Shell)
+	shell.Dir = c.Dir
+	shell.Env = append(slices.Clone(c.Env), updatePath(c.Env, filepath.Dir(c.Path)))
+	if pluginDir, ok := pluginDirArg(c.Args); ok {
+		shell.Env = append(shell.Env, "TF_CLI_ARGS_init=-plugin-dir="+pluginDir)
+	}
+	shell.Stdin = e.terminal.Stdin
+	shell.Stdout = e.terminal.Stdout
+	shell.Stderr = e.terminal.Stderr
+
+	var exitErr *exec.ExitError
+	if err := shell.Run(); err != nil && !errors.As(err, &exitErr) {
+		return ExecutionOutput{}, fmt.Errorf("failed to start shell %q: %w", e.terminal.Shell, err)
+	}
+
+	return ExecutionOutput{}, nil
+}
+
+func pluginDirArg(args []string) (string, bool) {
+	for _, arg := range args {
+		if dir, ok := strings.CutPrefix(arg, "-plugin-dir="); ok {
+			return dir, true
+		}
+	}
+	return "", false
+}
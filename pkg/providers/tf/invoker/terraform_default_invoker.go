@@ -55,6 +55,15 @@ func (cmd TerraformDefaultInvoker) Plan(ctx context.Context, workspace workspace
 		command.NewPlan())
 }
 
+// PlanJSON plans the workspace and returns the plan in the JSON format of "tofu show -json"
+func (cmd TerraformDefaultInvoker) PlanJSON(ctx context.Context, workspace workspace.Workspace) (executor.ExecutionOutput, error) {
+	const planFile = "changes.tfplan"
+	return workspace.Execute(ctx, cmd.executor,
+		command.NewInit(cmd.pluginDirectory),
+		command.NewPlanToFile(planFile),
+		command.NewShowPlanJSON(planFile))
+}
+
 func (cmd TerraformDefaultInvoker) Import(ctx context.Context, workspace workspace.Workspace, resources map[string]string) error {
 	commands := []command.TerraformCommand{
 		command.NewInit(cmd.pluginDirectory),
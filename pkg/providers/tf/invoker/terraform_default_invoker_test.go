@@ -101,6 +101,22 @@ var _ = Context("TerraformDefaultInvoker", func() {
 		})
 	})
 
+	Context("PlanJSON", func() {
+		It("initializes the workspace, plans to a file and shows it as JSON", func() {
+			invokerUnderTest.PlanJSON(expectedContext, fakeWorkspace)
+
+			Expect(fakeWorkspace.ExecuteCallCount()).To(Equal(1))
+			actualContext, actualExecutor, actualCommands := fakeWorkspace.ExecuteArgsForCall(0)
+			Expect(actualContext).To(Equal(expectedContext))
+			Expect(actualExecutor).To(Equal(fakeExecutor))
+			Expect(actualCommands).To(Equal([]command.TerraformCommand{
+				command.NewInit(pluginDirectory),
+				command.NewPlanToFile("changes.tfplan"),
+				command.NewShowPlanJSON("changes.tfplan"),
+			}))
+		})
+	})
+
 	Context("Show", func() {
 		Context("has no renames", func() {
 			BeforeEach(func() {
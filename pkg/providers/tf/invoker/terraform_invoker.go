@@ -23,5 +23,6 @@ type TerraformInvoker interface {
 	Apply(ctx context.Context, workspace workspace.Workspace) error
 	Show(ctx context.Context, workspace workspace.Workspace) (string, error)
 	Plan(ctx context.Context, workspace workspace.Workspace) (executor.ExecutionOutput, error)
+	PlanJSON(ctx context.Context, workspace workspace.Workspace) (executor.ExecutionOutput, error)
 	Import(ctx context.Context, workspace workspace.Workspace, resources map[string]string) error
 }
@@ -6,7 +6,13 @@ import (
 )
 
 func (provider *TerraformProvider) CheckOperationConstraints(deploymentID string, operationType string) error {
-	if operationType != models.DeprovisionOperationType {
+	var blockingOperationType string
+	switch operationType {
+	case models.DeprovisionOperationType:
+		blockingOperationType = models.ProvisionOperationType
+	case models.UnbindOperationType:
+		blockingOperationType = models.BindOperationType
+	default:
 		return nil
 	}
 
@@ -14,8 +20,9 @@ func (provider *TerraformProvider) CheckOperationConstraints(deploymentID string
 	switch {
 	case err != nil:
 		return err
-	case deployment.LastOperationType == models.ProvisionOperationType && deployment.LastOperationState == InProgress:
-		// Will not accept a deprovision while a provision is in progress
+	case deployment.LastOperationType == blockingOperationType && deployment.LastOperationState == InProgress:
+		// Will not accept a deprovision while a provision is in progress,
+		// nor an unbind while an asynchronous bind is in progress
 		return apiresponses.ErrConcurrentInstanceAccess
 	default:
 		return nil
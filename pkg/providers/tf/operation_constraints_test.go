@@ -55,7 +55,24 @@ var _ = Describe("CheckOperationConstraints", func() {
 		})
 	})
 
-	When("call from an operation which is not a deprovision", func() {
+	When("a bind operation is in progress", func() {
+		BeforeEach(func() {
+			deployment = storage.TerraformDeployment{
+				ID:                 deploymentID,
+				Workspace:          &workspace.TerraformWorkspace{},
+				LastOperationType:  "bind",
+				LastOperationState: "in progress",
+			}
+			fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+		})
+
+		It("returns a ErrConcurrentInstanceAccess error on unbind", func() {
+			err := provider.CheckOperationConstraints(deploymentID, "unbind")
+			Expect(err).To(MatchError(apiresponses.ErrConcurrentInstanceAccess))
+		})
+	})
+
+	When("call from an operation which is not a deprovision or unbind", func() {
 		DescribeTable(
 			"does not return an error",
 			func(operationType string) {
@@ -19,6 +19,10 @@ func (provider *TerraformProvider) GetTerraformOutputs(_ context.Context, instan
 	return outs, nil
 }
 
+func (provider *TerraformProvider) GetBindingOutputs(_ context.Context, instanceGUID, bindingID string) (storage.JSONObject, error) {
+	return provider.outputs(generateTfID(instanceGUID, bindingID), workspace.DefaultInstanceName)
+}
+
 // Outputs gets the output variables for the given module instance in the workspace.
 func (provider *TerraformProvider) outputs(deploymentID, instanceName string) (map[string]any, error) {
 	deployment, err := provider.GetTerraformDeployment(deploymentID)
@@ -8,3 +8,8 @@ import (
 func (provider *TerraformProvider) PollInstance(_ context.Context, instanceGUID string) (bool, string, string, error) {
 	return provider.OperationStatus(generateTfID(instanceGUID, ""))
 }
+
+// PollBinding returns the binding status of the backing job.
+func (provider *TerraformProvider) PollBinding(_ context.Context, instanceGUID, bindingID string) (bool, string, string, error) {
+	return provider.OperationStatus(generateTfID(instanceGUID, bindingID))
+}
@@ -31,3 +31,24 @@ var _ = Describe("PollInstance", func() {
 		Expect(fakeDeploymentManager.OperationStatusArgsForCall(0)).To(Equal("tf:instance-guid:"))
 	})
 })
+
+var _ = Describe("PollBinding", func() {
+	It("returns gets operation status", func() {
+		fakeDeploymentManager := &tffakes.FakeDeploymentManagerInterface{}
+		fakeInvokerBuilder := &tffakes.FakeTerraformInvokerBuilder{}
+		fakeLogger := utils.NewLogger("test")
+
+		fakeDeploymentManager.OperationStatusReturns(false, "LO message", "bind", nil)
+		provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, tf.TfServiceDefinitionV1{}, fakeDeploymentManager)
+
+		finished, message, operationType, err := provider.PollBinding(context.TODO(), "instance-guid", "binding-guid")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(finished).To(BeFalse())
+		Expect(operationType).To(Equal("bind"))
+		Expect(message).To(Equal("LO message"))
+
+		Expect(fakeDeploymentManager.OperationStatusCallCount()).To(Equal(1))
+		Expect(fakeDeploymentManager.OperationStatusArgsForCall(0)).To(Equal("tf:instance-guid:binding-guid"))
+	})
+})
@@ -0,0 +1,52 @@
+package tf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/command"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+)
+
+// Shell writes the workspace of the deployment to a temporary directory and initializes it with the
+// OpenTofu binary, providers and environment from the brokerpak, the same way an operation would.
+// It then opens an interactive shell in the directory. When the shell exits, the directory is removed
+// and the workspace is returned with the state as it was left. The new state is not stored.
+func (provider *TerraformProvider) Shell(ctx context.Context, deploymentID string, terminal executor.Terminal) (*workspace.TerraformWorkspace, error) {
+	deployment, err := provider.GetTerraformDeployment(deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if deployment.LastOperationState == InProgress {
+		return nil, fmt.Errorf("an operation is in progress for deployment %q", deploymentID)
+	}
+
+	envVars, err := provider.serviceDefinition.resolveEnvVars()
+	if err != nil {
+		return nil, err
+	}
+
+	executorFactory := executor.ExecutorFactory{
+		Dir:     provider.tfBinContext.Dir,
+		Params:  provider.tfBinContext.Params,
+		EnvVars: envVars,
+	}
+	shellExecutor := executorFactory.VersionedShellExecutor(provider.tfBinContext.DefaultTfVersion, terminal)
+
+	tfWorkspace := deployment.TFWorkspace()
+	var commands []command.TerraformCommand
+	if tfWorkspace.HasState() {
+		for oldName, newName := range provider.tfBinContext.ProviderReplacements {
+			commands = append(commands, command.NewRenameProvider(oldName, newName))
+		}
+	}
+	commands = append(commands, command.NewInit(provider.tfBinContext.Dir))
+
+	if _, err := tfWorkspace.Execute(ctx, shellExecutor, commands...); err != nil {
+		return nil, err
+	}
+
+	return tfWorkspace, nil
+}
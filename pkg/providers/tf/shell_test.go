@@ -0,0 +1,114 @@
+package tf_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/tffakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+)
+
+var _ = Describe("Shell", func() {
+	const deploymentID = "tf:cc57a89e-8f43-48e8-9e41-c7c99d331066:"
+
+	var (
+		binDir                string
+		deployment            storage.TerraformDeployment
+		fakeDeploymentManager *tffakes.FakeDeploymentManagerInterface
+		stdout                *gbytes.Buffer
+		provider              *tf.TerraformProvider
+	)
+
+	terminal := func(script string) executor.Terminal {
+		return executor.Terminal{Shell: "/bin/sh", Stdin: strings.NewReader(script), Stdout: stdout, Stderr: stdout}
+	}
+
+	BeforeEach(func() {
+		binDir = GinkgoT().TempDir()
+		tofuDir := filepath.Join(binDir, "versions", "1.6.0")
+		Expect(os.MkdirAll(tofuDir, 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tofuDir, "tofu"), []byte("#!/bin/sh\necho \"fake tofu $*\"\n"), 0o755)).To(Succeed())
+
+		deployment = storage.TerraformDeployment{
+			ID: deploymentID,
+			Workspace: &workspace.TerraformWorkspace{
+				Modules:   []workspace.ModuleDefinition{{Name: "brokertemplate", Definitions: map[string]string{"main": `variable "name" { type = string }`}}},
+				Instances: []workspace.ModuleInstance{{ModuleName: "brokertemplate", InstanceName: "instance", Configuration: map[string]any{"name": "foo"}}},
+				State:     []byte(`{"terraform_version":"1.6.0"}`),
+			},
+		}
+
+		fakeDeploymentManager = &tffakes.FakeDeploymentManagerInterface{}
+		fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+		stdout = gbytes.NewBuffer()
+
+		provider = tf.NewTerraformProvider(
+			executor.TFBinariesContext{
+				Dir:                  binDir,
+				DefaultTfVersion:     version.Must(version.NewVersion("1.6.0")),
+				Params:               map[string]string{"PARAM": "param-value"},
+				ProviderReplacements: map[string]string{"old/provider": "new/provider"},
+			},
+			&tffakes.FakeTerraformInvokerBuilder{},
+			utils.NewLogger("test"),
+			tf.TfServiceDefinitionV1{Name: "test-service"},
+			fakeDeploymentManager,
+		)
+	})
+
+	It("initializes the workspace and opens a shell in it", func() {
+		ws, err := provider.Shell(context.TODO(), deploymentID, terminal(`
+cat terraform.tfvars.json
+echo "param=$PARAM"
+echo "init args=$TF_CLI_ARGS_init"
+tofu version
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeDeploymentManager.GetTerraformDeploymentArgsForCall(0)).To(Equal(deploymentID))
+		Expect(stdout).To(gbytes.Say(`fake tofu state replace-provider -auto-approve old/provider new/provider`))
+		Expect(stdout).To(gbytes.Say(`fake tofu init -plugin-dir=` + binDir + ` -no-color`))
+		Expect(stdout).To(gbytes.Say(`"name": "foo"`))
+		Expect(stdout).To(gbytes.Say(`param=param-value`))
+		Expect(stdout).To(gbytes.Say(`init args=-plugin-dir=` + binDir))
+		Expect(stdout).To(gbytes.Say(`fake tofu version`))
+		Expect(ws.State).To(MatchJSON(`{"terraform_version":"1.6.0"}`))
+	})
+
+	It("returns the state as the shell left it", func() {
+		ws, err := provider.Shell(context.TODO(), deploymentID, terminal(`
+echo '{"terraform_version":"1.6.0","serial":2}' > terraform.tfstate
+exit 3
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ws.State).To(MatchJSON(`{"terraform_version":"1.6.0","serial":2}`))
+	})
+
+	It("does not open a shell while an operation is in progress", func() {
+		deployment.LastOperationState = tf.InProgress
+		fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+
+		_, err := provider.Shell(context.TODO(), deploymentID, terminal("exit"))
+		Expect(err).To(MatchError(`an operation is in progress for deployment "` + deploymentID + `"`))
+		Expect(stdout.Contents()).To(BeEmpty())
+	})
+
+	It("fails when the shell cannot be started", func() {
+		t := terminal("exit")
+		t.Shell = filepath.Join(binDir, "no-such-shell")
+
+		_, err := provider.Shell(context.TODO(), deploymentID, t)
+		Expect(err).To(MatchError(ContainSubstring("failed to start shell")))
+	})
+})
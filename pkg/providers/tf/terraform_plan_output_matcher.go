@@ -1,8 +1,10 @@
 package tf
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
 
@@ -24,3 +26,44 @@ func CheckTerraformPlanOutput(logger lager.Logger, output executor.ExecutionOutp
 	}
 	return nil
 }
+
+// terraformPlan is the part of the "tofu show -json" format of a plan that describes the changes to resources
+type terraformPlan struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+	Errored bool `json:"errored"`
+}
+
+// CheckTerraformPlanHasNoChanges only passes when a plan, in the JSON format of "tofu show -json", shows that no
+// resources would be created, updated or deleted. Changes to outputs are allowed, as the outputs of imported
+// resources are only recorded when the plan is applied.
+func CheckTerraformPlanHasNoChanges(logger lager.Logger, output executor.ExecutionOutput) error {
+	var plan terraformPlan
+	if err := json.Unmarshal([]byte(output.StdOut), &plan); err != nil {
+		return fmt.Errorf("error reading tofu plan: %w", err)
+	}
+	if plan.Errored {
+		return fmt.Errorf("tofu plan did not complete - cancelling adopt")
+	}
+
+	var changes []string
+	for _, rc := range plan.ResourceChanges {
+		for _, action := range rc.Change.Actions {
+			if action != "no-op" && action != "read" {
+				changes = append(changes, fmt.Sprintf("%s (%s)", rc.Address, strings.Join(rc.Change.Actions, ", ")))
+				break
+			}
+		}
+	}
+	if len(changes) > 0 {
+		logger.Info("cancelling-adopt", lager.Data{"changes": changes})
+		return fmt.Errorf("tofu plan shows that resources would be changed: %s - the imported resources do not match the provision template", strings.Join(changes, "; "))
+	}
+
+	logger.Info("no-changes")
+	return nil
+}
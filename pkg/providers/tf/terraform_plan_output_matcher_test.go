@@ -1,6 +1,9 @@
 package tf
 
 import (
+	"os"
+	"path/filepath"
+
 	"code.cloudfoundry.org/lager/v3"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
 	. "github.com/onsi/ginkgo/v2"
@@ -77,3 +80,41 @@ Changes to Outputs:
 		Expect(output).To(MatchError("tofu plan shows that resources would be destroyed - cancelling subsume"))
 	})
 })
+
+var _ = Context("CheckTerraformPlanHasNoChanges", func() {
+	planFixture := func(name string) executor.ExecutionOutput {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		Expect(err).NotTo(HaveOccurred())
+		return executor.ExecutionOutput{StdOut: string(data)}
+	}
+
+	It("returns no errors if only outputs are changed", func() {
+		logger := lager.NewLogger("test")
+		output := CheckTerraformPlanHasNoChanges(logger, planFixture("plan-outputs-only.json"))
+		Expect(output).NotTo(HaveOccurred())
+	})
+
+	It("returns no errors if there are no resources", func() {
+		logger := lager.NewLogger("test")
+		output := CheckTerraformPlanHasNoChanges(logger, executor.ExecutionOutput{StdOut: `{"format_version":"1.2","errored":false}`})
+		Expect(output).NotTo(HaveOccurred())
+	})
+
+	It("fails if resources are being changed", func() {
+		logger := lager.NewLogger("test")
+		output := CheckTerraformPlanHasNoChanges(logger, planFixture("plan-replace.json"))
+		Expect(output).To(MatchError("tofu plan shows that resources would be changed: random_string.username (delete, create) - the imported resources do not match the provision template"))
+	})
+
+	It("fails if the plan errored", func() {
+		logger := lager.NewLogger("test")
+		output := CheckTerraformPlanHasNoChanges(logger, executor.ExecutionOutput{StdOut: `{"format_version":"1.2","errored":true}`})
+		Expect(output).To(MatchError("tofu plan did not complete - cancelling adopt"))
+	})
+
+	It("fails if the output is not a JSON plan", func() {
+		logger := lager.NewLogger("test")
+		output := CheckTerraformPlanHasNoChanges(logger, executor.ExecutionOutput{StdOut: "No changes. Your infrastructure matches the configuration."})
+		Expect(output).To(MatchError(ContainSubstring("error reading tofu plan")))
+	})
+})
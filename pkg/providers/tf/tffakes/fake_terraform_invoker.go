@@ -62,6 +62,20 @@ type FakeTerraformInvoker struct {
 		result1 executor.ExecutionOutput
 		result2 error
 	}
+	PlanJSONStub        func(context.Context, workspace.Workspace) (executor.ExecutionOutput, error)
+	planJSONMutex       sync.RWMutex
+	planJSONArgsForCall []struct {
+		arg1 context.Context
+		arg2 workspace.Workspace
+	}
+	planJSONReturns struct {
+		result1 executor.ExecutionOutput
+		result2 error
+	}
+	planJSONReturnsOnCall map[int]struct {
+		result1 executor.ExecutionOutput
+		result2 error
+	}
 	ShowStub        func(context.Context, workspace.Workspace) (string, error)
 	showMutex       sync.RWMutex
 	showArgsForCall []struct {
@@ -332,6 +346,71 @@ func (fake *FakeTerraformInvoker) PlanReturnsOnCall(i int, result1 executor.Exec
 	}{result1, result2}
 }
 
+func (fake *FakeTerraformInvoker) PlanJSON(arg1 context.Context, arg2 workspace.Workspace) (executor.ExecutionOutput, error) {
+	fake.planJSONMutex.Lock()
+	ret, specificReturn := fake.planJSONReturnsOnCall[len(fake.planJSONArgsForCall)]
+	fake.planJSONArgsForCall = append(fake.planJSONArgsForCall, struct {
+		arg1 context.Context
+		arg2 workspace.Workspace
+	}{arg1, arg2})
+	stub := fake.PlanJSONStub
+	fakeReturns := fake.planJSONReturns
+	fake.recordInvocation("PlanJSON", []any{arg1, arg2})
+	fake.planJSONMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONCallCount() int {
+	fake.planJSONMutex.RLock()
+	defer fake.planJSONMutex.RUnlock()
+	return len(fake.planJSONArgsForCall)
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONCalls(stub func(context.Context, workspace.Workspace) (executor.ExecutionOutput, error)) {
+	fake.planJSONMutex.Lock()
+	defer fake.planJSONMutex.Unlock()
+	fake.PlanJSONStub = stub
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONArgsForCall(i int) (context.Context, workspace.Workspace) {
+	fake.planJSONMutex.RLock()
+	defer fake.planJSONMutex.RUnlock()
+	argsForCall := fake.planJSONArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONReturns(result1 executor.ExecutionOutput, result2 error) {
+	fake.planJSONMutex.Lock()
+	defer fake.planJSONMutex.Unlock()
+	fake.PlanJSONStub = nil
+	fake.planJSONReturns = struct {
+		result1 executor.ExecutionOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTerraformInvoker) PlanJSONReturnsOnCall(i int, result1 executor.ExecutionOutput, result2 error) {
+	fake.planJSONMutex.Lock()
+	defer fake.planJSONMutex.Unlock()
+	fake.PlanJSONStub = nil
+	if fake.planJSONReturnsOnCall == nil {
+		fake.planJSONReturnsOnCall = make(map[int]struct {
+			result1 executor.ExecutionOutput
+			result2 error
+		})
+	}
+	fake.planJSONReturnsOnCall[i] = struct {
+		result1 executor.ExecutionOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTerraformInvoker) Show(arg1 context.Context, arg2 workspace.Workspace) (string, error) {
 	fake.showMutex.Lock()
 	ret, specificReturn := fake.showReturnsOnCall[len(fake.showArgsForCall)]
@@ -400,16 +479,6 @@ func (fake *FakeTerraformInvoker) ShowReturnsOnCall(i int, result1 string, resul
 func (fake *FakeTerraformInvoker) Invocations() map[string][][]any {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.applyMutex.RLock()
-	defer fake.applyMutex.RUnlock()
-	fake.destroyMutex.RLock()
-	defer fake.destroyMutex.RUnlock()
-	fake.importMutex.RLock()
-	defer fake.importMutex.RUnlock()
-	fake.planMutex.RLock()
-	defer fake.planMutex.RUnlock()
-	fake.showMutex.RLock()
-	defer fake.showMutex.RUnlock()
 	copiedInvocations := map[string][][]any{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -19,15 +19,32 @@ func (provider *TerraformProvider) Unbind(ctx context.Context, instanceGUID, bin
 		"tfId":     tfID,
 	})
 
-	if err := provider.UpdateWorkspaceHCL(tfID, provider.serviceDefinition.BindSettings, vc.ToMap()); err != nil {
+	if err := provider.startUnbind(ctx, tfID, vc); err != nil {
 		return err
 	}
 
-	if err := provider.destroy(ctx, tfID, vc.ToMap(), models.UnbindOperationType); err != nil {
+	return provider.Wait(ctx, tfID)
+}
+
+// UnbindAsync starts a terraform destroy on the binding without waiting on the result.
+// Progress can be followed with PollBinding.
+func (provider *TerraformProvider) UnbindAsync(ctx context.Context, instanceGUID, bindingID string, vc *varcontext.VarContext) error {
+	tfID := generateTfID(instanceGUID, bindingID)
+	provider.logger.Debug("terraform-unbind-async", correlation.ID(ctx), lager.Data{
+		"instance": instanceGUID,
+		"binding":  bindingID,
+		"tfId":     tfID,
+	})
+
+	return provider.startUnbind(ctx, tfID, vc)
+}
+
+func (provider *TerraformProvider) startUnbind(ctx context.Context, tfID string, vc *varcontext.VarContext) error {
+	if err := provider.UpdateWorkspaceHCL(tfID, provider.serviceDefinition.BindSettings, vc.ToMap()); err != nil {
 		return err
 	}
 
-	return provider.Wait(ctx, tfID)
+	return provider.destroy(ctx, tfID, vc.ToMap(), models.UnbindOperationType)
 }
 
 // DeleteBindingData deletes a terraform deployment from the database
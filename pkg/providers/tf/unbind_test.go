@@ -149,6 +149,42 @@ var _ = Describe("Unbind", func() {
 		Expect(err).To(MatchError(expectedError))
 	})
 
+	Describe("UnbindAsync", func() {
+		It("starts the destroy without waiting for it to complete", func() {
+			fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+			fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{DefaultTfVersion: version.Must(version.NewVersion("1"))}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			err := provider.UnbindAsync(context.TODO(), instanceGUID, bindingGUID, unbindContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the HCL was updated")
+			actualTFID, _, _ := fakeDeploymentManager.UpdateWorkspaceHCLArgsForCall(0)
+			Expect(actualTFID).To(Equal(expectedTFID))
+
+			By("Checking that unbind is marked as started")
+			Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
+			_, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+			Expect(actualOperationType).To(Equal("unbind"))
+
+			By("checking the result is not waited on")
+			Expect(fakeDeploymentManager.OperationStatusCallCount()).To(BeZero())
+
+			By("checking TF destroy has been called")
+			Eventually(destroyCallCount(fakeDefaultInvoker)).Should(Equal(1))
+		})
+
+		It("fails, when unable to update the workspace HCL", func() {
+			fakeDeploymentManager.UpdateWorkspaceHCLReturns(errors.New(expectedError))
+
+			provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+			err := provider.UnbindAsync(context.TODO(), instanceGUID, bindingGUID, unbindContext)
+			Expect(err).To(MatchError(expectedError))
+		})
+	})
+
 	Describe("DeleteBindingData", func() {
 		var provider *tf.TerraformProvider
 		BeforeEach(func() {
@@ -0,0 +1,52 @@
+package tf
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager/v3"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils/correlation"
+)
+
+// UpdateBinding re-applies an existing binding workspace with new configuration, waiting on the result.
+func (provider *TerraformProvider) UpdateBinding(ctx context.Context, bindContext *varcontext.VarContext) (map[string]any, error) {
+	provider.logger.Debug("terraform-update-binding", correlation.ID(ctx), lager.Data{
+		"context": bindContext.ToMap(),
+	})
+
+	tfID := bindContext.GetString("tf_id")
+	if err := bindContext.Error(); err != nil {
+		return nil, err
+	}
+
+	if err := provider.UpdateWorkspaceHCL(tfID, provider.serviceDefinition.BindSettings, bindContext.ToMap()); err != nil {
+		return nil, err
+	}
+
+	deployment, err := provider.GetTerraformDeployment(tfID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := provider.MarkOperationStarted(&deployment, models.UpdateBindingOperationType); err != nil {
+		return nil, fmt.Errorf("error marking job started: %w", err)
+	}
+
+	go func() {
+		err := deployment.Workspace.UpdateInstanceConfiguration(bindContext.ToMap())
+		if err == nil {
+			err = provider.DefaultInvoker().Apply(ctx, deployment.Workspace)
+		}
+		_ = provider.MarkOperationFinished(&deployment, err)
+	}()
+
+	if err := provider.Wait(ctx, tfID); err != nil {
+		return nil, fmt.Errorf("error waiting for result: %w", err)
+	}
+
+	return provider.outputs(tfID, workspace.DefaultInstanceName)
+}
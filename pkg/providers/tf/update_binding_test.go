@@ -0,0 +1,123 @@
+package tf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/dbservice/models"
+	"github.com/cloudfoundry/cloud-service-broker/v2/internal/storage"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/executor"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/tffakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/providers/tf/workspace/workspacefakes"
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext"
+	"github.com/cloudfoundry/cloud-service-broker/v2/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateBinding", func() {
+	const expectedTfID = "tf:instance-id:binding-id"
+
+	var (
+		fakeDeploymentManager *tffakes.FakeDeploymentManagerInterface
+		fakeWorkspace         *workspacefakes.FakeWorkspace
+		deployment            storage.TerraformDeployment
+		fakeInvokerBuilder    *tffakes.FakeTerraformInvokerBuilder
+		fakeDefaultInvoker    *tffakes.FakeTerraformInvoker
+		fakeLogger            = utils.NewLogger("test")
+		fakeServiceDefinition tf.TfServiceDefinitionV1
+		bindContext           *varcontext.VarContext
+		templateVars          = map[string]any{"tf_id": expectedTfID, "username": "other-user"}
+	)
+
+	BeforeEach(func() {
+		fakeDeploymentManager = &tffakes.FakeDeploymentManagerInterface{}
+		fakeWorkspace = &workspacefakes.FakeWorkspace{}
+		fakeWorkspace.ModuleInstancesReturns([]workspace.ModuleInstance{{ModuleName: "moduleName"}})
+		fakeInvokerBuilder = &tffakes.FakeTerraformInvokerBuilder{}
+		fakeDefaultInvoker = &tffakes.FakeTerraformInvoker{}
+		fakeInvokerBuilder.VersionedTerraformInvokerReturns(fakeDefaultInvoker)
+
+		fakeServiceDefinition = tf.TfServiceDefinitionV1{
+			BindSettings: tf.TfServiceDefinitionV1Action{
+				Template: `variable username { type = string }`,
+			},
+		}
+
+		deployment = storage.TerraformDeployment{
+			ID:        expectedTfID,
+			Workspace: fakeWorkspace,
+		}
+		fakeDeploymentManager.GetTerraformDeploymentReturns(deployment, nil)
+
+		var err error
+		bindContext, err = varcontext.Builder().MergeMap(templateVars).Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("re-applies the binding workspace and returns the outputs", func() {
+		fakeDeploymentManager.OperationStatusReturns(true, "update binding succeeded", models.UpdateBindingOperationType, nil)
+		fakeWorkspace.OutputsReturns(map[string]any{"username": "other-user"}, nil)
+
+		provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+		actual, err := provider.UpdateBinding(context.TODO(), bindContext)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual).To(Equal(map[string]any{"username": "other-user"}))
+
+		By("checking the workspace HCL was updated from the bind template")
+		Expect(fakeDeploymentManager.UpdateWorkspaceHCLCallCount()).To(Equal(1))
+		actualTfID, actualAction, actualVars := fakeDeploymentManager.UpdateWorkspaceHCLArgsForCall(0)
+		Expect(actualTfID).To(Equal(expectedTfID))
+		Expect(actualAction).To(Equal(fakeServiceDefinition.BindSettings))
+		Expect(actualVars).To(Equal(templateVars))
+
+		By("checking the operation was marked as started")
+		Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(Equal(1))
+		_, actualOperationType := fakeDeploymentManager.MarkOperationStartedArgsForCall(0)
+		Expect(actualOperationType).To(Equal(models.UpdateBindingOperationType))
+
+		By("checking the configuration was updated and applied")
+		Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(BeNil())
+		Expect(fakeWorkspace.UpdateInstanceConfigurationArgsForCall(0)).To(Equal(templateVars))
+		Expect(fakeDefaultInvoker.ApplyCallCount()).To(Equal(1))
+	})
+
+	It("fails, when tfID is not provided", func() {
+		var err error
+		bindContext, err = varcontext.Builder().Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+		_, err = provider.UpdateBinding(context.TODO(), bindContext)
+		Expect(err).To(MatchError(ContainSubstring(`missing value for key "tf_id"`)))
+	})
+
+	It("fails, when the workspace HCL cannot be updated", func() {
+		fakeDeploymentManager.UpdateWorkspaceHCLReturns(errors.New("cannot update"))
+
+		provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+		_, err := provider.UpdateBinding(context.TODO(), bindContext)
+		Expect(err).To(MatchError("cannot update"))
+		Expect(fakeDeploymentManager.MarkOperationStartedCallCount()).To(BeZero())
+	})
+
+	It("returns the error in last operation, if tofu apply fails", func() {
+		fakeDeploymentManager.OperationStatusReturns(true, "update binding failed", models.UpdateBindingOperationType, fmt.Errorf("tofu apply failed"))
+		fakeDefaultInvoker.ApplyReturns(errors.New("some TF issue happened"))
+
+		provider := tf.NewTerraformProvider(executor.TFBinariesContext{}, fakeInvokerBuilder, fakeLogger, fakeServiceDefinition, fakeDeploymentManager)
+
+		_, err := provider.UpdateBinding(context.TODO(), bindContext)
+		Expect(err).To(MatchError("error waiting for result: tofu apply failed"))
+
+		Eventually(operationWasFinishedForDeployment(fakeDeploymentManager)).Should(Equal(deployment))
+		Expect(operationWasFinishedWithError(fakeDeploymentManager)()).To(MatchError("some TF issue happened"))
+	})
+})
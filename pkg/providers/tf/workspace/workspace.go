@@ -283,6 +283,23 @@ func (workspace *TerraformWorkspace) initializeFsWithoutTerraformInit() error {
 		return err
 	}
 
+	return workspace.writeFiles()
+}
+
+// Export writes the modules, variables and state of the workspace to dir, laid out in the same way as when
+// Terraform is executed, so that the directory can be used as a standalone Terraform project.
+func (workspace *TerraformWorkspace) Export(dir string) error {
+	workspace.dirLock.Lock()
+	defer workspace.dirLock.Unlock()
+
+	workspace.dir = dir
+	defer func() { workspace.dir = "" }()
+
+	return workspace.writeFiles()
+}
+
+// writeFiles writes the modules, variables and state of the workspace to the workspace directory
+func (workspace *TerraformWorkspace) writeFiles() error {
 	var err error
 
 	terraformLen := 0
@@ -254,6 +254,37 @@ func TestTerrafromWorkspace_Execute(t *testing.T) {
 	})
 }
 
+func TestTerraformWorkspace_Export(t *testing.T) {
+	ws, err := NewWorkspace(map[string]any{"name": "foo"}, "", map[string]string{"main": `variable "name" { type = string }`}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.State = []byte(`{"version":4}`)
+
+	dir := t.TempDir()
+	if err := ws.Export(dir); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for name, expected := range map[string]string{
+		"main.tf":               `variable "name" { type = string }`,
+		"terraform.tfvars.json": "{\n  \"name\": \"foo\"\n}",
+		"terraform.tfstate":     `{"version":4}`,
+	} {
+		contents, err := os.ReadFile(path.Join(dir, name))
+		if err != nil {
+			t.Fatalf("couldn't read %s: %s", name, err)
+		}
+		if string(contents) != expected {
+			t.Errorf("expected %s to be %q, got %q", name, expected, contents)
+		}
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("expected the directory to be kept: %s", err)
+	}
+}
+
 func TestCustomEnvironmentExecutor(t *testing.T) {
 	c := exec.Command("/path/to/terraform", "apply")
 	c.Env = []string{"ORIGINAL=value"}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"code.cloudfoundry.org/lager/v3"
+)
+
+type BindingUpdater interface {
+	UpdateBinding(ctx context.Context, instanceID, bindingID string, details domain.BindDetails) (domain.Binding, error)
+}
+
+// NewBindingUpdateHandler serves binding updates, which are not part of the OSB API and
+// therefore not routed by brokerapi.
+func NewBindingUpdateHandler(updater BindingUpdater, logger lager.Logger) http.HandlerFunc {
+	logger = logger.Session("update-binding")
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		instanceID := req.PathValue("instance_id")
+		bindingID := req.PathValue("binding_id")
+
+		var details domain.BindDetails
+		if err := json.NewDecoder(req.Body).Decode(&details); err != nil {
+			respondJSON(w, http.StatusUnprocessableEntity, apiresponses.ErrorResponse{Description: err.Error()})
+			return
+		}
+
+		binding, err := updater.UpdateBinding(req.Context(), instanceID, bindingID, details)
+		var failure *apiresponses.FailureResponse
+		switch {
+		case errors.As(err, &failure):
+			logger.Error(failure.LoggerAction(), err)
+			respondJSON(w, failure.ValidatedStatusCode(slog.New(lager.NewHandler(logger))), failure.ErrorResponse())
+		case err != nil:
+			logger.Error("unknown-error", err)
+			respondJSON(w, http.StatusInternalServerError, apiresponses.ErrorResponse{Description: err.Error()})
+		default:
+			respondJSON(w, http.StatusOK, apiresponses.BindingResponse{Credentials: binding.Credentials})
+		}
+	}
+}
+
+func respondJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain"
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"code.cloudfoundry.org/lager/v3/lagertest"
+)
+
+func TestNewBindingUpdateHandler(t *testing.T) {
+	cases := map[string]struct {
+		Body           string
+		Binding        domain.Binding
+		Err            error
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		"success": {
+			Body:           `{"service_id":"svc","plan_id":"plan","parameters":{"role":"admin"}}`,
+			Binding:        domain.Binding{Credentials: map[string]any{"user": "fake"}},
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   `{"credentials":{"user":"fake"}}`,
+		},
+		"invalid body": {
+			Body:           `not-json`,
+			ExpectedStatus: http.StatusUnprocessableEntity,
+		},
+		"failure response": {
+			Body:           `{}`,
+			Err:            apiresponses.ErrBindingDoesNotExist,
+			ExpectedStatus: http.StatusGone,
+		},
+		"unknown error": {
+			Body:           `{}`,
+			Err:            errors.New("boom"),
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedBody:   `{"description":"boom"}`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			updater := &fakeBindingUpdater{binding: tc.Binding, err: tc.Err}
+
+			router := http.NewServeMux()
+			router.Handle("PATCH /v2/service_instances/{instance_id}/service_bindings/{binding_id}", NewBindingUpdateHandler(updater, lagertest.NewTestLogger("test")))
+
+			request := httptest.NewRequest(http.MethodPatch, "/v2/service_instances/fake-instance/service_bindings/fake-binding", strings.NewReader(tc.Body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			if w.Code != tc.ExpectedStatus {
+				t.Errorf("Expected response code: %d got: %d", tc.ExpectedStatus, w.Code)
+			}
+			if tc.ExpectedBody != "" && strings.TrimSpace(w.Body.String()) != tc.ExpectedBody {
+				t.Errorf("Expected body: %s got: %s", tc.ExpectedBody, w.Body.String())
+			}
+			if tc.ExpectedStatus != http.StatusUnprocessableEntity && (updater.instanceID != "fake-instance" || updater.bindingID != "fake-binding") {
+				t.Errorf("Unexpected IDs: %q, %q", updater.instanceID, updater.bindingID)
+			}
+		})
+	}
+}
+
+type fakeBindingUpdater struct {
+	binding    domain.Binding
+	err        error
+	instanceID string
+	bindingID  string
+}
+
+func (f *fakeBindingUpdater) UpdateBinding(_ context.Context, instanceID, bindingID string, _ domain.BindDetails) (domain.Binding, error) {
+	f.instanceID = instanceID
+	f.bindingID = bindingID
+	return f.binding, f.err
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/brokerapi/v13/domain/apiresponses"
+	"code.cloudfoundry.org/lager/v3"
+)
+
+type BrokerpakReloader interface {
+	ReloadBrokerpaks() ([]string, error)
+}
+
+type brokerpakReloadResponse struct {
+	Services []string `json:"services"`
+}
+
+// NewBrokerpakReloadHandler reloads the brokerpaks on request, responding with the names of the services
+// now in the catalog. If the reload fails then the current catalog continues to be served.
+func NewBrokerpakReloadHandler(reloader BrokerpakReloader, logger lager.Logger) http.HandlerFunc {
+	logger = logger.Session("reload-brokerpaks")
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		services, err := reloader.ReloadBrokerpaks()
+		if err != nil {
+			logger.Error("failed", err)
+			respondJSON(w, http.StatusInternalServerError, apiresponses.ErrorResponse{Description: err.Error()})
+			return
+		}
+
+		logger.Info("reloaded", lager.Data{"services": services})
+		respondJSON(w, http.StatusOK, brokerpakReloadResponse{Services: services})
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.cloudfoundry.org/lager/v3/lagertest"
+)
+
+func TestNewBrokerpakReloadHandler(t *testing.T) {
+	cases := map[string]struct {
+		Services       []string
+		Err            error
+		ExpectedStatus int
+		ExpectedBody   string
+	}{
+		"success": {
+			Services:       []string{"first-service", "second-service"},
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   `{"services":["first-service","second-service"]}`,
+		},
+		"failure": {
+			Err:            errors.New("error loading brokerpaks: boom"),
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedBody:   `{"description":"error loading brokerpaks: boom"}`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			reloader := &fakeBrokerpakReloader{services: tc.Services, err: tc.Err}
+
+			request := httptest.NewRequest(http.MethodPost, "/brokerpaks/reload", nil)
+			w := httptest.NewRecorder()
+			NewBrokerpakReloadHandler(reloader, lagertest.NewTestLogger("test")).ServeHTTP(w, request)
+
+			if w.Code != tc.ExpectedStatus {
+				t.Errorf("Expected response code: %d got: %d", tc.ExpectedStatus, w.Code)
+			}
+			if strings.TrimSpace(w.Body.String()) != tc.ExpectedBody {
+				t.Errorf("Expected body: %s got: %s", tc.ExpectedBody, w.Body.String())
+			}
+			if reloader.calls != 1 {
+				t.Errorf("Expected one reload, got %d", reloader.calls)
+			}
+		})
+	}
+}
+
+type fakeBrokerpakReloader struct {
+	services []string
+	err      error
+	calls    int
+}
+
+func (f *fakeBrokerpakReloader) ReloadBrokerpaks() ([]string, error) {
+	f.calls++
+	return f.services, f.err
+}
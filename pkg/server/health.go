@@ -15,24 +15,50 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 	"time"
 
 	"github.com/heptiolabs/healthcheck"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// ReadinessCheck must pass for the broker to be reported as ready. The context passed
+// to the check is cancelled once the timeout has elapsed.
+type ReadinessCheck struct {
+	Name    string
+	Check   func(ctx context.Context) error
+	Timeout time.Duration
+}
+
 // AddHealthHandler creates a new handler for health and liveness checks and
-// adds it to the /live and /ready endpoints.
-func AddHealthHandler(router *http.ServeMux, db *sql.DB) healthcheck.Handler {
-	health := healthcheck.NewHandler()
+// adds it to the /live and /ready endpoints. The status of each check is
+// also reported as a gauge on the /metrics endpoint.
+func AddHealthHandler(router *http.ServeMux, db *sql.DB, checks ...ReadinessCheck) healthcheck.Handler {
+	registry := prometheus.NewRegistry()
+	health := healthcheck.NewMetricsHandler(registry, "csb")
 
 	if db != nil {
 		health.AddReadinessCheck("database", healthcheck.DatabasePingCheck(db, 2*time.Second))
 	}
 
+	for _, c := range checks {
+		health.AddReadinessCheck(c.Name, withTimeout(c.Check, c.Timeout))
+	}
+
 	router.HandleFunc("/live", health.LiveEndpoint)
 	router.HandleFunc("/ready", health.ReadyEndpoint)
+	router.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
 	return health
 }
+
+func withTimeout(check func(ctx context.Context) error, timeout time.Duration) healthcheck.Check {
+	return healthcheck.Timeout(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return check(ctx)
+	}, timeout)
+}
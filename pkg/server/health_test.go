@@ -15,12 +15,15 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -114,3 +117,43 @@ func TestNewHealthHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthHandlerReadinessChecks(t *testing.T) {
+	router := http.NewServeMux()
+	AddHealthHandler(router, nil,
+		ReadinessCheck{Name: "fast", Timeout: time.Second, Check: func(context.Context) error { return nil }},
+		ReadinessCheck{Name: "slow", Timeout: time.Millisecond, Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}},
+	)
+
+	request := httptest.NewRequest(http.MethodGet, "/ready?full=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected response code: %v got: %v", http.StatusServiceUnavailable, w.Code)
+	}
+
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, w.Body.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"fast":"OK","slow":"timed out after 1ms"}`; compacted.String() != expected {
+		t.Fatalf("Expected response: %v got: %v", expected, compacted.String())
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	for _, expected := range []string{
+		`csb_healthcheck_status{check="fast"} 0`,
+		`csb_healthcheck_status{check="slow"} 1`,
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("Expected metrics to contain %q, got: %v", expected, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+// probeSize is the size of the file written to check that a directory is writable. It is
+// larger than an empty file so that a full disk is detected. It is also the least free space
+// that a FreeSpaceCheck accepts.
+const probeSize = 4096
+
+// TofuBinariesCheck checks that the OpenTofu binaries of the loaded brokerpaks exist and are executable.
+// The brokerpaks are read for each check, as they can be reloaded.
+func TofuBinariesCheck(brokerpaks func() []broker.Brokerpak, timeout time.Duration) ReadinessCheck {
+	return ReadinessCheck{
+		Name:    "tofu-binaries",
+		Timeout: timeout,
+		Check: func(context.Context) error {
+			var errs []error
+			for _, pak := range brokerpaks() {
+				for _, path := range pak.TofuBinaries {
+					info, err := os.Stat(path)
+					switch {
+					case err != nil:
+						errs = append(errs, fmt.Errorf("brokerpak %q: %w", pak.Name, err))
+					case info.Mode().Perm()&0o111 == 0:
+						errs = append(errs, fmt.Errorf("brokerpak %q: %s is not executable", pak.Name, path))
+					}
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// PluginDirsCheck checks that the directories the loaded brokerpaks were extracted to are writable
+func PluginDirsCheck(brokerpaks func() []broker.Brokerpak, timeout time.Duration) ReadinessCheck {
+	return ReadinessCheck{
+		Name:    "plugin-dirs",
+		Timeout: timeout,
+		Check: func(context.Context) error {
+			var errs []error
+			for _, pak := range brokerpaks() {
+				if pak.PluginDir == "" {
+					continue
+				}
+				if err := probeDir(pak.PluginDir); err != nil {
+					errs = append(errs, fmt.Errorf("brokerpak %q: %w", pak.Name, err))
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// FreeSpaceCheck checks that the directory is writable and that its filesystem has space and inodes free,
+// without writing to it. It is for directories where any new file has a meaning, such as the lock file directory.
+// The free space is only checked on Unix systems.
+func FreeSpaceCheck(name, dir string, timeout time.Duration) ReadinessCheck {
+	return ReadinessCheck{
+		Name:    name,
+		Timeout: timeout,
+		Check: func(context.Context) error {
+			return checkFreeSpace(dir)
+		},
+	}
+}
+
+// PingCheck checks that a service that the broker depends on is reachable
+func PingCheck(name string, ping func(ctx context.Context) error, timeout time.Duration) ReadinessCheck {
+	return ReadinessCheck{
+		Name:    name,
+		Timeout: timeout,
+		Check:   ping,
+	}
+}
+
+func probeDir(dir string) error {
+	f, err := os.CreateTemp(dir, ".csb-readiness-probe")
+	if err != nil {
+		return fmt.Errorf("directory %q is not writable: %w", dir, err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := f.Write(make([]byte, probeSize)); err != nil {
+		return fmt.Errorf("cannot write to directory %q: %w", dir, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("cannot write to directory %q: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package server
+
+import (
+	"fmt"
+	"os"
+)
+
+func checkFreeSpace(dir string) error {
+	info, err := os.Stat(dir)
+	switch {
+	case err != nil:
+		return fmt.Errorf("directory %q is not writable: %w", dir, err)
+	case !info.IsDir():
+		return fmt.Errorf("directory %q is not writable: not a directory", dir)
+	}
+	return nil
+}
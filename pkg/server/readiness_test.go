@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+func TestTofuBinariesCheck(t *testing.T) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "tofu-executable")
+	if err := os.WriteFile(executable, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	notExecutable := filepath.Join(dir, "tofu-not-executable")
+	if err := os.WriteFile(notExecutable, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		Binaries      []string
+		ExpectedError string
+	}{
+		"present": {
+			Binaries: []string{executable},
+		},
+		"missing": {
+			Binaries:      []string{executable, filepath.Join(dir, "missing")},
+			ExpectedError: `brokerpak "fake-pak": stat ` + filepath.Join(dir, "missing"),
+		},
+		"not-executable": {
+			Binaries:      []string{notExecutable},
+			ExpectedError: `brokerpak "fake-pak": ` + notExecutable + " is not executable",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			check := TofuBinariesCheck(func() []broker.Brokerpak {
+				return []broker.Brokerpak{{Name: "fake-pak", TofuBinaries: tc.Binaries}}
+			}, time.Second)
+
+			assertCheckError(t, check, tc.ExpectedError)
+		})
+	}
+}
+
+func TestPluginDirsCheck(t *testing.T) {
+	writable := t.TempDir()
+
+	cases := map[string]struct {
+		PluginDir     string
+		ExpectedError string
+	}{
+		"writable": {
+			PluginDir: writable,
+		},
+		"not-loaded-from-brokerpak": {
+			PluginDir: "",
+		},
+		"missing": {
+			PluginDir:     filepath.Join(writable, "missing"),
+			ExpectedError: `brokerpak "fake-pak": directory "` + filepath.Join(writable, "missing") + `" is not writable`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			check := PluginDirsCheck(func() []broker.Brokerpak {
+				return []broker.Brokerpak{{Name: "fake-pak", PluginDir: tc.PluginDir}}
+			}, time.Second)
+
+			assertCheckError(t, check, tc.ExpectedError)
+		})
+	}
+
+	entries, err := os.ReadDir(writable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected the probe file to be removed, got %v", entries)
+	}
+}
+
+func TestFreeSpaceCheck(t *testing.T) {
+	dir := t.TempDir()
+	assertCheckError(t, FreeSpaceCheck("lock-file-dir", dir, time.Second), "")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected nothing to be written to the directory, got %v", entries)
+	}
+
+	assertCheckError(t, FreeSpaceCheck("lock-file-dir", filepath.Join(dir, "missing"), time.Second), "is not writable")
+
+	if err := os.Chmod(dir, 0o500); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0o700)
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	assertCheckError(t, FreeSpaceCheck("lock-file-dir", dir, time.Second), `directory "`+dir+`" is not writable`)
+}
+
+func TestPingCheck(t *testing.T) {
+	check := PingCheck("fake-service", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, time.Millisecond)
+
+	if check.Name != "fake-service" {
+		t.Errorf("Expected name to be: fake-service got %v", check.Name)
+	}
+	if err := withTimeout(check.Check, check.Timeout)(); err == nil {
+		t.Error("Expected the check to time out")
+	}
+}
+
+func assertCheckError(t *testing.T, check ReadinessCheck, expected string) {
+	t.Helper()
+
+	err := check.Check(context.Background())
+	switch {
+	case expected == "" && err != nil:
+		t.Errorf("Expected no error, got %v", err)
+	case expected != "" && err == nil:
+		t.Errorf("Expected error containing %q, got none", expected)
+	case expected != "" && !strings.Contains(err.Error(), expected):
+		t.Errorf("Expected error containing %q, got %v", expected, err)
+	}
+}
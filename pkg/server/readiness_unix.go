@@ -0,0 +1,27 @@
+//go:build unix
+
+package server
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func checkFreeSpace(dir string) error {
+	if err := unix.Access(dir, unix.W_OK); err != nil {
+		return fmt.Errorf("directory %q is not writable: %w", dir, err)
+	}
+
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return fmt.Errorf("cannot read the filesystem of directory %q: %w", dir, err)
+	}
+	switch {
+	case uint64(stat.Bavail)*uint64(stat.Bsize) < probeSize:
+		return fmt.Errorf("no space left for directory %q", dir)
+	case stat.Ffree == 0:
+		return fmt.Errorf("no inodes left for directory %q", dir)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+// NewSBOMHandler serves the software bills of materials of the brokerpaks that the services
+// in the registry were loaded from, as a JSON array with one entry per brokerpak.
+func NewSBOMHandler(registry broker.BrokerRegistry) http.HandlerFunc {
+	sboms := []json.RawMessage{}
+	for _, svc := range registry.GetAllServices() {
+		if len(svc.SBOM) == 0 || containsSBOM(sboms, svc.SBOM) {
+			continue
+		}
+		sboms = append(sboms, svc.SBOM)
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		respondJSON(w, http.StatusOK, sboms)
+	}
+}
+
+func containsSBOM(sboms []json.RawMessage, sbom json.RawMessage) bool {
+	for _, s := range sboms {
+		if bytes.Equal(s, sbom) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/broker"
+)
+
+func TestNewSBOMHandler(t *testing.T) {
+	registry := broker.BrokerRegistry{
+		"first-service":  &broker.ServiceDefinition{Name: "first-service", SBOM: json.RawMessage(`{"bomFormat":"CycloneDX","metadata":{"component":{"name":"first-pak"}}}`)},
+		"second-service": &broker.ServiceDefinition{Name: "second-service", SBOM: json.RawMessage(`{"bomFormat":"CycloneDX","metadata":{"component":{"name":"first-pak"}}}`)},
+		"third-service":  &broker.ServiceDefinition{Name: "third-service", SBOM: json.RawMessage(`{"bomFormat":"CycloneDX","metadata":{"component":{"name":"second-pak"}}}`)},
+		"no-sbom":        &broker.ServiceDefinition{Name: "no-sbom"},
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/info/sbom", nil)
+	w := httptest.NewRecorder()
+	NewSBOMHandler(registry).ServeHTTP(w, request)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected response code: %d got: %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected application/json content type got: %q", contentType)
+	}
+
+	expected := `[{"bomFormat":"CycloneDX","metadata":{"component":{"name":"first-pak"}}},{"bomFormat":"CycloneDX","metadata":{"component":{"name":"second-pak"}}}]`
+	if strings.TrimSpace(w.Body.String()) != expected {
+		t.Errorf("Expected body: %s got: %s", expected, w.Body.String())
+	}
+}
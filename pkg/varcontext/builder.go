@@ -22,6 +22,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cast"
+	"github.com/spf13/viper"
 
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/validation"
 	"github.com/cloudfoundry/cloud-service-broker/v2/pkg/varcontext/interpolation"
@@ -42,6 +43,7 @@ type ContextBuilder struct {
 	errors    *multierror.Error
 	context   map[string]any
 	constants map[string]any
+	config    *viper.Viper
 }
 
 // Builder creates a new ContextBuilder for constructing VariableContexts.
@@ -61,6 +63,14 @@ func (builder *ContextBuilder) SetEvalConstants(constants map[string]any) *Conte
 	return builder
 }
 
+// SetConfig sets the configuration that the config function reads values from when templates
+// are evaluated. The global Viper is used if it is not set.
+func (builder *ContextBuilder) SetConfig(settings *viper.Viper) *ContextBuilder {
+	builder.config = settings
+
+	return builder
+}
+
 // DefaultVariable holds a value that may or may not be evaluated.
 // If the value is a string then it will be evaluated.
 type DefaultVariable struct {
@@ -110,7 +120,7 @@ func (builder *ContextBuilder) MergeEvalResult(key, template, resultType string)
 	maps.Copy(evaluationContext, builder.context)
 	maps.Copy(evaluationContext, builder.constants)
 
-	result, err := interpolation.Eval(template, evaluationContext)
+	result, err := interpolation.EvalWithConfig(template, evaluationContext, builder.config)
 	if err != nil {
 		builder.errors = multierror.Append(fmt.Errorf("couldn't compute the value for %q, template: %q, %v", key, template, err))
 		return builder
@@ -167,6 +177,50 @@ func (builder *ContextBuilder) MergeMap(data map[string]any) *ContextBuilder {
 	return builder
 }
 
+// MergeLabels merges the labels into the object held by the key, creating it if it does not exist.
+// Labels override any existing values with the same name. If the existing object is a JSON string,
+// for example produced by `json.marshal()`, the result is also a JSON string.
+func (builder *ContextBuilder) MergeLabels(key string, labels map[string]string) *ContextBuilder {
+	merged := make(map[string]any)
+
+	_, isString := builder.context[key].(string)
+	switch v := builder.context[key].(type) {
+	case nil:
+	case string:
+		if err := json.Unmarshal([]byte(v), &merged); err != nil {
+			builder.errors = multierror.Append(builder.errors, fmt.Errorf("couldn't merge labels into %q, value %q is not a JSON object: %w", key, v, err))
+			return builder
+		}
+	case map[string]any:
+		maps.Copy(merged, v)
+	case map[string]string:
+		for k, val := range v {
+			merged[k] = val
+		}
+	default:
+		builder.errors = multierror.Append(builder.errors, fmt.Errorf("couldn't merge labels into %q, value of type %T is not an object", key, v))
+		return builder
+	}
+
+	for k, val := range labels {
+		merged[k] = val
+	}
+
+	if !isString {
+		builder.context[key] = merged
+		return builder
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		builder.errors = multierror.Append(builder.errors, err)
+		return builder
+	}
+	builder.context[key] = string(encoded)
+
+	return builder
+}
+
 // MergeJSONObject converts the raw message to a map[string]any and
 // merges the values into the context. Blank RawMessages are treated like
 // empty objects.
@@ -51,6 +51,32 @@ func TestContextBuilder(t *testing.T) {
 			Expected: map[string]any{"a": "aaa"},
 		},
 
+		// MergeLabels
+		"MergeLabels creates object": {
+			Builder:  Builder().MergeLabels("labels", map[string]string{"a": "a"}),
+			Expected: map[string]any{"labels": map[string]any{"a": "a"}},
+		},
+		"MergeLabels into object": {
+			Builder:  Builder().MergeMap(map[string]any{"labels": map[string]any{"a": "a", "b": "b"}}).MergeLabels("labels", map[string]string{"b": "bbb", "c": "c"}),
+			Expected: map[string]any{"labels": map[string]any{"a": "a", "b": "bbb", "c": "c"}},
+		},
+		"MergeLabels into string map": {
+			Builder:  Builder().MergeMap(map[string]any{"labels": map[string]string{"a": "a"}}).MergeLabels("labels", map[string]string{"c": "c"}),
+			Expected: map[string]any{"labels": map[string]any{"a": "a", "c": "c"}},
+		},
+		"MergeLabels into JSON string": {
+			Builder:  Builder().MergeMap(map[string]any{"labels": `{"a":"a"}`}).MergeLabels("labels", map[string]string{"c": "c"}),
+			Expected: map[string]any{"labels": `{"a":"a","c":"c"}`},
+		},
+		"MergeLabels into invalid JSON string": {
+			Builder:     Builder().MergeMap(map[string]any{"labels": `not-json`}).MergeLabels("labels", map[string]string{"c": "c"}),
+			ErrContains: `couldn't merge labels into "labels", value "not-json" is not a JSON object`,
+		},
+		"MergeLabels into non-object": {
+			Builder:     Builder().MergeMap(map[string]any{"labels": 42}).MergeLabels("labels", map[string]string{"c": "c"}),
+			ErrContains: `couldn't merge labels into "labels", value of type int is not an object`,
+		},
+
 		// MergeDefaultWithEval
 		"MergeDefaultWithEval no defaults": {
 			Builder:  Builder().MergeDefaultWithEval([]DefaultVariable{{Name: "foo"}}),
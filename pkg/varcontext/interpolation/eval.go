@@ -15,11 +15,13 @@
 package interpolation
 
 import (
+	"maps"
 	"reflect"
 	"sync"
 
 	"github.com/hashicorp/hil"
 	"github.com/hashicorp/hil/ast"
+	"github.com/spf13/viper"
 )
 
 // there seems to be a race condition in the hil functions,
@@ -30,6 +32,12 @@ var hilMutex sync.Mutex
 // Eval evaluates the tempate string using hil https://github.com/hashicorp/hil
 // with the given variables that can be accessed form the string.
 func Eval(templateString string, variables map[string]any) (any, error) {
+	return EvalWithConfig(templateString, variables, nil)
+}
+
+// EvalWithConfig is like Eval, but the config function reads values from the given
+// configuration rather than from the global Viper, unless it is nil.
+func EvalWithConfig(templateString string, variables map[string]any, settings *viper.Viper) (any, error) {
 	hilMutex.Lock()
 	defer hilMutex.Unlock()
 
@@ -47,10 +55,16 @@ func Eval(templateString string, variables map[string]any) (any, error) {
 		varMap[vn] = converted
 	}
 
+	funcMap := hilStandardLibrary
+	if settings != nil {
+		funcMap = maps.Clone(hilStandardLibrary)
+		funcMap["config"] = hilFuncConfig(settings)
+	}
+
 	config := &hil.EvalConfig{
 		GlobalScope: &ast.BasicScope{
 			VarMap:  varMap,
-			FuncMap: hilStandardLibrary,
+			FuncMap: funcMap,
 		},
 	}
 
@@ -101,6 +101,30 @@ func TestEval(t *testing.T) {
 	}
 }
 
+func TestEvalWithConfig(t *testing.T) {
+	viper.Set("reload.val", "global")
+	defer viper.Reset()
+
+	settings := viper.New()
+	settings.Set("reload.val", "reloaded")
+
+	result, err := EvalWithConfig(`${config("reload.val")}`, nil, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "reloaded" {
+		t.Errorf("Expected result: 'reloaded', got '%+v'", result)
+	}
+
+	result, err = Eval(`${config("reload.val")}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "global" {
+		t.Errorf("Expected result: 'global', got '%+v'", result)
+	}
+}
+
 func TestHilFuncTimeNano(t *testing.T) {
 	before := time.Now().UnixNano()
 	result, _ := Eval("${time.nano()}", nil)
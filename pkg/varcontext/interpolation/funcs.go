@@ -49,20 +49,24 @@ func createStandardLibrary() map[string]ast.Function {
 		"json.marshal":    hilFuncJSONMarshal(),
 		"map.flatten":     hilFuncMapFlatten(),
 		"env":             hilFuncEnv(),
-		"config":          hilFuncConfig(),
+		"config":          hilFuncConfig(nil),
 	}
 }
 
-// hilFuncConfig looks up a Viper config value
-func hilFuncConfig() ast.Function {
+// hilFuncConfig looks up a Viper config value, in the global Viper if settings is nil
+func hilFuncConfig(settings *viper.Viper) ast.Function {
 	return ast.Function{
 		ArgTypes:   []ast.Type{ast.TypeString},
 		ReturnType: ast.TypeString,
 		Callback: func(args []any) (any, error) {
+			v := settings
+			if v == nil {
+				v = viper.GetViper()
+			}
 
 			key := args[0].(string)
-			if viper.IsSet(key) {
-				val := viper.Get(key)
+			if v.IsSet(key) {
+				val := v.Get(key)
 				// Check If we're handling a nested object
 				if mapVal, ok := val.(map[string]any); ok {
 					bytes, err := json.Marshal(mapVal)